@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// adjacentEvenRange returns the numbers that may be the next (or previous)
+// even number of n: one or two away from n.
+func adjacentEvenRange(n int, next bool) (NumberRange, error) {
+	if next {
+		if n >= math.MaxInt-1 {
+			return NumberRange{}, fmt.Errorf("no even number after %d fits in an int", n)
+		}
+		return NumberRange{Min: n + 1, Max: n + 2}, nil
+	}
+	if n <= math.MinInt+1 {
+		return NumberRange{}, fmt.Errorf("no even number before %d fits in an int", n)
+	}
+	return NumberRange{Min: n - 2, Max: n - 1}, nil
+}
+
+// NextEven asks the model for the smallest even number greater than n. With
+// AskInt, the answer must be n+1 or n+2 and be certified by IsEven through
+// the middleware; otherwise it is asked for again, and after three attempts
+// NextEven fails with an error wrapping ErrAnswerRejected.
+func (ai *IsEvenAiGemini) NextEven(ctx context.Context, n int) (int, error) {
+	return ai.adjacentEven(ctx, n, true)
+}
+
+// PreviousEven is like NextEven for the largest even number less than n,
+// which must be n-2 or n-1.
+func (ai *IsEvenAiGemini) PreviousEven(ctx context.Context, n int) (int, error) {
+	return ai.adjacentEven(ctx, n, false)
+}
+
+// adjacentEven asks for the next or previous even number of n.
+func (ai *IsEvenAiGemini) adjacentEven(ctx context.Context, n int, next bool) (int, error) {
+	r, err := adjacentEvenRange(n, next)
+	if err != nil {
+		return 0, err
+	}
+	predicate, prompt := "previousEven", "What is the largest even number less than %d? Answer with the number only, in digits."
+	if next {
+		predicate, prompt = "nextEven", "What is the smallest even number greater than %d? Answer with the number only, in digits."
+	}
+	return ai.AskInt(ctx, fmt.Sprintf(prompt, n), AskIntOptions{
+		Predicate: predicate,
+		Args:      []int{n},
+		Range:     &r,
+		Validate:  ai.certifier(ctx, true),
+	})
+}
+
+// NextEven returns the smallest even number greater than n.
+func (l *IsEvenAiLocal) NextEven(ctx context.Context, n int) (int, error) {
+	return l.adjacentEven(ctx, n, true)
+}
+
+// PreviousEven returns the largest even number less than n.
+func (l *IsEvenAiLocal) PreviousEven(ctx context.Context, n int) (int, error) {
+	return l.adjacentEven(ctx, n, false)
+}
+
+// adjacentEven computes the next or previous even number of n.
+func (l *IsEvenAiLocal) adjacentEven(ctx context.Context, n int, next bool) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r, err := adjacentEvenRange(n, next)
+	if err != nil {
+		return 0, err
+	}
+	if r.Min&1 == 0 {
+		return r.Min, nil
+	}
+	return r.Max, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_NextEven(t *testing.T) {
+	answers := []string{"10", "9", "8."}
+	asked := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "smallest even number greater than 7"):
+			geminitest.WriteAnswer(w, r, answers[min(asked, len(answers)-1)])
+			asked++
+		case strings.Contains(string(body), "Is 9 an even number?"):
+			geminitest.WriteAnswer(w, r, "false")
+		case strings.Contains(string(body), "Is 8 an even number?"):
+			geminitest.WriteAnswer(w, r, "true")
+		default:
+			t.Errorf("Unexpected request: %s", body)
+		}
+	})
+
+	// 10 is out of range and 9 is not certified.
+	if n, err := ai.NextEven(context.Background(), 7); err != nil || n != 8 {
+		t.Errorf("NextEven(7) = %d, %v; want 8", n, err)
+	}
+	if asked != 3 {
+		t.Errorf("Expected 3 attempts, got %d", asked)
+	}
+	if u := ai.UsageByPredicate()["nextEven"]; u.Requests != 3 {
+		t.Errorf("Expected 3 nextEven requests in usage, got %d", u.Requests)
+	}
+}
+
+func TestIsEvenAiGemini_PreviousEven_Rejected(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "I don't know")
+	})
+	if n, err := ai.PreviousEven(context.Background(), 7); !errors.Is(err, ErrAnswerRejected) || !errors.Is(err, ErrUnparsableAnswer) {
+		t.Errorf("PreviousEven(7) = %d, %v; want ErrAnswerRejected", n, err)
+	}
+	if _, err := ai.NextEven(context.Background(), math.MaxInt); err == nil {
+		t.Error("NextEven(MaxInt) succeeded, want an error")
+	}
+}
+
+func TestIsEvenAiLocal_AdjacentEven(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	ctx := context.Background()
+	for _, tc := range []struct{ n, next, previous int }{
+		{0, 2, -2},
+		{7, 8, 6},
+		{-3, -2, -4},
+		{-4, -2, -6},
+	} {
+		if got, err := l.NextEven(ctx, tc.n); err != nil || got != tc.next {
+			t.Errorf("NextEven(%d) = %d, %v; want %d", tc.n, got, err, tc.next)
+		}
+		if got, err := l.PreviousEven(ctx, tc.n); err != nil || got != tc.previous {
+			t.Errorf("PreviousEven(%d) = %d, %v; want %d", tc.n, got, err, tc.previous)
+		}
+	}
+	if _, err := l.PreviousEven(ctx, math.MinInt); err == nil {
+		t.Error("PreviousEven(MinInt) succeeded, want an error")
+	}
+}
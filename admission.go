@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Priority ranks queries waiting for admission. Interactive queries can be
+// tagged high so that they overtake background jobs tagged low.
+type Priority int
+
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose queries (see IsEvenAiCore.WithContext)
+// have priority p. Queries without a priority are PriorityNormal.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set with WithPriority, clamped to
+// the defined levels.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+	return min(max(p, PriorityLow), PriorityHigh)
+}
+
+// AdmissionControl limits how many queries run at once, so bursts queue up
+// instead of opening hundreds of connections to the provider. Queries beyond
+// the limit wait, higher priorities first (see WithPriority) and in arrival
+// order within a priority. Once the queue is full, a query fails with
+// ErrQueueFull, unless it has a higher priority than the last query queued
+// at the lowest priority, which then fails in its place. Add it to a client
+// with Middleware:
+//
+//	ai.Use(is_even_ai.NewAdmissionControl(16, 256).Middleware())
+//
+// AdmissionControl is safe for concurrent use, and one can be shared by
+// several clients to limit them together.
+type AdmissionControl struct {
+	maxInFlight, maxQueued int
+
+	mu       sync.Mutex
+	inFlight int
+	queues   [PriorityHigh - PriorityLow + 1][]*admissionWaiter // By priority, lowest first
+	queued   int
+}
+
+// admissionWaiter is a queued query. It receives nil on done when admitted,
+// or ErrQueueFull if it was pushed out of the queue.
+type admissionWaiter struct {
+	done chan error
+}
+
+// NewAdmissionControl returns an AdmissionControl admitting up to maxInFlight
+// queries at once (at least one) and queueing up to maxQueued more.
+func NewAdmissionControl(maxInFlight, maxQueued int) *AdmissionControl {
+	return &AdmissionControl{maxInFlight: max(maxInFlight, 1), maxQueued: max(maxQueued, 0)}
+}
+
+// Middleware admits each query before passing it on. Waiting ends early with
+// the context's error if the query's context is done.
+func (a *AdmissionControl) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			if err := a.acquire(ctx); err != nil {
+				return nil, err
+			}
+			defer a.release()
+			return next(ctx, prompt)
+		}
+	}
+}
+
+// InFlight returns the number of admitted queries and of queries waiting.
+func (a *AdmissionControl) InFlight() (running, queued int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.inFlight, a.queued
+}
+
+// acquire waits until a query may run.
+func (a *AdmissionControl) acquire(ctx context.Context) error {
+	prio := PriorityFromContext(ctx)
+	a.mu.Lock()
+	if a.inFlight < a.maxInFlight && a.queued == 0 {
+		a.inFlight++
+		a.mu.Unlock()
+		return nil
+	}
+	if a.queued >= a.maxQueued && !a.evictBelow(prio) {
+		err := fmt.Errorf("%w: %d queries in flight, %d queued", ErrQueueFull, a.inFlight, a.queued)
+		a.mu.Unlock()
+		return err
+	}
+	w := &admissionWaiter{done: make(chan error, 1)}
+	q := &a.queues[prio-PriorityLow]
+	*q = append(*q, w)
+	a.queued++
+	a.mu.Unlock()
+
+	select {
+	case err := <-w.done:
+		return err
+	case <-ctx.Done():
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		for i, other := range *q {
+			if other == w {
+				*q = append((*q)[:i], (*q)[i+1:]...)
+				a.queued--
+				return ctx.Err()
+			}
+		}
+		// Admitted or evicted just as ctx was done; pass an admission on.
+		if err := <-w.done; err == nil {
+			a.releaseLocked()
+		}
+		return ctx.Err()
+	}
+}
+
+// evictBelow fails the most recently queued query of the lowest priority
+// below prio, to make room for a query of priority prio. It reports whether
+// there was such a query. a.mu must be held.
+func (a *AdmissionControl) evictBelow(prio Priority) bool {
+	for i := range a.queues[:prio-PriorityLow] {
+		if q := a.queues[i]; len(q) > 0 {
+			q[len(q)-1].done <- fmt.Errorf("%w: displaced by a query of higher priority", ErrQueueFull)
+			a.queues[i] = q[:len(q)-1]
+			a.queued--
+			return true
+		}
+	}
+	return false
+}
+
+// release ends an admitted query, admitting the next waiting one.
+func (a *AdmissionControl) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.releaseLocked()
+}
+
+// releaseLocked is release with a.mu held.
+func (a *AdmissionControl) releaseLocked() {
+	for i := len(a.queues) - 1; i >= 0; i-- {
+		if q := a.queues[i]; len(q) > 0 {
+			// The slot passes to the waiter, so inFlight stays the same.
+			q[0].done <- nil
+			a.queues[i] = q[1:]
+			a.queued--
+			return
+		}
+	}
+	a.inFlight--
+}
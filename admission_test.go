@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler answers true once unblock is closed, reporting each query
+// on started.
+func blockingHandler(started chan<- int, unblock <-chan struct{}) QueryHandler {
+	return func(ctx context.Context, _ string) (*bool, error) {
+		info, _ := CallInfoFromContext(ctx)
+		started <- info.Args[0]
+		<-unblock
+		yes := true
+		return &yes, nil
+	}
+}
+
+// waitQueued waits until a has n queued queries.
+func waitQueued(t *testing.T, a *AdmissionControl, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if _, queued := a.InFlight(); queued == n {
+			return
+		}
+	}
+	t.Fatalf("Timed out waiting for %d queued queries", n)
+}
+
+func TestAdmissionControl(t *testing.T) {
+	started, unblock := make(chan int, 10), make(chan struct{})
+	admission := NewAdmissionControl(2, 2)
+	core := newIsEvenAiCore(testPromptTemplates, blockingHandler(started, unblock))
+	core.Use(admission.Middleware())
+
+	var wg sync.WaitGroup
+	for n := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := core.IsEven(n); err != nil {
+				t.Errorf("IsEven(%d) failed: %v", n, err)
+			}
+		}()
+		if n < 2 {
+			<-started
+		} else {
+			waitQueued(t, admission, n-1)
+		}
+	}
+	if running, queued := admission.InFlight(); running != 2 || queued != 2 {
+		t.Errorf("InFlight() = %d, %d; want 2, 2", running, queued)
+	}
+
+	if _, err := core.IsEven(4); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("IsEven() with a full queue = %v, want ErrQueueFull", err)
+	}
+
+	close(unblock)
+	wg.Wait()
+	if running, queued := admission.InFlight(); running != 0 || queued != 0 {
+		t.Errorf("InFlight() after draining = %d, %d; want 0, 0", running, queued)
+	}
+}
+
+func TestAdmissionControl_Cancel(t *testing.T) {
+	started, unblock := make(chan int, 10), make(chan struct{})
+	admission := NewAdmissionControl(1, 1)
+	core := newIsEvenAiCore(testPromptTemplates, blockingHandler(started, unblock))
+	core.Use(admission.Middleware())
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = core.IsEven(1)
+		close(done)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := core.WithContext(ctx).IsEven(2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("IsEven() while waiting past the deadline = %v, want context.DeadlineExceeded", err)
+	}
+	if _, queued := admission.InFlight(); queued != 0 {
+		t.Errorf("Canceled query is still queued")
+	}
+
+	close(unblock)
+	<-done
+	if _, err := core.IsEven(3); err != nil {
+		t.Errorf("IsEven() after draining failed: %v", err)
+	}
+}
+
+func TestAdmissionControl_Priority(t *testing.T) {
+	started, unblock := make(chan int, 10), make(chan struct{})
+	admission := NewAdmissionControl(1, 2)
+	core := newIsEvenAiCore(testPromptTemplates, blockingHandler(started, unblock))
+	core.Use(admission.Middleware())
+
+	ask := func(n int, p Priority) <-chan error {
+		errc := make(chan error, 1)
+		go func() {
+			_, err := core.WithContext(WithPriority(context.Background(), p)).IsEven(n)
+			errc <- err
+		}()
+		return errc
+	}
+	first := ask(0, PriorityNormal)
+	<-started
+	low := ask(1, PriorityLow)
+	waitQueued(t, admission, 1)
+	normal := ask(2, PriorityNormal)
+	waitQueued(t, admission, 2)
+	high := ask(3, PriorityHigh) // Displaces the low priority query from the full queue
+	if err := <-low; !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Low priority query = %v, want ErrQueueFull", err)
+	}
+	waitQueued(t, admission, 2)
+
+	close(unblock)
+	for _, errc := range []<-chan error{first, normal, high} {
+		if err := <-errc; err != nil {
+			t.Errorf("Query failed: %v", err)
+		}
+	}
+	var order []int
+	for len(started) > 0 {
+		order = append(order, <-started)
+	}
+	if !slices.Equal(order, []int{3, 2}) {
+		t.Errorf("Queued queries ran in order %v, want [3 2]", order)
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.Background()
+	if p := PriorityFromContext(ctx); p != PriorityNormal {
+		t.Errorf("PriorityFromContext() = %v, want normal", p)
+	}
+	if p := PriorityFromContext(WithPriority(ctx, 7)); p != PriorityHigh {
+		t.Errorf("PriorityFromContext() of 7 = %v, want high", p)
+	}
+}
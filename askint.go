@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/philwo/is-even-ai/core"
+)
+
+// defaultAskIntAttempts is how often AskInt asks by default.
+const defaultAskIntAttempts = 3
+
+// AskIntOptions describes and validates the question of AskInt.
+type AskIntOptions struct {
+	// Predicate and Args describe the question in the audit log and
+	// UsageByPredicate. Optional: Predicate defaults to "askInt".
+	Predicate string
+	Args      []int
+	// Range, if set, rejects answers outside of it.
+	Range *NumberRange
+	// Validate, if set, rejects answers for which it returns false, e.g.
+	// after asking the model to certify them. An error fails AskInt.
+	Validate func(n int) (bool, error)
+	// Attempts is how often the question is asked before AskInt gives up.
+	// Optional: defaults to 3.
+	Attempts int
+}
+
+// AskInt asks the model prompt, whose answer is a whole number, and parses
+// the answer with ParseNumber. Answers that are unparsable or rejected by
+// the options are asked for again; after the last attempt AskInt fails with
+// an error wrapping ErrAnswerRejected. Predicates with numeric answers, such
+// as GenerateEven and NextEven, are built on it.
+//
+// Like ExtractAndCheck, the question bypasses middleware, but counts towards
+// Usage and the Budget and is recorded in the audit log.
+func (ai *IsEvenAiGemini) AskInt(ctx context.Context, prompt string, opts ...AskIntOptions) (int, error) {
+	var o AskIntOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Predicate == "" {
+		o.Predicate = "askInt"
+	}
+	if o.Attempts <= 0 {
+		o.Attempts = defaultAskIntAttempts
+	}
+	info := CallInfo{Predicate: o.Predicate, Args: o.Args}
+
+	var rejected error
+	for range o.Attempts {
+		n, err := ai.askNumber(ctx, info, prompt)
+		switch {
+		case errors.Is(err, ErrUnparsableAnswer):
+			rejected = err
+			continue
+		case err != nil:
+			return 0, err
+		case o.Range != nil && !o.Range.Contains(n):
+			rejected = fmt.Errorf("%d is not between %d and %d", n, o.Range.Min, o.Range.Max)
+			continue
+		}
+		if o.Validate != nil {
+			valid, err := o.Validate(n)
+			if err != nil {
+				return 0, err
+			}
+			if !valid {
+				rejected = fmt.Errorf("%d failed validation", n)
+				ai.logger.Warn("gemini answer rejected", "model", ai.modelName, "predicate", o.Predicate, "n", n)
+				continue
+			}
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%w after %d attempts: %w", ErrAnswerRejected, o.Attempts, rejected)
+}
+
+// askNumber asks the primary model prompt, whose answer is a number, with
+// the bookkeeping of regular queries.
+func (ai *IsEvenAiGemini) askNumber(ctx context.Context, info CallInfo, prompt string) (int, error) {
+	if err := ai.Drainer().Enter(); err != nil {
+		return 0, err
+	}
+	defer ai.Drainer().Leave()
+	if err := ai.usage.checkBudget(); err != nil {
+		return 0, err
+	}
+
+	callCtx, cancel := withCallTimeout(core.ContextWithCallInfo(ctx, info), ai.timeout)
+	defer cancel()
+	start := time.Now()
+	call := geminiCall{model: ai.modelName}
+	ai.logger.Debug("gemini request started", "model", call.model, "predicate", info.Predicate)
+	n, err := ai.generateNumber(callCtx, prompt, &call)
+	if err != nil {
+		ai.logger.Debug("gemini request failed", "model", call.model, "latency", time.Since(start), "err", err)
+	}
+	ai.audit(start, info, LabelsFromContext(ctx), prompt, call, nil, err)
+	return n, err
+}
+
+// generateNumber sends prompt to the primary model and parses the answer.
+func (ai *IsEvenAiGemini) generateNumber(ctx context.Context, prompt string, call *geminiCall) (int, error) {
+	resp, err := ai.freeformModel().GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate content from Gemini API: %w", geminiError(call.model, err))
+	}
+	ai.recordUsage(ctx, call.model, resp)
+	call.rawAnswer = geminiResponseText(resp)
+	n, err := ParseNumber(call.rawAnswer)
+	if err != nil {
+		ai.logger.Warn("gemini answer not understood", "model", call.model, "text", call.rawAnswer)
+	}
+	return n, err
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_AskInt(t *testing.T) {
+	asked := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		asked++
+		geminitest.WriteAnswer(w, r, "**1,024**")
+	})
+	ctx := context.Background()
+
+	if n, err := ai.AskInt(ctx, "How many bytes are in a kilobyte?"); err != nil || n != 1024 {
+		t.Errorf("AskInt() = %d, %v; want 1024", n, err)
+	}
+	if u := ai.UsageByPredicate()["askInt"]; u.Requests != 1 {
+		t.Errorf("Expected 1 askInt request in usage, got %d", u.Requests)
+	}
+
+	asked = 0
+	validateErr := errors.New("validator failed")
+	_, err := ai.AskInt(ctx, "Pick a number.", AskIntOptions{Attempts: 5, Validate: func(int) (bool, error) { return false, nil }})
+	if !errors.Is(err, ErrAnswerRejected) || asked != 5 {
+		t.Errorf("AskInt() with a rejecting validator = %v after %d attempts, want ErrAnswerRejected after 5", err, asked)
+	}
+	if _, err := ai.AskInt(ctx, "Pick a number.", AskIntOptions{Validate: func(int) (bool, error) { return false, validateErr }}); !errors.Is(err, validateErr) {
+		t.Errorf("AskInt() with a failing validator = %v, want its error", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one entry of an AuditLog, describing a single query.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Predicate string    `json:"predicate"` // Prompt name, e.g. "isEven"
+	Args      []int     `json:"args"`
+	Prompt    string    `json:"prompt"`
+	Model     string    `json:"model"`
+	// TemplateVersion is the IsEvenAiCorePromptTemplates.Version of Prompt.
+	TemplateVersion string `json:"template_version,omitempty"`
+	RawAnswer       string `json:"raw_answer"`
+	Result          *bool  `json:"result"` // null if the answer was undefined
+	Error           string `json:"error,omitempty"`
+	LatencyMs       int64  `json:"latency_ms"`
+	// Labels are the labels of the query, see WithLabels.
+	Labels Labels `json:"labels,omitempty"`
+}
+
+// AuditLog appends one JSON line per query to a writer. It is safe for
+// concurrent use, so one AuditLog can be shared by several clients.
+type AuditLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewAuditLog returns an AuditLog writing to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenAuditLog opens (or creates) the file at path for appending and returns an
+// AuditLog writing to it. Call Close when done.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return NewAuditLog(f), nil
+}
+
+// Record appends rec to the log.
+func (l *AuditLog) Record(rec AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(rec); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
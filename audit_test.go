@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenAuditLog_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	yes := true
+	for i, n := range []int{2, 3} {
+		log, err := OpenAuditLog(path)
+		if err != nil {
+			t.Fatalf("OpenAuditLog failed: %v", err)
+		}
+		rec := AuditRecord{Time: time.Unix(int64(i), 0), Predicate: "isEven", Args: []int{n}, Result: &yes}
+		if err := log.Record(rec); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if err := log.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var args []int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", scanner.Text(), err)
+		}
+		args = append(args, rec.Args...)
+	}
+	if len(args) != 2 || args[0] != 2 || args[1] != 3 {
+		t.Errorf("Expected records for 2 and 3 in order, got args %v", args)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// BearerTokenFunc adapts a function returning bearer tokens, e.g. from an
+// enterprise gateway's token endpoint, to an oauth2.TokenSource for
+// GeminiClientOptions.TokenSource. The function is called for every request;
+// to reuse tokens until they expire, return them from an oauth2.TokenSource
+// wrapped in oauth2.ReuseTokenSource instead.
+type BearerTokenFunc func() (string, error)
+
+// Token implements oauth2.TokenSource.
+func (f BearerTokenFunc) Token() (*oauth2.Token, error) {
+	token, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token, TokenType: "Bearer"}, nil
+}
+
+// authTransport returns base authenticating requests with the token source
+// or, if there is none, the API key of opts.
+func authTransport(base http.RoundTripper, opts GeminiClientOptions) http.RoundTripper {
+	if opts.TokenSource != nil {
+		return &oauth2.Transport{Source: opts.TokenSource, Base: base}
+	}
+	return &apiKeyTransport{base: base, apiKey: opts.APIKey}
+}
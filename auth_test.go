@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestNewIsEvenAiGemini_TokenSource(t *testing.T) {
+	var mu sync.Mutex
+	var auth []string
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auth = append(auth, r.Header.Get("Authorization")+"|"+r.Header.Get("x-goog-api-key")+"|"+r.URL.Query().Get("key"))
+		mu.Unlock()
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"name":"models/gemini-2.0-flash-lite"}`)
+			return
+		}
+		geminitest.WriteAnswer(w, r, "true")
+	})
+
+	tokens := 0
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{
+		BaseURL: baseURL,
+		TokenSource: BearerTokenFunc(func() (string, error) {
+			tokens++
+			return fmt.Sprintf("token-%d", tokens), nil
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini with a token source failed: %v", err)
+	}
+	defer ai.Close()
+
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if err := ai.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	want := []string{"Bearer token-1||", "Bearer token-2||"}
+	if fmt.Sprint(auth) != fmt.Sprint(want) {
+		t.Errorf("Requests were authenticated with %q, want %q", auth, want)
+	}
+}
+
+func TestNewIsEvenAiGemini_TokenSourceError(t *testing.T) {
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "true")
+	})
+	errNoToken := errors.New("gateway refused to mint a token")
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{
+		BaseURL:     baseURL,
+		TokenSource: BearerTokenFunc(func() (string, error) { return "", errNoToken }),
+	})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini failed: %v", err)
+	}
+	defer ai.Close()
+	if _, err := ai.IsEven(2); !errors.Is(err, errNoToken) {
+		t.Errorf("Expected the token source error, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchResult is the answer to one number of a batch.
+type BatchResult struct {
+	Index    int // Position of N in the input
+	N        int
+	Result   *bool
+	Err      error
+	Latency  time.Duration
+	Metadata CallMetadata // Empty unless ask passes its ctx on, see Batch
+}
+
+func (r BatchResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%d: error after %v: %v", r.N, r.Latency, r.Err)
+	}
+	return fmt.Sprintf("%d: %s in %v", r.N, core.AnswerOf(r.Result), r.Latency)
+}
+
+// LogValue implements slog.LogValuer, logging the result as a group of its
+// fields.
+func (r BatchResult) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("index", r.Index),
+		slog.Int("n", r.N),
+		slog.Any("answer", core.AnswerOf(r.Result)),
+		slog.Duration("latency", r.Latency),
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("err", r.Err.Error()))
+	}
+	if r.Metadata != (CallMetadata{}) {
+		attrs = append(attrs, slog.Any("metadata", r.Metadata))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Batch calls ask for each of numbers, running up to concurrency calls at once
+// (at least one), and sends the results on the returned channel as they
+// complete. Results may therefore arrive out of order; use Index to restore it.
+// The channel is closed once every number has been reported. After ctx is
+// done, numbers that have not been started yet are reported with ctx.Err()
+// instead of being asked.
+//
+// ask receives a context derived from ctx that collects CallMetadata, so
+// queries made with it report their model and cost in BatchResult.Metadata:
+//
+//	results := is_even_ai.Batch(ctx, numbers, 8, func(ctx context.Context, n int) (*bool, error) {
+//		return ai.WithContext(ctx).IsEven(n)
+//	})
+func Batch(ctx context.Context, numbers []int, concurrency int, ask func(ctx context.Context, n int) (*bool, error)) <-chan BatchResult {
+	concurrency = max(concurrency, 1)
+	results := make(chan BatchResult, concurrency)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(numbers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				res := BatchResult{Index: i, N: numbers[i]}
+				if res.Err = ctx.Err(); res.Err == nil {
+					callCtx, md := WithCallMetadata(ctx)
+					start := time.Now()
+					res.Result, res.Err = ask(callCtx, numbers[i])
+					res.Latency = time.Since(start)
+					res.Metadata = *md
+				}
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		for i := range numbers {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// BatchMode selects how BatchContext handles numbers whose question fails.
+type BatchMode int
+
+const (
+	// CollectAll asks about every number and reports failures in the
+	// results only.
+	CollectAll BatchMode = iota
+	// FailFast stops at the first failure: questions in flight are canceled
+	// through their context, and numbers not started yet are not asked.
+	FailFast
+)
+
+// BatchContext is like Batch, but waits for the whole batch, running it in an
+// errgroup, and returns the results in the order of numbers. In FailFast mode
+// the error is the first failure, wrapped with its number, and the results of
+// canceled or skipped numbers carry the cancellation error. In CollectAll mode
+// every failure is reported in its BatchResult only, and the error is ctx.Err()
+// if ctx was done before all numbers were asked:
+//
+//	results, err := is_even_ai.BatchContext(ctx, numbers, 8, is_even_ai.FailFast, func(ctx context.Context, n int) (*bool, error) {
+//		return ai.WithContext(ctx).IsEven(n)
+//	})
+func BatchContext(ctx context.Context, numbers []int, concurrency int, mode BatchMode, ask func(ctx context.Context, n int) (*bool, error)) ([]BatchResult, error) {
+	g, gctx := &errgroup.Group{}, ctx
+	if mode == FailFast {
+		g, gctx = errgroup.WithContext(ctx)
+	}
+	g.SetLimit(max(concurrency, 1))
+	results := make([]BatchResult, len(numbers))
+	for i, n := range numbers {
+		results[i] = BatchResult{Index: i, N: n}
+		if err := gctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		g.Go(func() error {
+			res := &results[i]
+			if res.Err = gctx.Err(); res.Err != nil {
+				return nil // Canceled while waiting for a free slot
+			}
+			callCtx, md := WithCallMetadata(gctx)
+			start := time.Now()
+			res.Result, res.Err = ask(callCtx, n)
+			res.Latency = time.Since(start)
+			res.Metadata = *md
+			if res.Err != nil && mode == FailFast {
+				return fmt.Errorf("%d: %w", n, res.Err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return results, err
+	}
+	return results, ctx.Err()
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	local := NewIsEvenAiLocal()
+	numbers := []int{1, 2, 3, 4, 5, 6, 7}
+	got := make([]*bool, len(numbers))
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		return local.WithContext(ctx).IsEven(n)
+	}
+	for res := range Batch(context.Background(), numbers, 3, ask) {
+		if res.Err != nil {
+			t.Fatalf("Unexpected error for %d: %v", res.N, res.Err)
+		}
+		if res.Metadata.Model != localModel || res.Metadata.Requests != 1 {
+			t.Errorf("Unexpected metadata for %d: %+v", res.N, res.Metadata)
+		}
+		if res.N != numbers[res.Index] {
+			t.Errorf("Result for index %d has N=%d, want %d", res.Index, res.N, numbers[res.Index])
+		}
+		got[res.Index] = res.Result
+	}
+	for i, n := range numbers {
+		if got[i] == nil || *got[i] != (n%2 == 0) {
+			t.Errorf("IsEven(%d) = %v, want %v", n, got[i], n%2 == 0)
+		}
+	}
+}
+
+func TestBatch_Concurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	ask := func(_ context.Context, n int) (*bool, error) {
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return new(bool), nil
+	}
+	count := 0
+	for range Batch(context.Background(), make([]int, 20), 4, ask) {
+		count++
+	}
+	if count != 20 {
+		t.Errorf("Expected 20 results, got %d", count)
+	}
+	if p := peak.Load(); p > 4 || p < 2 {
+		t.Errorf("Expected between 2 and 4 concurrent calls, got %d", p)
+	}
+}
+
+func TestBatch_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	ask := func(_ context.Context, n int) (*bool, error) {
+		calls++
+		return new(bool), nil
+	}
+	for res := range Batch(ctx, []int{1, 2, 3}, 1, ask) {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for %d, got %v", res.N, res.Err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Expected no calls after cancellation, got %d", calls)
+	}
+}
+
+func TestBatch_Empty(t *testing.T) {
+	ask := func(context.Context, int) (*bool, error) { return nil, nil }
+	for res := range Batch(context.Background(), nil, 4, ask) {
+		t.Errorf("Unexpected result %+v", res)
+	}
+}
+
+func TestBatchContext(t *testing.T) {
+	local := NewIsEvenAiLocal()
+	boom := errors.New("boom")
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		if n == 3 {
+			return nil, boom
+		}
+		return local.WithContext(ctx).IsEven(n)
+	}
+	numbers := []int{1, 2, 3, 4, 5}
+
+	results, err := BatchContext(context.Background(), numbers, 2, CollectAll, ask)
+	if err != nil {
+		t.Fatalf("BatchContext(CollectAll) failed: %v", err)
+	}
+	for i, res := range results {
+		switch {
+		case res.Index != i || res.N != numbers[i]:
+			t.Errorf("Result %d is for index %d, N=%d", i, res.Index, res.N)
+		case res.N == 3 && !errors.Is(res.Err, boom):
+			t.Errorf("Result for 3 has error %v, want boom", res.Err)
+		case res.N != 3 && (res.Err != nil || res.Result == nil || *res.Result != (res.N%2 == 0) || res.Metadata.Model != localModel):
+			t.Errorf("Unexpected result %+v", res)
+		}
+	}
+
+	// With one slot, the numbers after 3 are never asked.
+	var asked atomic.Int32
+	results, err = BatchContext(context.Background(), numbers, 1, FailFast, func(ctx context.Context, n int) (*bool, error) {
+		asked.Add(1)
+		return ask(ctx, n)
+	})
+	if !errors.Is(err, boom) || !strings.HasPrefix(err.Error(), "3: ") {
+		t.Errorf("BatchContext(FailFast) = %v, want boom for 3", err)
+	}
+	if asked.Load() != 3 {
+		t.Errorf("FailFast asked %d numbers, want 3", asked.Load())
+	}
+	if !errors.Is(results[4].Err, context.Canceled) || results[1].Err != nil {
+		t.Errorf("Unexpected results after failing fast: %v", results)
+	}
+}
+
+func TestBatchContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := BatchContext(ctx, []int{1, 2}, 2, CollectAll, func(context.Context, int) (*bool, error) {
+		t.Error("ask called after cancellation")
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) || len(results) != 2 || !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("BatchContext() = %v, %v; want context.Canceled", results, err)
+	}
+}
+
+func TestBatchResult_Log(t *testing.T) {
+	yes := true
+	r := BatchResult{Index: 1, N: 4, Result: &yes, Latency: time.Second, Metadata: CallMetadata{Model: "m", Usage: Usage{Requests: 1, PromptTokens: 10}}}
+	if got, want := r.String(), "4: true in 1s"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("answered", "result", r)
+	want := `"result":{"index":1,"n":4,"answer":"true","latency":1000000000,"metadata":{"model":"m","requests":1,"prompt_tokens":10,"completion_tokens":0,"cost_usd":0}}`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Logged %s, want it to contain %s", buf.String(), want)
+	}
+
+	r = BatchResult{N: 3, Err: errors.New("boom")}
+	if got, want := r.String(), "3: error after 0s: boom"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
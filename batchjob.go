@@ -0,0 +1,413 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// geminiBatchDiscount is the share of the list price charged for batch jobs.
+const geminiBatchDiscount = 0.5
+
+// BatchJobState is the state of an asynchronous batch job.
+type BatchJobState string
+
+const (
+	BatchJobPending   BatchJobState = "PENDING"
+	BatchJobRunning   BatchJobState = "RUNNING"
+	BatchJobSucceeded BatchJobState = "SUCCEEDED"
+	BatchJobFailed    BatchJobState = "FAILED"
+	BatchJobCancelled BatchJobState = "CANCELLED"
+	BatchJobExpired   BatchJobState = "EXPIRED"
+)
+
+// Done reports whether a job in state s has finished, successfully or not.
+func (s BatchJobState) Done() bool {
+	switch s {
+	case BatchJobSucceeded, BatchJobFailed, BatchJobCancelled, BatchJobExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// GeminiBatchJob is a batch of IsEven questions, or questions of another
+// one-number predicate, submitted to the Gemini Batch API, which answers them
+// asynchronously, typically within a few hours, at half the price of regular
+// queries. GeminiBatchJob is safe for concurrent use.
+type GeminiBatchJob struct {
+	// Name identifies the job, e.g. "batches/123". Pass it to
+	// IsEvenAiGemini.BatchJob to pick the job up again, e.g. after a restart.
+	Name string
+
+	ai     *IsEvenAiGemini
+	labels Labels // Of the submitting context, for usage accounting
+
+	mu      sync.Mutex
+	state   BatchJobState
+	err     error // Why the job failed
+	results []BatchResult
+}
+
+// SubmitBatchJob submits a batch job asking IsEven for each of numbers with the
+// configured model and returns without waiting for the answers. Use Wait to
+// collect them. Fallback models, session mode and verification do not apply to
+// batch jobs, and the audit log does not record them.
+func (ai *IsEvenAiGemini) SubmitBatchJob(ctx context.Context, numbers []int) (*GeminiBatchJob, error) {
+	return ai.SubmitPredicateBatchJob(ctx, "isEven", numbers)
+}
+
+// SubmitPredicateBatchJob is like SubmitBatchJob, but asks predicate, a
+// one-number prompt name such as "isOdd" or "isPerfectNumber", instead of
+// IsEven.
+func (ai *IsEvenAiGemini) SubmitPredicateBatchJob(ctx context.Context, predicate string, numbers []int) (*GeminiBatchJob, error) {
+	if len(numbers) == 0 {
+		return nil, errors.New("batch job needs at least one number")
+	}
+	if err := ai.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+	requests := make([]geminiBatchRequest, len(numbers))
+	for i, n := range numbers {
+		prompt, err := ai.Prompt(predicate, n)
+		if err != nil {
+			return nil, err
+		}
+		if prompt == "" {
+			return nil, fmt.Errorf("batch jobs cannot ask %s without its prompt template", predicate)
+		}
+		requests[i] = geminiBatchRequest{
+			Request: geminiRequest{
+				Contents:          []geminiContent{{Role: "user", Parts: []geminiPart{{Text: prompt}}}},
+				SystemInstruction: &geminiContent{Parts: []geminiPart{{Text: geminiSystemPrompt}}},
+				GenerationConfig:  ai.batchGenerationConfig(),
+			},
+			Metadata: map[string]string{"index": strconv.Itoa(i), "n": strconv.Itoa(n), "predicate": predicate},
+		}
+	}
+	body := map[string]any{
+		"batch": map[string]any{
+			"displayName": "is-even-ai",
+			"inputConfig": map[string]any{"requests": map[string]any{"requests": requests}},
+		},
+	}
+
+	var op geminiOperation
+	if err := ai.rest(ctx, http.MethodPost, "v1beta/models/"+ai.modelName+":batchGenerateContent", body, &op); err != nil {
+		return nil, fmt.Errorf("failed to submit Gemini batch job: %w", err)
+	}
+	if op.Name == "" {
+		return nil, errors.New("failed to submit Gemini batch job: response has no job name")
+	}
+	ai.logger.Debug("gemini batch job submitted", "job", op.Name, "model", ai.modelName, "predicate", predicate, "numbers", len(numbers))
+	job := ai.BatchJob(op.Name)
+	job.labels = LabelsFromContext(ctx)
+	job.update(op)
+	return job, nil
+}
+
+// batchGenerationConfig returns the generation settings of batch requests.
+func (ai *IsEvenAiGemini) batchGenerationConfig() geminiGenerationConfig {
+	config := geminiGenerationConfig{Temperature: ai.temperature}
+	if ai.deterministic {
+		topK, candidates, seed := int32(1), int32(1), deterministicSeed
+		config.TopK, config.CandidateCount, config.Seed = &topK, &candidates, &seed
+	}
+	return config
+}
+
+// BatchJob returns a handle for a batch job submitted earlier, possibly by
+// another instance using the same API key.
+func (ai *IsEvenAiGemini) BatchJob(name string) *GeminiBatchJob {
+	return &GeminiBatchJob{Name: name, ai: ai, state: BatchJobPending}
+}
+
+// Status fetches the job's current state from the API. Once the job has
+// succeeded, its results are available from Results.
+func (j *GeminiBatchJob) Status(ctx context.Context) (BatchJobState, error) {
+	j.mu.Lock()
+	state := j.state
+	j.mu.Unlock()
+	if state.Done() {
+		return state, nil
+	}
+	var op geminiOperation
+	if err := j.ai.rest(ctx, http.MethodGet, "v1beta/"+j.Name, nil, &op); err != nil {
+		if isGeminiNotFound(err) {
+			return "", fmt.Errorf("gemini batch job %s not found: %w", j.Name, err)
+		}
+		return "", fmt.Errorf("failed to get Gemini batch job status: %w", err)
+	}
+	return j.update(op), nil
+}
+
+// Wait polls the job's status every interval (at least one second) until it is
+// done or ctx is done, and returns the results ordered by Index. Unanswered
+// questions are reported with an Err or an undefined Result; a job that
+// failed, was cancelled or expired is an error.
+func (j *GeminiBatchJob) Wait(ctx context.Context, interval time.Duration) ([]BatchResult, error) {
+	interval = max(interval, time.Second)
+	for {
+		state, err := j.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if state.Done() {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			return slices.Clone(j.results), j.err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Results returns the results of a job that has succeeded, ordered by Index,
+// or nil if the job has not succeeded (yet).
+func (j *GeminiBatchJob) Results() []BatchResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return slices.Clone(j.results)
+}
+
+// Cancel asks the API to cancel the job. Questions answered before the job
+// stops are not reported.
+func (j *GeminiBatchJob) Cancel(ctx context.Context) error {
+	if err := j.ai.rest(ctx, http.MethodPost, "v1beta/"+j.Name+":cancel", struct{}{}, nil); err != nil {
+		return fmt.Errorf("failed to cancel Gemini batch job: %w", err)
+	}
+	return nil
+}
+
+// update records the state reported in op and, the first time the job is seen
+// to have succeeded, its results and usage.
+func (j *GeminiBatchJob) update(op geminiOperation) BatchJobState {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state.Done() {
+		return j.state
+	}
+	state := op.state()
+	if !state.Done() {
+		j.state = state
+		return state
+	}
+	j.state = state
+	model := j.ai.modelName
+	switch {
+	case op.Error != nil:
+		j.err = fmt.Errorf("gemini batch job %s %s: %s", j.Name, strings.ToLower(string(state)), op.Error.Message)
+	case state != BatchJobSucceeded:
+		j.err = fmt.Errorf("gemini batch job %s %s", j.Name, strings.ToLower(string(state)))
+	default:
+		for i, resp := range op.inlinedResponses() {
+			res := resp.result(i)
+			res.Metadata.Model = model
+			if u := resp.Response.usage(); u != nil {
+				res.Metadata.Requests = 1
+				res.Metadata.PromptTokens = u.PromptTokenCount
+				res.Metadata.CompletionTokens = u.CandidatesTokenCount
+				predicate := cmp.Or(resp.Metadata["predicate"], "isEven") // Jobs submitted by older versions lack it
+				res.Metadata.CostUSD = j.ai.usage.recordDiscounted(model, predicate, j.labels, u.PromptTokenCount, u.CandidatesTokenCount, geminiBatchDiscount)
+			}
+			j.results = append(j.results, res)
+		}
+		slices.SortStableFunc(j.results, func(a, b BatchResult) int { return a.Index - b.Index })
+	}
+	j.ai.logger.Debug("gemini batch job finished", "job", j.Name, "state", state, "results", len(j.results))
+	return state
+}
+
+// rest sends a JSON request to the API path below restBase and decodes the
+// response into out, unless out is nil. API errors are returned as *googleapi.Error.
+func (ai *IsEvenAiGemini) rest(ctx context.Context, method, path string, in, out any) error {
+	ctx, cancel := withCallTimeout(ctx, ai.timeout)
+	defer cancel()
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ai.restBase+"/"+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := ai.restClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// The following types mirror the parts of the Gemini REST API used by batch
+// jobs, which the SDK does not support.
+
+type geminiPart struct {
+	Text string `json:"text,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature    float32 `json:"temperature"`
+	TopK           *int32  `json:"topK,omitempty"`
+	CandidateCount *int32  `json:"candidateCount,omitempty"`
+	Seed           *int32  `json:"seed,omitempty"`
+}
+
+type geminiRequest struct {
+	Contents          []geminiContent        `json:"contents"`
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiBatchRequest struct {
+	Request  geminiRequest     `json:"request"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type geminiUsageMetadata struct {
+	PromptTokenCount     int64 `json:"promptTokenCount"`
+	CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content *geminiContent `json:"content"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata *geminiUsageMetadata `json:"usageMetadata"`
+}
+
+// usage returns the token counts of r, or nil if there are none.
+func (r *geminiResponse) usage() *geminiUsageMetadata {
+	if r == nil {
+		return nil
+	}
+	return r.UsageMetadata
+}
+
+// answer interprets r like parseGeminiResponse.
+func (r *geminiResponse) answer() (*bool, error) {
+	if len(r.Candidates) == 0 || r.Candidates[0].Content == nil || len(r.Candidates[0].Content.Parts) == 0 {
+		if r.PromptFeedback != nil && r.PromptFeedback.BlockReason != "" && r.PromptFeedback.BlockReason != "BLOCK_REASON_UNSPECIFIED" {
+			return nil, fmt.Errorf("gemini API request blocked, reason: %s", r.PromptFeedback.BlockReason)
+		}
+		return nil, nil
+	}
+	answer, err := ParseAnswer(r.Candidates[0].Content.Parts[0].Text)
+	if err != nil {
+		return nil, nil // Unrecognized answers are undefined
+	}
+	return answer.Bool(), nil
+}
+
+type geminiStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type geminiInlinedResponse struct {
+	Response *geminiResponse   `json:"response"`
+	Error    *geminiStatus     `json:"error"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// result converts the response to the question at position i of the batch,
+// unless its metadata says otherwise.
+func (r geminiInlinedResponse) result(i int) BatchResult {
+	res := BatchResult{Index: i}
+	if index, err := strconv.Atoi(r.Metadata["index"]); err == nil {
+		res.Index = index
+	}
+	res.N, _ = strconv.Atoi(r.Metadata["n"])
+	switch {
+	case r.Error != nil:
+		res.Err = fmt.Errorf("gemini batch request failed: %s", r.Error.Message)
+	case r.Response != nil:
+		res.Result, res.Err = r.Response.answer()
+	}
+	return res
+}
+
+type geminiBatchOutput struct {
+	InlinedResponses struct {
+		InlinedResponses []geminiInlinedResponse `json:"inlinedResponses"`
+	} `json:"inlinedResponses"`
+}
+
+type geminiOperation struct {
+	Name     string `json:"name"`
+	Done     bool   `json:"done"`
+	Metadata struct {
+		State  string             `json:"state"`
+		Output *geminiBatchOutput `json:"output"`
+	} `json:"metadata"`
+	Response *geminiBatchOutput `json:"response"`
+	Error    *geminiStatus      `json:"error"`
+}
+
+// state returns the job state reported by op, e.g. BatchJobSucceeded for
+// "BATCH_STATE_SUCCEEDED".
+func (op geminiOperation) state() BatchJobState {
+	if _, state, ok := strings.Cut(op.Metadata.State, "STATE_"); ok && state != "UNSPECIFIED" {
+		return BatchJobState(state)
+	}
+	switch {
+	case op.Error != nil:
+		return BatchJobFailed
+	case op.Done:
+		return BatchJobSucceeded
+	default:
+		return BatchJobPending
+	}
+}
+
+// inlinedResponses returns the answers of a finished job.
+func (op geminiOperation) inlinedResponses() []geminiInlinedResponse {
+	if op.Response != nil && len(op.Response.InlinedResponses.InlinedResponses) > 0 {
+		return op.Response.InlinedResponses.InlinedResponses
+	}
+	if op.Metadata.Output != nil {
+		return op.Metadata.Output.InlinedResponses.InlinedResponses
+	}
+	return nil
+}
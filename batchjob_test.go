@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsEvenAiGemini_BatchJob(t *testing.T) {
+	var submitted string
+	polls := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/v1beta/models/priced-model:batchGenerateContent"):
+			body, _ := io.ReadAll(r.Body)
+			submitted = string(body)
+			_, _ = io.WriteString(w, `{"name":"batches/42","metadata":{"state":"BATCH_STATE_PENDING"}}`)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/v1beta/batches/42"):
+			polls++
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"name":"batches/42","metadata":{"state":"BATCH_STATE_RUNNING"}}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"name":"batches/42","done":true,"metadata":{"state":"BATCH_STATE_SUCCEEDED"},
+				"response":{"inlinedResponses":{"inlinedResponses":[
+					{"metadata":{"index":"2","n":"5"},"error":{"code":500,"message":"internal"}},
+					{"metadata":{"index":"0","n":"2"},"response":{"candidates":[{"content":{"parts":[{"text":"true"}]}}],"usageMetadata":{"promptTokenCount":20,"candidatesTokenCount":1}}},
+					{"metadata":{"index":"1","n":"3"},"response":{"candidates":[{"content":{"parts":[{"text":"False."}]}}],"usageMetadata":{"promptTokenCount":20,"candidatesTokenCount":1}}}
+				]}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}, GeminiModelOptions{
+		Model:      "priced-model",
+		PriceTable: map[string]ModelPrice{"priced-model": {InputPerMillion: 1, OutputPerMillion: 10}},
+	})
+	ctx := context.Background()
+
+	job, err := ai.SubmitBatchJob(ctx, []int{2, 3, 5})
+	if err != nil {
+		t.Fatalf("SubmitBatchJob failed: %v", err)
+	}
+	if job.Name != "batches/42" {
+		t.Errorf("Expected job batches/42, got %q", job.Name)
+	}
+	var req struct {
+		Batch struct {
+			InputConfig struct {
+				Requests struct {
+					Requests []geminiBatchRequest `json:"requests"`
+				} `json:"requests"`
+			} `json:"inputConfig"`
+		} `json:"batch"`
+	}
+	if err := json.Unmarshal([]byte(submitted), &req); err != nil {
+		t.Fatalf("Invalid batch request %s: %v", submitted, err)
+	}
+	if reqs := req.Batch.InputConfig.Requests.Requests; len(reqs) != 3 || reqs[1].Request.Contents[0].Parts[0].Text != "Is 3 an even number?" || reqs[1].Metadata["n"] != "3" {
+		t.Errorf("Unexpected batch requests: %s", submitted)
+	}
+
+	if state, err := job.Status(ctx); err != nil || state != BatchJobRunning {
+		t.Errorf("Status() = %q, %v; want RUNNING", state, err)
+	}
+	if res := job.Results(); res != nil {
+		t.Errorf("Expected no results while running, got %+v", res)
+	}
+	if state, err := job.Status(ctx); err != nil || state != BatchJobSucceeded {
+		t.Fatalf("Status() = %q, %v; want SUCCEEDED", state, err)
+	}
+	results, err := job.Wait(ctx, 0)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("Expected no more polls once the job is done, got %d", polls)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %+v", results)
+	}
+	for i, want := range []struct {
+		n      int
+		result string
+	}{{2, "true"}, {3, "false"}, {5, "error"}} {
+		res := results[i]
+		got := "error"
+		if res.Err == nil && res.Result != nil {
+			got = fmt.Sprint(*res.Result)
+		}
+		if res.Index != i || res.N != want.n || got != want.result {
+			t.Errorf("results[%d] = %+v (%s); want N=%d %s", i, res, got, want.n, want.result)
+		}
+	}
+
+	u := ai.Usage()
+	if want := (40*1 + 2*10) / 1e6 / 2; u.Requests != 2 || u.PromptTokens != 40 || math.Abs(u.CostUSD-want) > 1e-12 {
+		t.Errorf("Expected usage of 2 requests at half price ($%g), got %+v", want, u)
+	}
+}
+
+func TestIsEvenAiGemini_PredicateBatchJob(t *testing.T) {
+	var submitted string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			submitted = string(body)
+			_, _ = io.WriteString(w, `{"name":"batches/6","done":true,"metadata":{"state":"BATCH_STATE_SUCCEEDED"},
+				"response":{"inlinedResponses":{"inlinedResponses":[
+					{"metadata":{"index":"0","n":"6","predicate":"isPerfectNumber"},"response":{"candidates":[{"content":{"parts":[{"text":"true"}]}}],"usageMetadata":{"promptTokenCount":20,"candidatesTokenCount":1}}}
+				]}}}`)
+			return
+		}
+		http.NotFound(w, r)
+	})
+	ctx := context.Background()
+
+	job, err := ai.SubmitPredicateBatchJob(ctx, "isPerfectNumber", []int{6})
+	if err != nil {
+		t.Fatalf("SubmitPredicateBatchJob failed: %v", err)
+	}
+	if want := DefaultGeminiPromptTemplates.IsPerfectNumber(6); !strings.Contains(submitted, want) || !strings.Contains(submitted, `"predicate":"isPerfectNumber"`) {
+		t.Errorf("Batch request %s does not ask %q", submitted, want)
+	}
+	results := job.Results()
+	if len(results) != 1 || results[0].Err != nil || results[0].Result == nil || !*results[0].Result {
+		t.Errorf("Results() = %+v, want 6 to be perfect", results)
+	}
+	if u := ai.UsageByPredicate()["isPerfectNumber"]; u.Requests != 1 {
+		t.Errorf("Expected 1 isPerfectNumber request in the usage, got %+v", u)
+	}
+
+	if _, err := ai.SubmitPredicateBatchJob(ctx, "areEqual", []int{6}); err == nil {
+		t.Error("SubmitPredicateBatchJob(areEqual) succeeded")
+	}
+}
+
+func TestIsEvenAiGemini_BatchJobFailed(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"batches/7","done":true,"metadata":{"state":"BATCH_STATE_EXPIRED"}}`)
+	})
+
+	_, err := ai.BatchJob("batches/7").Wait(context.Background(), 0)
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected an expired job error, got %v", err)
+	}
+}
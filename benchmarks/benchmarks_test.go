@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func BenchmarkPromptConstruction(b *testing.B) {
+	templates := is_even_ai.DefaultGeminiPromptTemplates
+	for i := 0; b.Loop(); i++ {
+		_ = templates.IsEven(i)
+		_ = templates.IsGreaterThan(i, i+1)
+	}
+}
+
+func BenchmarkParseAnswer(b *testing.B) {
+	for _, text := range []string{"true", "  **False**.\n", "I cannot answer that"} {
+		b.Run(text, func(b *testing.B) {
+			for b.Loop() {
+				_, _ = is_even_ai.ParseAnswer(text)
+			}
+		})
+	}
+}
+
+func BenchmarkLocal(b *testing.B) {
+	local := is_even_ai.NewIsEvenAiLocal()
+	for i := 0; b.Loop(); i++ {
+		if _, err := local.IsEven(i); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGeminiFakeServer(b *testing.B) {
+	srv := geminitest.NewServer(geminitest.Always("true"))
+	defer srv.Close()
+	ai, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: srv.URL})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ai.Close()
+
+	b.Run("Serial", func(b *testing.B) {
+		for i := 0; b.Loop(); i++ {
+			if _, err := ai.IsEven(i); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				if _, err := ai.IsEven(i); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkCacheHit(b *testing.B) {
+	srv := geminitest.NewServer(geminitest.Always("true"))
+	defer srv.Close()
+	ai, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: srv.URL})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ai.Close()
+	ai.Use(is_even_ai.CacheMiddleware(is_even_ai.NewMemoryCache()))
+	if _, err := ai.IsEven(2); err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := ai.IsEven(2); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if n := len(srv.Requests()); n != 1 {
+		b.Errorf("Expected all queries after the first to hit the cache, got %d requests", n)
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	srv := geminitest.NewServer(geminitest.Always("true"))
+	defer srv.Close()
+	ai, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: srv.URL})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ai.Close()
+	numbers := make([]int, 100)
+	for i := range numbers {
+		numbers[i] = i
+	}
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		return ai.WithContext(ctx).IsEven(n)
+	}
+	for _, concurrency := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("Concurrency%d", concurrency), func(b *testing.B) {
+			for b.Loop() {
+				for res := range is_even_ai.Batch(context.Background(), numbers, concurrency, ask) {
+					if res.Err != nil {
+						b.Fatal(res.Err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package benchmarks holds benchmarks of the query hot path, run offline
+// against the local provider and the geminitest fake server:
+//
+//	go test -bench . -benchmem ./benchmarks
+package benchmarks
@@ -0,0 +1,305 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// Cache stores answers by key. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the answer stored for key, and whether there was one.
+	Get(ctx context.Context, key string) (Answer, bool, error)
+	// Set stores answer for key.
+	Set(ctx context.Context, key string, answer Answer) error
+}
+
+// ExpiringCache is a Cache that can also store answers for a limited time.
+// CacheMiddlewareWithOptions uses it for undefined answers.
+type ExpiringCache interface {
+	Cache
+	// SetTTL stores answer for key until ttl has passed.
+	SetTTL(ctx context.Context, key string, answer Answer, ttl time.Duration) error
+}
+
+// DeletingCache is a Cache that can also remove answers.
+// CacheReverifier uses it to invalidate answers that became undefined.
+type DeletingCache interface {
+	Cache
+	// Delete removes the answer stored for key, if there is one.
+	Delete(ctx context.Context, key string) error
+}
+
+// TimedCache is a Cache that also knows when answers were stored.
+// CacheMiddlewareWithOptions uses it for CacheOptions.TTL.
+type TimedCache interface {
+	Cache
+	// GetTime is like Get, and also returns when the answer was stored, or
+	// the zero time if that is unknown.
+	GetTime(ctx context.Context, key string) (Answer, time.Time, bool, error)
+}
+
+// MemoryCache is an unbounded in-memory ExpiringCache, DeletingCache,
+// TimedCache and CacheInvalidator. Expired entries are removed when they are
+// next looked up.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	answer  Answer
+	expires time.Time // zero if the entry does not expire
+	stored  time.Time
+	info    CallInfo // Question answered, from the context of Set
+}
+
+var (
+	_ ExpiringCache    = (*MemoryCache)(nil)
+	_ DeletingCache    = (*MemoryCache)(nil)
+	_ TimedCache       = (*MemoryCache)(nil)
+	_ CacheInvalidator = (*MemoryCache)(nil)
+)
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(ctx context.Context, key string) (Answer, bool, error) {
+	a, _, ok, err := c.GetTime(ctx, key)
+	return a, ok, err
+}
+
+// GetTime implements TimedCache.
+func (c *MemoryCache) GetTime(_ context.Context, key string) (Answer, time.Time, bool, error) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.mu.Lock()
+		if cur, ok := c.entries[key]; ok && cur.expires.Equal(e.expires) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		return AnswerUndefined, time.Time{}, false, nil
+	}
+	return e.answer, e.stored, ok, nil
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(ctx context.Context, key string, answer Answer) error {
+	info, _ := CallInfoFromContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryEntry{answer: answer, stored: c.now(), info: info}
+	return nil
+}
+
+// SetTTL implements ExpiringCache.
+func (c *MemoryCache) SetTTL(ctx context.Context, key string, answer Answer, ttl time.Duration) error {
+	info, _ := CallInfoFromContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.entries[key] = memoryEntry{answer: answer, expires: now.Add(ttl), stored: now, info: info}
+	return nil
+}
+
+// Delete implements DeletingCache.
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// InvalidateNumber implements CacheInvalidator. Only answers stored with a
+// CallInfo in the context, as by CacheMiddleware, are matched.
+func (c *MemoryCache) InvalidateNumber(_ context.Context, n int) error {
+	c.deleteFunc(func(e memoryEntry) bool { return slices.Contains(e.info.Args, n) })
+	return nil
+}
+
+// InvalidatePredicate implements CacheInvalidator. Like InvalidateNumber, it
+// only matches answers stored with a CallInfo.
+func (c *MemoryCache) InvalidatePredicate(_ context.Context, predicate string) error {
+	c.deleteFunc(func(e memoryEntry) bool { return e.info.Predicate == predicate })
+	return nil
+}
+
+// FlushCache implements CacheInvalidator.
+func (c *MemoryCache) FlushCache(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	return nil
+}
+
+// deleteFunc removes the entries for which del returns true.
+func (c *MemoryCache) deleteFunc(del func(memoryEntry) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	maps.DeleteFunc(c.entries, func(_ string, e memoryEntry) bool { return del(e) })
+}
+
+// Len returns the number of cached answers, including expired ones that have
+// not been removed yet.
+func (c *MemoryCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// CacheKey returns the key under which CacheMiddleware stores the answer to
+// prompt. Besides the prompt, with its whitespace normalized, the key covers
+// the AnswerSource from the CallInfo in ctx, so that changing the provider,
+// model, prompt templates or system prompt invalidates earlier answers. Keys
+// start with a format version and the provider and model in clear text, e.g.
+// "v1:gemini:gemini-2.0-flash-lite:" followed by a hash.
+func CacheKey(ctx context.Context, prompt string) string {
+	info, _ := CallInfoFromContext(ctx)
+	provider := strings.ToLower(strings.TrimSpace(info.Source.Provider))
+	model := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(info.Source.Model), "models/"))
+	h := sha256.New()
+	for _, s := range []string{provider, model, info.Source.TemplateVersion, info.Source.SystemPromptHash, strings.Join(strings.Fields(prompt), " ")} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s:%s:%s:%x", cacheKeyVersion, provider, model, h.Sum(nil))
+}
+
+// cacheKeyVersion is the format version of CacheKey. Bump it when changing
+// what goes into keys.
+const cacheKeyVersion = "v1"
+
+// CacheOptions configures CacheMiddlewareWithOptions.
+type CacheOptions struct {
+	// UndefinedTTL, if positive, also caches undefined answers for this long,
+	// so a model that keeps refusing to answer for a number is not asked again
+	// on every query. It requires a cache implementing ExpiringCache; other
+	// caches only store defined answers.
+	UndefinedTTL time.Duration
+	// TTL, if positive, is how long defined answers are fresh. Older answers
+	// count as misses, unless StaleWhileRevalidate is set. It requires a
+	// cache implementing TimedCache; answers in other caches stay fresh.
+	TTL time.Duration
+	// StaleWhileRevalidate serves answers older than TTL right away and asks
+	// the model again in the background, keeping latency flat for hot
+	// numbers. Only one refresh per answer runs at a time; failed and
+	// undefined refreshes keep the stale answer. Refreshes are not reported
+	// to the CallMetadata of the query that triggered them.
+	StaleWhileRevalidate bool
+	// Drainer, if set, tracks refreshes like queries, so that Shutdown waits
+	// for them and no refresh starts once it has begun. Pass the Drainer() of
+	// the client the middleware is added to.
+	Drainer *core.Drainer
+	// Reverifier, if set, remembers the questions answered from or stored in
+	// the cache, so that its Run can ask them again.
+	Reverifier *CacheReverifier
+}
+
+// CacheMiddleware answers repeated prompts from cache instead of asking the
+// model again. Answers are stored under CacheKey. Only defined answers are
+// cached. Cache errors are not fatal: a failed lookup counts as a miss and a
+// failed store is ignored.
+func CacheMiddleware(cache Cache) Middleware {
+	return CacheMiddlewareWithOptions(cache, CacheOptions{})
+}
+
+// CacheMiddlewareWithOptions is like CacheMiddleware, with negative caching of
+// undefined answers and expiry of defined ones configured by opts. Errors are
+// never cached.
+func CacheMiddlewareWithOptions(cache Cache, opts CacheOptions) Middleware {
+	expiring, _ := cache.(ExpiringCache)
+	cacheUndefined := opts.UndefinedTTL > 0 && expiring != nil
+	timed, _ := cache.(TimedCache)
+	expire := opts.TTL > 0 && timed != nil
+	return func(next QueryHandler) QueryHandler {
+		var (
+			mu         sync.Mutex
+			refreshing = map[string]bool{} // Keys of stale answers being refreshed
+		)
+		// refresh asks the question again in the background and stores a
+		// defined answer.
+		refresh := func(ctx context.Context, key, prompt string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if refreshing[key] {
+				return
+			}
+			if opts.Drainer != nil && opts.Drainer.Enter() != nil {
+				return // Shutting down
+			}
+			refreshing[key] = true
+			// The refresh outlives the query, so it must neither be canceled
+			// with it nor report to its CallMetadata.
+			ctx, _ = WithCallMetadata(context.WithoutCancel(ctx))
+			go func() {
+				defer func() {
+					mu.Lock()
+					delete(refreshing, key)
+					mu.Unlock()
+					if opts.Drainer != nil {
+						opts.Drainer.Leave()
+					}
+				}()
+				if res, err := next(ctx, prompt); err == nil && res != nil {
+					_ = cache.Set(ctx, key, core.AnswerOf(res))
+					opts.Reverifier.remember(ctx, cache, next, key, prompt, core.AnswerOf(res))
+				}
+			}()
+		}
+
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			key := CacheKey(ctx, prompt)
+			var (
+				a      Answer
+				stored time.Time
+				ok     bool
+				err    error
+			)
+			if expire {
+				a, stored, ok, err = timed.GetTime(ctx, key)
+			} else {
+				a, ok, err = cache.Get(ctx, key)
+			}
+			stale := expire && a != AnswerUndefined && !stored.IsZero() && time.Since(stored) >= opts.TTL
+			if err == nil && ok && (!stale || opts.StaleWhileRevalidate) {
+				info, _ := CallInfoFromContext(ctx)
+				eventsFromContext(ctx).CacheHit(ctx, info, a)
+				if a != AnswerUndefined {
+					opts.Reverifier.remember(ctx, cache, next, key, prompt, a)
+				}
+				if stale {
+					refresh(ctx, key, prompt)
+				}
+				return a.Bool(), nil
+			}
+			res, err := next(ctx, prompt)
+			switch {
+			case err != nil:
+			case res != nil:
+				_ = cache.Set(ctx, key, core.AnswerOf(res))
+				opts.Reverifier.remember(ctx, cache, next, key, prompt, core.AnswerOf(res))
+			case cacheUndefined:
+				_ = expiring.SetTTL(ctx, key, AnswerUndefined, opts.UndefinedTTL)
+			}
+			return res, err
+		}
+	}
+}
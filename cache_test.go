@@ -0,0 +1,285 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true), func() (*bool, error) { return nil, nil }, failed(errors.New("boom"))))
+	core.Use(CacheMiddleware(cache))
+
+	for i := 0; i < 3; i++ {
+		res, err := core.IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+	}
+	if calls != 1 || cache.Len() != 1 {
+		t.Errorf("Expected one query and one cached answer, got %d queries and %d entries", calls, cache.Len())
+	}
+
+	// Undefined answers and errors are not cached.
+	_, _ = core.IsEven(3)
+	_, _ = core.IsEven(3)
+	if calls != 3 || cache.Len() != 1 {
+		t.Errorf("Expected undefined answers and errors to bypass the cache, got %d queries and %d entries", calls, cache.Len())
+	}
+}
+
+func TestMemoryCache_Invalidate(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+	ai := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true)))
+	ai.Use(CacheMiddleware(cache))
+	ai.RegisterCache(cache)
+	_, _ = ai.IsEven(2)
+	_, _ = ai.IsEven(5)
+	_, _ = ai.AreEqual(3, 2)
+	_, _ = ai.IsGreaterThan(7, 1)
+	if cache.Len() != 4 {
+		t.Fatalf("Expected 4 cached answers, got %d", cache.Len())
+	}
+
+	// Questions comparing 2 to another number are purged as well.
+	if err := ai.InvalidateNumber(2); err != nil || cache.Len() != 2 {
+		t.Errorf("InvalidateNumber(2) = %v, leaving %d entries; want 2", err, cache.Len())
+	}
+	_, _ = ai.IsEven(2)
+	if calls != 5 {
+		t.Errorf("Expected the model to be asked again, got %d queries", calls)
+	}
+	if err := ai.InvalidatePredicate("isGreaterThan"); err != nil || cache.Len() != 2 {
+		t.Errorf("InvalidatePredicate(isGreaterThan) = %v, leaving %d entries; want 2", err, cache.Len())
+	}
+	if err := ai.FlushCache(); err != nil || cache.Len() != 0 {
+		t.Errorf("FlushCache() = %v, leaving %d entries; want none", err, cache.Len())
+	}
+}
+
+// failingCache is a Cache whose operations always fail.
+type failingCache struct{}
+
+func (failingCache) Get(context.Context, string) (Answer, bool, error) {
+	return AnswerUndefined, false, errors.New("cache down")
+}
+
+func (failingCache) Set(context.Context, string, Answer) error { return errors.New("cache down") }
+
+func TestCacheMiddleware_CacheErrors(t *testing.T) {
+	core := NewIsEvenAiLocal()
+	core.Use(CacheMiddleware(failingCache{}))
+	res, err := core.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+}
+
+func TestCacheMiddleware_UndefinedTTL(t *testing.T) {
+	calls := 0
+	now := time.Unix(0, 0)
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+	undefined := func() (*bool, error) { return nil, nil }
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, undefined, answered(false)))
+	core.Use(CacheMiddlewareWithOptions(cache, CacheOptions{UndefinedTTL: time.Minute}))
+
+	for i := 0; i < 3; i++ {
+		if res, err := core.IsEven(3); res != nil || err != nil {
+			t.Fatalf("IsEven(3) = %v, %v; want undefined", res, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the undefined answer to be cached, got %d queries", calls)
+	}
+
+	// The model is asked again once the negative entry has expired.
+	now = now.Add(time.Minute)
+	res, err := core.IsEven(3)
+	checkGeminiResult(t, res, err, false, "IsEven", 3)
+	if calls != 2 {
+		t.Errorf("Expected a new query after the TTL, got %d queries", calls)
+	}
+	res, err = core.IsEven(3)
+	checkGeminiResult(t, res, err, false, "IsEven", 3)
+	if calls != 2 {
+		t.Errorf("Expected the defined answer to be cached, got %d queries", calls)
+	}
+}
+
+func TestCacheMiddleware_TTL(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true), answered(false)))
+	core.Use(CacheMiddlewareWithOptions(cache, CacheOptions{TTL: time.Minute}))
+
+	_, _ = core.IsEven(2)
+	res, err := core.IsEven(2)
+	checkGeminiResult(t, res, err, false, "IsEven", 2)
+	if calls != 2 {
+		t.Errorf("Expected the expired answer to be asked again, got %d queries", calls)
+	}
+}
+
+func TestCacheMiddleware_StaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	refreshed := make(chan struct{})
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	core := newIsEvenAiCore(testPromptTemplates, func(context.Context, string) (*bool, error) {
+		answer := calls.Add(1) == 1
+		if !answer {
+			defer close(refreshed)
+		}
+		return &answer, nil
+	})
+	core.Use(CacheMiddlewareWithOptions(cache, CacheOptions{TTL: time.Minute, StaleWhileRevalidate: true}))
+
+	_, _ = core.IsEven(2)
+	cache.now = time.Now
+
+	// The stale answer is served while the model is asked again.
+	res, err := core.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	<-refreshed
+	for refreshing := true; refreshing; time.Sleep(time.Millisecond) {
+		cache.mu.RLock()
+		for _, e := range cache.entries {
+			refreshing = e.answer != AnswerFalse
+		}
+		cache.mu.RUnlock()
+	}
+	res, err = core.IsEven(2)
+	checkGeminiResult(t, res, err, false, "IsEven", 2)
+	if calls.Load() != 2 {
+		t.Errorf("Expected one refresh, got %d queries", calls.Load())
+	}
+}
+
+func TestCacheMiddleware_StaleWhileRevalidateShutdown(t *testing.T) {
+	refreshing := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	ai := newIsEvenAiCore(testPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		calls++
+		if calls > 1 {
+			close(refreshing)
+			<-release
+		}
+		core.CallMetadataFromContext(ctx).Record("model", 1, 1, 0)
+		answer := true
+		return &answer, nil
+	})
+	ai.Use(CacheMiddlewareWithOptions(cache, CacheOptions{TTL: time.Minute, StaleWhileRevalidate: true, Drainer: ai.Drainer()}))
+
+	_, _ = ai.IsEven(2)
+	cache.now = time.Now
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	<-refreshing
+
+	done := make(chan error)
+	go func() { done <- ai.Shutdown(context.Background()) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned %v while a refresh was in flight", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if md.Requests != 0 {
+		t.Errorf("Expected the refresh not to report to the caller's CallMetadata, got %+v", *md)
+	}
+}
+
+func TestCacheMiddleware_UndefinedTTLUnsupported(t *testing.T) {
+	calls := 0
+	stats := &Stats{}
+	cache := &plainCache{NewMemoryCache()}
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, func() (*bool, error) { return nil, nil }))
+	core.Use(CacheMiddlewareWithOptions(stats.Cache(cache), CacheOptions{UndefinedTTL: time.Minute}))
+	_, _ = core.IsEven(3)
+	_, _ = core.IsEven(3)
+	if calls != 2 || cache.Len() != 0 {
+		t.Errorf("Expected undefined answers to bypass a cache without expiry, got %d queries and %d entries", calls, cache.Len())
+	}
+}
+
+// plainCache hides the SetTTL method of a MemoryCache.
+type plainCache struct{ c *MemoryCache }
+
+func (p *plainCache) Get(ctx context.Context, key string) (Answer, bool, error) {
+	return p.c.Get(ctx, key)
+}
+func (p *plainCache) Set(ctx context.Context, key string, a Answer) error {
+	return p.c.Set(ctx, key, a)
+}
+func (p *plainCache) Len() int { return p.c.Len() }
+
+func TestCacheKey(t *testing.T) {
+	withSource := func(source AnswerSource) context.Context {
+		return core.ContextWithCallInfo(context.Background(), CallInfo{Predicate: "isEven", Args: []int{2}, Source: source})
+	}
+	base := AnswerSource{Provider: "gemini", Model: "gemini-2.0-flash-lite", TemplateVersion: "1", SystemPromptHash: HashSystemPrompt("be brief")}
+	key := CacheKey(withSource(base), "Is 2 an even number?")
+	if !strings.HasPrefix(key, "v1:gemini:gemini-2.0-flash-lite:") {
+		t.Errorf("CacheKey() = %q, want the format version, provider and model as prefix", key)
+	}
+
+	// Equivalent spellings map to the same key.
+	same := base
+	same.Provider, same.Model = "Gemini", "models/gemini-2.0-flash-lite"
+	if got := CacheKey(withSource(same), "  Is 2 an\teven number? "); got != key {
+		t.Errorf("CacheKey() with normalized differences = %q, want %q", got, key)
+	}
+
+	for name, change := range map[string]func(*AnswerSource){
+		"provider":         func(s *AnswerSource) { s.Provider = "local" },
+		"model":            func(s *AnswerSource) { s.Model = "gemini-2.0-flash" },
+		"template version": func(s *AnswerSource) { s.TemplateVersion = "2" },
+		"system prompt":    func(s *AnswerSource) { s.SystemPromptHash = HashSystemPrompt("be verbose") },
+	} {
+		source := base
+		change(&source)
+		if got := CacheKey(withSource(source), "Is 2 an even number?"); got == key {
+			t.Errorf("CacheKey() did not change with the %s", name)
+		}
+	}
+	if got := CacheKey(withSource(base), "Is 4 an even number?"); got == key {
+		t.Error("CacheKey() did not change with the prompt")
+	}
+}
+
+func TestCacheMiddleware_TemplateVersion(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+	handler := countingHandler(&calls, answered(true))
+	v1 := newIsEvenAiCore(testPromptTemplates, handler)
+	v1.Use(CacheMiddleware(cache))
+	templates := testPromptTemplates
+	templates.Version = "edited"
+	v2 := newIsEvenAiCore(templates, handler)
+	v2.Use(CacheMiddleware(cache))
+
+	_, _ = v1.IsEven(2)
+	_, _ = v1.IsEven(2)
+	_, _ = v2.IsEven(2)
+	if calls != 2 || cache.Len() != 2 {
+		t.Errorf("Expected new templates to bypass old answers, got %d queries and %d entries", calls, cache.Len())
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// Defaults of Canary.
+const defaultCanaryInterval = 10 * time.Minute
+
+var defaultCanaryNumbers = []int{0, 1, 2, 7, 10, 42, 99, -3, -8, 1<<31 - 1}
+
+// Canary periodically asks a provider IsEven and IsOdd about a fixed set of
+// numbers and signals drift when the share of correct answers drops below a
+// threshold, e.g. after a silent update of the model behind a model name.
+// Run it in the background:
+//
+//	canary := &is_even_ai.Canary{
+//		Provider:  ai,
+//		Threshold: 0.95,
+//		OnDrift:   func(r is_even_ai.CanaryResult) { page("model drift: %.0f%% correct", 100*r.Accuracy.Accuracy()) },
+//	}
+//	go canary.Run(ctx)
+//
+// Give it a provider without caching middleware, whose cached answers would
+// hide a changed model. Errors do not count as drift, as they are usually
+// outages rather than wrong answers.
+type Canary struct {
+	// Provider is asked the canary questions. Providers built on IsEvenAiCore
+	// are asked with the context of Run or Check.
+	Provider Provider
+	// Numbers are the numbers asked about. Optional: defaults to a mix of
+	// small, large and negative numbers.
+	Numbers []int
+	// Interval is the time between checks. Optional: defaults to 10 minutes.
+	Interval time.Duration
+	// Threshold is the share of correct answers, between 0 and 1, below which
+	// a check signals drift. Optional: defaults to 1, i.e. any wrong or
+	// undefined answer.
+	Threshold float64
+	// OnResult, if set, is called with the result of every check, e.g. to
+	// export the accuracy as a metric.
+	OnResult func(CanaryResult)
+	// OnDrift, if set, is called when a check signals drift. It is not called
+	// again until a check passes.
+	OnDrift func(CanaryResult)
+
+	mu       sync.Mutex
+	last     *CanaryResult
+	drifting bool
+}
+
+// CanaryResult is the outcome of one Canary check.
+type CanaryResult struct {
+	Time time.Time
+	// Accuracy counts the answers; errors are not counted as questions.
+	Accuracy PredicateAccuracy
+	Errors   int
+	Drift    bool
+}
+
+// Run checks the provider right away and then every Interval until ctx is
+// done, and returns ctx.Err().
+func (c *Canary) Run(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultCanaryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Check(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check asks the canary questions once and reports the result to OnResult
+// and, if it signals drift, to OnDrift. If ctx is done, Check stops and
+// returns ctx.Err().
+func (c *Canary) Check(ctx context.Context) (CanaryResult, error) {
+	var p predicateAsker = c.Provider
+	if cp, ok := p.(contextProvider); ok {
+		p = cp.WithContext(ctx)
+	}
+	numbers := c.Numbers
+	if len(numbers) == 0 {
+		numbers = defaultCanaryNumbers
+	}
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	res := CanaryResult{Time: time.Now()}
+	for _, n := range numbers {
+		for _, predicate := range []string{"isEven", "isOdd"} {
+			if err := ctx.Err(); err != nil {
+				return CanaryResult{}, err
+			}
+			want, err := core.GroundTruth(predicate, []int{n})
+			if err != nil {
+				return CanaryResult{}, err
+			}
+			got, err := askProvider(p, predicate, []int{n})
+			if err != nil {
+				res.Errors++
+				continue
+			}
+			res.Accuracy.Add(want, got, nil)
+		}
+	}
+	res.Drift = res.Accuracy.Questions > 0 && res.Accuracy.Accuracy() < threshold
+
+	c.mu.Lock()
+	c.last = &res
+	alert := res.Drift && !c.drifting
+	c.drifting = res.Drift
+	c.mu.Unlock()
+
+	if c.OnResult != nil {
+		c.OnResult(res)
+	}
+	if alert && c.OnDrift != nil {
+		c.OnDrift(res)
+	}
+	return res, nil
+}
+
+// Last returns the result of the latest check, if there was one.
+func (c *Canary) Last() (CanaryResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		return CanaryResult{}, false
+	}
+	return *c.last, true
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+func TestCanary_Check(t *testing.T) {
+	var drifted, failing atomic.Bool
+	provider := &stubProvider{answer: func(predicate string, args ...int) (*bool, error) {
+		if failing.Load() {
+			return nil, errors.New("unavailable")
+		}
+		if drifted.Load() {
+			return answerAlways(true)(predicate, args...) // Everything is even and odd
+		}
+		res, err := core.GroundTruth(predicate, args)
+		return &res, err
+	}}
+	var drifts int
+	canary := &Canary{Provider: provider, Numbers: []int{1, 2}, Threshold: 0.9, OnDrift: func(CanaryResult) { drifts++ }}
+	ctx := context.Background()
+
+	if res, err := canary.Check(ctx); err != nil || res.Drift || res.Accuracy.Questions != 4 || res.Accuracy.Accuracy() != 1 {
+		t.Fatalf("Check() = %+v, %v; want all 4 answers correct", res, err)
+	}
+	drifted.Store(true)
+	res, err := canary.Check(ctx)
+	if err != nil || !res.Drift || res.Accuracy.Accuracy() != 0.5 || drifts != 1 {
+		t.Fatalf("Check() = %+v, %v with %d drift signals; want drift at 50%%", res, err, drifts)
+	}
+	if _, _ = canary.Check(ctx); drifts != 1 {
+		t.Errorf("Expected no repeated drift signal, got %d", drifts)
+	}
+
+	failing.Store(true)
+	res, _ = canary.Check(ctx)
+	if res.Drift || res.Errors != 4 || res.Accuracy.Questions != 0 {
+		t.Errorf("Check() = %+v; want errors not to count as drift", res)
+	}
+	if last, ok := canary.Last(); !ok || last.Errors != 4 {
+		t.Errorf("Last() = %+v, %t", last, ok)
+	}
+	failing.Store(false)
+	if _, _ = canary.Check(ctx); drifts != 2 {
+		t.Errorf("Expected a new drift signal after recovering, got %d", drifts)
+	}
+}
+
+func TestCanary_Run(t *testing.T) {
+	var checks atomic.Int32
+	canary := &Canary{Provider: NewIsEvenAiLocal(), Interval: 10 * time.Millisecond, OnResult: func(CanaryResult) { checks.Add(1) }}
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	if err := canary.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() = %v, want context.DeadlineExceeded", err)
+	}
+	if n := checks.Load(); n < 2 {
+		t.Errorf("Expected several checks, got %d", n)
+	}
+	if last, _ := canary.Last(); last.Drift || last.Accuracy.Questions != 2*len(defaultCanaryNumbers) {
+		t.Errorf("Unexpected last result %+v", last)
+	}
+}
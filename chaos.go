@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ChaosOptions configures the faults injected by IsEvenAiChaos. Rates are
+// probabilities between 0 and 1; at most one fault is injected per call, and
+// the rates must not add up to more than 1.
+type ChaosOptions struct {
+	TimeoutRate   float64       // Fail with context.DeadlineExceeded after TimeoutDelay
+	TimeoutDelay  time.Duration // How long a simulated timeout takes
+	RateLimitRate float64       // Fail with ErrRateLimited, like an HTTP 429
+	UndefinedRate float64       // Return an undefined (nil) answer
+	WrongRate     float64       // Return the opposite of the real answer
+	// Rand is the source of randomness; set it for reproducible runs.
+	// Optional: defaults to a randomly seeded source.
+	Rand *rand.Rand
+}
+
+// IsEvenAiChaos wraps a provider and injects failures at configurable
+// probabilities, to test how applications cope with upstream AI weirdness.
+type IsEvenAiChaos struct {
+	inner Provider
+	opts  ChaosOptions
+
+	mu   sync.Mutex // Guards rand, which is not safe for concurrent use
+	rand *rand.Rand
+}
+
+var _ Provider = (*IsEvenAiChaos)(nil)
+
+// NewIsEvenAiChaos creates a fault-injecting wrapper around inner.
+func NewIsEvenAiChaos(inner Provider, opts ChaosOptions) *IsEvenAiChaos {
+	if inner == nil {
+		panic("inner provider cannot be nil")
+	}
+	if total := opts.TimeoutRate + opts.RateLimitRate + opts.UndefinedRate + opts.WrongRate; total > 1 {
+		panic(fmt.Sprintf("chaos rates add up to %g, more than 1", total))
+	}
+	c := &IsEvenAiChaos{inner: inner, opts: opts, rand: opts.Rand}
+	if c.rand == nil {
+		c.rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return c
+}
+
+// inject either fails the call, passes it to the inner provider, or distorts the inner answer.
+func (c *IsEvenAiChaos) inject(ask func(Provider) (*bool, error)) (*bool, error) {
+	c.mu.Lock()
+	r := c.rand.Float64()
+	c.mu.Unlock()
+
+	if r -= c.opts.TimeoutRate; r < 0 {
+		time.Sleep(c.opts.TimeoutDelay)
+		return nil, fmt.Errorf("chaos: injected timeout: %w", context.DeadlineExceeded)
+	}
+	if r -= c.opts.RateLimitRate; r < 0 {
+		return nil, fmt.Errorf("chaos: injected 429: %w", ErrRateLimited)
+	}
+	if r -= c.opts.UndefinedRate; r < 0 {
+		return nil, nil
+	}
+	res, err := ask(c.inner)
+	if r -= c.opts.WrongRate; r < 0 && res != nil {
+		wrong := !*res
+		return &wrong, err
+	}
+	return res, err
+}
+
+// IsEven checks if n is even.
+func (c *IsEvenAiChaos) IsEven(n int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.IsEven(n) })
+}
+
+// IsOdd checks if n is odd.
+func (c *IsEvenAiChaos) IsOdd(n int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.IsOdd(n) })
+}
+
+// AreEqual checks if a and b are equal.
+func (c *IsEvenAiChaos) AreEqual(a, b int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.AreEqual(a, b) })
+}
+
+// AreNotEqual checks if a and b are not equal.
+func (c *IsEvenAiChaos) AreNotEqual(a, b int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.AreNotEqual(a, b) })
+}
+
+// IsGreaterThan checks if a is greater than b.
+func (c *IsEvenAiChaos) IsGreaterThan(a, b int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.IsGreaterThan(a, b) })
+}
+
+// IsLessThan checks if a is less than b.
+func (c *IsEvenAiChaos) IsLessThan(a, b int) (*bool, error) {
+	return c.inject(func(p Provider) (*bool, error) { return p.IsLessThan(a, b) })
+}
+
+// Close closes the inner provider.
+func (c *IsEvenAiChaos) Close() error {
+	return c.inner.Close()
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestIsEvenAiChaos_SingleFault(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  ChaosOptions
+		check func(t *testing.T, res *bool, err error)
+	}{
+		{"Timeout", ChaosOptions{TimeoutRate: 1}, func(t *testing.T, res *bool, err error) {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected context.DeadlineExceeded, got %v, %v", res, err)
+			}
+		}},
+		{"RateLimit", ChaosOptions{RateLimitRate: 1}, func(t *testing.T, res *bool, err error) {
+			if !errors.Is(err, ErrRateLimited) {
+				t.Errorf("Expected ErrRateLimited, got %v, %v", res, err)
+			}
+		}},
+		{"Undefined", ChaosOptions{UndefinedRate: 1}, func(t *testing.T, res *bool, err error) {
+			if res != nil || err != nil {
+				t.Errorf("Expected undefined answer, got %v, %v", res, err)
+			}
+		}},
+		{"Wrong", ChaosOptions{WrongRate: 1}, func(t *testing.T, res *bool, err error) {
+			checkGeminiResult(t, res, err, false, "IsEven", 2)
+		}},
+		{"NoFaults", ChaosOptions{}, func(t *testing.T, res *bool, err error) {
+			checkGeminiResult(t, res, err, true, "IsEven", 2)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewIsEvenAiChaos(NewIsEvenAiLocal(), tc.opts)
+			res, err := c.IsEven(2)
+			tc.check(t, res, err)
+		})
+	}
+}
+
+func TestIsEvenAiChaos_Rates(t *testing.T) {
+	c := NewIsEvenAiChaos(NewIsEvenAiLocal(), ChaosOptions{
+		RateLimitRate: 0.25,
+		UndefinedRate: 0.25,
+		Rand:          rand.New(rand.NewPCG(1, 2)),
+	})
+	var limited, undefined, ok int
+	for i := 0; i < 1000; i++ {
+		res, err := c.IsEven(i)
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			limited++
+		case err == nil && res == nil:
+			undefined++
+		case err == nil && *res == (i%2 == 0):
+			ok++
+		default:
+			t.Fatalf("IsEven(%d) = %v, %v: unexpected outcome", i, res, err)
+		}
+	}
+	for name, n := range map[string]int{"rate limited": limited, "undefined": undefined, "correct": ok} {
+		want := 250
+		if name == "correct" {
+			want = 500
+		}
+		if n < want-75 || n > want+75 {
+			t.Errorf("Got %d %s answers out of 1000; want about %d", n, name, want)
+		}
+	}
+}
+
+func TestNewIsEvenAiChaos_InvalidRates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for rates adding up to more than 1")
+		}
+	}()
+	NewIsEvenAiChaos(NewIsEvenAiLocal(), ChaosOptions{UndefinedRate: 0.6, WrongRate: 0.6})
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Command iseven-mcp is a Model Context Protocol server that offers the
+// is-even-ai predicates as tools (is_even, are_equal, is_greater_than), so
+// other LLM agents can delegate questions about numbers to this library.
+//
+// It speaks MCP over stdin and stdout; configure it in an MCP client as:
+//
+//	{"command": "iseven-mcp", "args": ["--provider", "gemini"]}
+//
+// The gemini provider reads the API key from GEMINI_API_KEY. Logs go to stderr.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func main() {
+	providerName := flag.String("provider", "gemini", "AI provider: gemini or local")
+	model := flag.String("model", "", "model to use (default: the provider's default)")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	c, err := newClient(*providerName, *model, logger)
+	if err != nil {
+		logger.Error("failed to create client", "err", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newServer(c.IsEvenAiCore, logger).serve(ctx, os.Stdin, os.Stdout); err != nil {
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// client is a provider's core, which allows per-call contexts, together with
+// the provider's Close method.
+type client struct {
+	*is_even_ai.IsEvenAiCore
+	io.Closer
+}
+
+// newClient creates a client for the named provider.
+func newClient(name, model string, logger *slog.Logger) (client, error) {
+	switch name {
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return client{}, errors.New("GEMINI_API_KEY environment variable not set")
+		}
+		ai, err := is_even_ai.NewIsEvenAiGemini(
+			is_even_ai.GeminiClientOptions{APIKey: apiKey, Logger: logger},
+			is_even_ai.GeminiModelOptions{Model: model},
+		)
+		if err != nil {
+			return client{}, err
+		}
+		return client{ai.IsEvenAiCore, ai}, nil
+	case "local":
+		local := is_even_ai.NewIsEvenAiLocal()
+		return client{local.IsEvenAiCore, local}, nil
+	default:
+		return client{}, fmt.Errorf("unknown provider %q", name)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// protocolVersions lists the MCP revisions this server speaks, newest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// tool is an MCP tool backed by a predicate of the core.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	args []string
+	call func(c *is_even_ai.IsEvenAiCore, args []int) (*bool, error)
+}
+
+func integerSchema(args ...string) map[string]any {
+	props := map[string]any{}
+	for _, arg := range args {
+		props[arg] = map[string]any{"type": "integer"}
+	}
+	return map[string]any{"type": "object", "properties": props, "required": args}
+}
+
+var tools = []tool{
+	{
+		Name:        "is_even",
+		Description: "Determines whether the integer n is even. Returns true, false or undefined.",
+		InputSchema: integerSchema("n"),
+		args:        []string{"n"},
+		call:        func(c *is_even_ai.IsEvenAiCore, args []int) (*bool, error) { return c.IsEven(args[0]) },
+	},
+	{
+		Name:        "are_equal",
+		Description: "Determines whether the integers a and b are equal. Returns true, false or undefined.",
+		InputSchema: integerSchema("a", "b"),
+		args:        []string{"a", "b"},
+		call:        func(c *is_even_ai.IsEvenAiCore, args []int) (*bool, error) { return c.AreEqual(args[0], args[1]) },
+	},
+	{
+		Name:        "is_greater_than",
+		Description: "Determines whether the integer a is greater than b. Returns true, false or undefined.",
+		InputSchema: integerSchema("a", "b"),
+		args:        []string{"a", "b"},
+		call:        func(c *is_even_ai.IsEvenAiCore, args []int) (*bool, error) { return c.IsGreaterThan(args[0], args[1]) },
+	},
+}
+
+// server answers MCP requests with a provider's core.
+type server struct {
+	core   *is_even_ai.IsEvenAiCore
+	logger *slog.Logger
+}
+
+func newServer(core *is_even_ai.IsEvenAiCore, logger *slog.Logger) *server {
+	return &server{core: core, logger: logger}
+}
+
+// serve reads newline delimited JSON-RPC messages from r and writes the
+// responses to w until r is exhausted or ctx is done.
+func (s *server) serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp := s.handle(ctx, scanner.Bytes())
+		if resp == nil {
+			continue // Notification
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// handle processes one message and returns the response, or nil for notifications.
+func (s *server) handle(ctx context.Context, msg []byte) *response {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error"}}
+	}
+	if req.ID == nil {
+		s.logger.Debug("mcp notification", "method", req.Method)
+		return nil
+	}
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	var err *rpcError
+	switch req.Method {
+	case "initialize":
+		resp.Result, err = s.initialize(req.Params)
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		resp.Result = map[string]any{"tools": tools}
+	case "tools/call":
+		resp.Result, err = s.callTool(ctx, req.Params)
+	default:
+		err = &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+	resp.Error = err
+	return resp
+}
+
+func (s *server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid initialize params"}
+	}
+	version := protocolVersions[0]
+	if slices.Contains(protocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]any{"name": "iseven-mcp", "version": "1.0.0"},
+	}, nil
+}
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string                     `json:"name"`
+		Arguments map[string]json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid tools/call params"}
+	}
+	i := slices.IndexFunc(tools, func(t tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{codeInvalidParams, "unknown tool: " + p.Name}
+	}
+	t := tools[i]
+	args := make([]int, len(t.args))
+	for i, name := range t.args {
+		if err := json.Unmarshal(p.Arguments[name], &args[i]); err != nil {
+			return nil, &rpcError{codeInvalidParams, fmt.Sprintf("argument %s must be an integer", name)}
+		}
+	}
+
+	answer, err := t.call(s.core.WithContext(ctx), args)
+	if err != nil {
+		// Tool failures are reported to the model rather than as protocol errors.
+		s.logger.Warn("mcp tool failed", "tool", t.Name, "args", args, "err", err)
+		return toolResult{Content: []textContent{{"text", err.Error()}}, IsError: true}, nil
+	}
+	text := "undefined"
+	if answer != nil {
+		text = strconv.FormatBool(*answer)
+	}
+	return toolResult{Content: []textContent{{"text", text}}}, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// exchange sends the given messages to a server backed by core and returns
+// its output lines.
+func exchange(t *testing.T, core *is_even_ai.IsEvenAiCore, messages ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	s := newServer(core, slog.New(slog.DiscardHandler))
+	if err := s.serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestServer(t *testing.T) {
+	got := exchange(t, is_even_ai.NewIsEvenAiLocal().IsEvenAiCore,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"is_even","arguments":{"n":42}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"are_equal","arguments":{"a":1,"b":2}}}`,
+		`{"jsonrpc":"2.0","id":"four","method":"tools/call","params":{"name":"is_greater_than","arguments":{"a":8,"b":7}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"ping"}`,
+	)
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"tools":{}},"protocolVersion":"2025-03-26","serverInfo":{"name":"iseven-mcp","version":"1.0.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"true"}]}}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"false"}]}}`,
+		`{"jsonrpc":"2.0","id":"four","result":{"content":[{"type":"text","text":"true"}]}}`,
+		`{"jsonrpc":"2.0","id":5,"result":{}}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	got := exchange(t, is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	for _, name := range []string{`"name":"is_even"`, `"name":"are_equal"`, `"name":"is_greater_than"`, `"required":["a","b"]`} {
+		if !strings.Contains(got[0], name) {
+			t.Errorf("tools/list response lacks %s: %s", name, got[0])
+		}
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	failing := is_even_ai.NewIsEvenAiCore(is_even_ai.DefaultGeminiPromptTemplates, func(string) (*bool, error) {
+		return nil, errors.New("model unavailable")
+	})
+	testCases := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"ParseError", `{not json`, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`},
+		{"UnknownMethod", `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found: resources/list"}}`},
+		{"UnknownTool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"is_prime","arguments":{"n":7}}}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"unknown tool: is_prime"}}`},
+		{"BadArgument", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"is_even","arguments":{"n":"seven"}}}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"argument n must be an integer"}}`},
+		{"ToolFailure", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"is_even","arguments":{"n":7}}}`, `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"model unavailable"}],"isError":true}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := exchange(t, failing, tc.msg); got[0] != tc.want {
+				t.Errorf("Got %s; want %s", got[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestServer_NegotiatesVersion(t *testing.T) {
+	got := exchange(t, is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	if !strings.Contains(got[0], `"protocolVersion":"`+protocolVersions[0]+`"`) {
+		t.Errorf("Expected server to offer its latest version for an unknown one, got %s", got[0])
+	}
+}
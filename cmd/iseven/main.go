@@ -0,0 +1,308 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Command iseven asks an AI about numbers from the command line.
+//
+// Usage:
+//
+//	iseven [flags] N            is N even?
+//	iseven [flags] even|odd|leap|perfect|happy|triangular N
+//	iseven [flags] eq|ne|gt|lt|factor|sumeven|prodeven A B
+//	iseven [flags] --file F [even|odd|leap|perfect|happy|triangular]
+//
+// It prints true, false or undefined and exits with status 0 for true
+// (e.g. even), 1 for false and 2 for undefined answers or errors.
+//
+// With --file, it reads newline or comma separated numbers from F ("-" for
+// stdin) and prints "N answer" for each as soon as it is known. The exit
+// status is then the highest one of all numbers.
+//
+// With --output json, csv or table, it prints one record per query with the
+// numbers, answer, model, latency and estimated cost instead.
+//
+// The gemini provider reads the API key from GEMINI_API_KEY.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Exit codes.
+const (
+	exitTrue      = 0
+	exitFalse     = 1
+	exitUndefined = 2 // Also used for errors
+)
+
+const usage = `Usage:
+  iseven [flags] N                 is N even?
+  iseven [flags] even|odd|leap|perfect|happy|triangular N
+  iseven [flags] eq|ne|gt|lt|factor|sumeven|prodeven A B
+  iseven [flags] --file F [even|odd|leap|perfect|happy|triangular]
+
+Prints true, false or undefined. Exit status is 0 for true, 1 for false and
+2 for undefined answers or errors.
+
+With --file, reads newline or comma separated numbers from F ("-" for stdin)
+and prints "N answer" for each as soon as it is known. The exit status is then
+the highest one of all numbers.
+
+With --output json, csv or table, prints one record per query with the
+numbers, answer, model, latency and estimated cost instead.
+
+Flags:
+`
+
+// command is a predicate that can be asked from the command line.
+type command struct {
+	name  string
+	arity int
+	ask   func(c *is_even_ai.IsEvenAiCore, args []int) (*bool, error)
+}
+
+var commands = map[string]command{
+	"even":       {"even", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsEven(n[0]) }},
+	"odd":        {"odd", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsOdd(n[0]) }},
+	"eq":         {"eq", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.AreEqual(n[0], n[1]) }},
+	"ne":         {"ne", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.AreNotEqual(n[0], n[1]) }},
+	"gt":         {"gt", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsGreaterThan(n[0], n[1]) }},
+	"lt":         {"lt", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsLessThan(n[0], n[1]) }},
+	"leap":       {"leap", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsLeapYear(n[0]) }},
+	"perfect":    {"perfect", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsPerfectNumber(n[0]) }},
+	"happy":      {"happy", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsHappyNumber(n[0]) }},
+	"triangular": {"triangular", 1, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsTriangularNumber(n[0]) }},
+	"factor":     {"factor", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.IsFactorOf(n[0], n[1]) }},
+	"sumeven":    {"sumeven", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.SumIsEven(n[0], n[1]) }},
+	"prodeven":   {"prodeven", 2, func(c *is_even_ai.IsEvenAiCore, n []int) (*bool, error) { return c.ProductIsEven(n[0], n[1]) }},
+}
+
+// client is a provider's core, which allows per-call contexts, together with
+// the provider's Close method.
+type client struct {
+	*is_even_ai.IsEvenAiCore
+	io.Closer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("iseven", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	providerName := fs.String("provider", "gemini", "AI provider: gemini or local")
+	model := fs.String("model", "", "model to use (default: the provider's default)")
+	temperature := fs.Float64("temperature", 0, "sampling temperature")
+	deterministic := fs.Bool("deterministic", false, "enforce reproducible answers (gemini DeterministicMode)")
+	file := fs.String("file", "", `read numbers from file ("-" for stdin)`)
+	concurrency := fs.Int("concurrency", 4, "maximum parallel queries with --file")
+	output := fs.String("output", "text", "output format: text, json, csv or table")
+	if err := fs.Parse(args); err != nil {
+		return exitUndefined
+	}
+	out, err := newRecordWriter(*output, *file != "", stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "iseven: %v\n", err)
+		return exitUndefined
+	}
+
+	var cmd command
+	var nums []int
+	if *file != "" {
+		cmd, nums, err = parseBatch(fs.Args(), *file, stdin)
+	} else {
+		cmd, nums, err = parseArgs(fs.Args())
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "iseven: %v\n", err)
+		fs.Usage()
+		return exitUndefined
+	}
+
+	var temp *float32
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "temperature" {
+			t := float32(*temperature)
+			temp = &t
+		}
+	})
+	c, err := newClient(*providerName, *model, temp, *deterministic)
+	if err != nil {
+		fmt.Fprintf(stderr, "iseven: %v\n", err)
+		return exitUndefined
+	}
+	defer c.Close()
+
+	var code int
+	if *file != "" {
+		code = runBatch(c, cmd, nums, *concurrency, out)
+	} else {
+		code = runOne(c, cmd, nums, out)
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(stderr, "iseven: %v\n", err)
+		return exitUndefined
+	}
+	return code
+}
+
+// runOne asks cmd about nums and writes the answer.
+func runOne(c client, cmd command, nums []int, out recordWriter) int {
+	ctx, md := is_even_ai.WithCallMetadata(context.Background())
+	start := time.Now()
+	res, err := cmd.ask(c.WithContext(ctx), nums)
+	return writeRecord(out, newRecord(cmd, nums, res, err, time.Since(start), *md))
+}
+
+// runBatch asks cmd about each of nums and writes the answers as they arrive.
+func runBatch(c client, cmd command, nums []int, concurrency int, out recordWriter) int {
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		return cmd.ask(c.WithContext(ctx), []int{n})
+	}
+	code := exitTrue
+	for res := range is_even_ai.Batch(context.Background(), nums, concurrency, ask) {
+		rec := newRecord(cmd, []int{res.N}, res.Result, res.Err, res.Latency, res.Metadata)
+		code = max(code, writeRecord(out, rec))
+	}
+	return code
+}
+
+// writeRecord writes rec to out and returns the exit status for it.
+func writeRecord(out recordWriter, rec record) int {
+	if err := out.Write(rec); err != nil || rec.err != nil {
+		return exitUndefined
+	}
+	return exitCode(rec.Answer)
+}
+
+// exitCode returns the exit status for an answer.
+func exitCode(res *bool) int {
+	switch {
+	case res == nil:
+		return exitUndefined
+	case *res:
+		return exitTrue
+	default:
+		return exitFalse
+	}
+}
+
+// parseArgs parses the positional arguments into a command and its numbers.
+// A single number is short for "even N".
+func parseArgs(args []string) (command, []int, error) {
+	if len(args) == 0 {
+		return command{}, nil, errors.New("missing arguments")
+	}
+	name := "even"
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return command{}, nil, fmt.Errorf("unknown command %q", name)
+	}
+	if len(args) != cmd.arity {
+		return command{}, nil, fmt.Errorf("%s takes %d number(s), got %d", name, cmd.arity, len(args))
+	}
+	nums := make([]int, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return command{}, nil, fmt.Errorf("invalid number %q", arg)
+		}
+		nums[i] = n
+	}
+	return cmd, nums, nil
+}
+
+// parseBatch parses the positional arguments of batch mode, an optional even
+// or odd, and reads the numbers from file, or from stdin if file is "-".
+func parseBatch(args []string, file string, stdin io.Reader) (command, []int, error) {
+	name := "even"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok || cmd.arity != 1 || len(args) > 0 {
+		return command{}, nil, errors.New("--file only supports one-number predicates without further arguments")
+	}
+
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return command{}, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	nums, err := readNumbers(r)
+	return cmd, nums, err
+}
+
+// readNumbers reads numbers separated by newlines and/or commas. Blank
+// fields are skipped.
+func readNumbers(r io.Reader) ([]int, error) {
+	var nums []int
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		for field := range strings.SplitSeq(scanner.Text(), ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, field)
+			}
+			nums = append(nums, n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read numbers: %w", err)
+	}
+	return nums, nil
+}
+
+// newClient creates a client for the named provider. The local provider is
+// always deterministic.
+func newClient(name, model string, temperature *float32, deterministic bool) (client, error) {
+	switch name {
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return client{}, errors.New("GEMINI_API_KEY environment variable not set")
+		}
+		ai, err := is_even_ai.NewIsEvenAiGemini(
+			is_even_ai.GeminiClientOptions{APIKey: apiKey},
+			is_even_ai.GeminiModelOptions{Model: model, Temperature: temperature, DeterministicMode: deterministic},
+		)
+		if err != nil {
+			return client{}, err
+		}
+		return client{ai.IsEvenAiCore, ai}, nil
+	case "local":
+		local := is_even_ai.NewIsEvenAiLocal()
+		return client{local.IsEvenAiCore, local}, nil
+	default:
+		return client{}, fmt.Errorf("unknown provider %q", name)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		args       string
+		wantCode   int
+		wantStdout string
+	}{
+		{"--provider local 42", exitTrue, "true\n"},
+		{"--provider local 7", exitFalse, "false\n"},
+		{"--provider local even -4", exitTrue, "true\n"},
+		{"--provider local odd 4", exitFalse, "false\n"},
+		{"--provider local gt 8 7", exitTrue, "true\n"},
+		{"--provider local lt 8 7", exitFalse, "false\n"},
+		{"--provider local eq 3 3", exitTrue, "true\n"},
+		{"--provider local ne 3 3", exitFalse, "false\n"},
+		{"--provider local leap 2000", exitTrue, "true\n"},
+		{"--provider local leap 1900", exitFalse, "false\n"},
+		{"--provider local perfect 28", exitTrue, "true\n"},
+		{"--provider local happy 19", exitTrue, "true\n"},
+		{"--provider local triangular 9", exitFalse, "false\n"},
+		{"--provider local factor 3 12", exitTrue, "true\n"},
+		{"--provider local factor 12 3", exitFalse, "false\n"},
+		{"--provider local sumeven 3 5", exitTrue, "true\n"},
+		{"--provider local prodeven 3 5", exitFalse, "false\n"},
+		{"--provider local", exitUndefined, ""},
+		{"--provider local gt 8", exitUndefined, ""},
+		{"--provider local frobnicate 8", exitUndefined, ""},
+		{"--provider local eight", exitUndefined, ""},
+		{"--provider quantum 2", exitUndefined, ""},
+		{"--no-such-flag 2", exitUndefined, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.args, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(strings.Fields(tc.args), strings.NewReader(""), &stdout, &stderr)
+			if code != tc.wantCode || stdout.String() != tc.wantStdout {
+				t.Errorf("run(%q) = %d with output %q; want %d with %q (stderr: %s)", tc.args, code, stdout.String(), tc.wantCode, tc.wantStdout, stderr.String())
+			}
+			if code == exitUndefined && stderr.Len() == 0 {
+				t.Errorf("run(%q) failed without explanation on stderr", tc.args)
+			}
+		})
+	}
+}
+
+func TestRun_GeminiRequiresAPIKey(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"2"}, strings.NewReader(""), &stdout, &stderr); code != exitUndefined || !strings.Contains(stderr.String(), "GEMINI_API_KEY") {
+		t.Errorf("Expected failure mentioning GEMINI_API_KEY, got %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_Batch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      []string
+		input     string
+		wantCode  int
+		wantLines []string
+	}{
+		{"even", []string{"--provider", "local", "--file", "-"}, "2\n4, 6\n\n", exitTrue, []string{"2 true", "4 true", "6 true"}},
+		{"mixed", []string{"--provider", "local", "--file", "-", "--concurrency", "1"}, "1,2", exitFalse, []string{"1 false", "2 true"}},
+		{"odd", []string{"--provider", "local", "--file", "-", "odd"}, "1\n3", exitTrue, []string{"1 true", "3 true"}},
+		{"invalid number", []string{"--provider", "local", "--file", "-"}, "1\nfour", exitUndefined, nil},
+		{"binary command", []string{"--provider", "local", "--file", "-", "gt"}, "1", exitUndefined, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.args, strings.NewReader(tc.input), &stdout, &stderr)
+			lines := slices.Collect(strings.Lines(stdout.String()))
+			for i := range lines {
+				lines[i] = strings.TrimSuffix(lines[i], "\n")
+			}
+			slices.Sort(lines) // Results arrive in completion order
+			if code != tc.wantCode || !slices.Equal(lines, tc.wantLines) {
+				t.Errorf("run(%q) = %d with output %q; want %d with %q (stderr: %s)", tc.args, code, lines, tc.wantCode, tc.wantLines, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_BatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "numbers.csv")
+	if err := os.WriteFile(path, []byte("10,11\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--provider", "local", "--file", path}, strings.NewReader(""), &stdout, &stderr); code != exitFalse {
+		t.Errorf("Expected exit code %d, got %d (stderr: %s)", exitFalse, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "10 true\n") || !strings.Contains(stdout.String(), "11 false\n") {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// record is the outcome of one query.
+type record struct {
+	Command   string  `json:"command"`
+	Value     int     `json:"value"`
+	Other     *int    `json:"other,omitempty"` // Second number of comparisons
+	Answer    *bool   `json:"answer"`          // null if the answer was undefined
+	Model     string  `json:"model,omitempty"`
+	LatencyMs int64   `json:"latency_ms"`
+	CostUSD   float64 `json:"cost_usd"`
+	Error     string  `json:"error,omitempty"`
+
+	err error
+}
+
+func newRecord(cmd command, nums []int, answer *bool, err error, latency time.Duration, md is_even_ai.CallMetadata) record {
+	rec := record{
+		Command:   cmd.name,
+		Value:     nums[0],
+		Answer:    answer,
+		Model:     md.Model,
+		LatencyMs: latency.Milliseconds(),
+		CostUSD:   md.CostUSD,
+		err:       err,
+	}
+	if len(nums) > 1 {
+		rec.Other = &nums[1]
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return rec
+}
+
+// fields returns the record's values as strings, in the order of recordHeader.
+func (r record) fields() []string {
+	other := ""
+	if r.Other != nil {
+		other = strconv.Itoa(*r.Other)
+	}
+	return []string{
+		r.Command,
+		strconv.Itoa(r.Value),
+		other,
+		answerString(r.Answer),
+		r.Model,
+		strconv.FormatInt(r.LatencyMs, 10),
+		strconv.FormatFloat(r.CostUSD, 'f', -1, 64),
+		r.Error,
+	}
+}
+
+var recordHeader = []string{"command", "value", "other", "answer", "model", "latency_ms", "cost_usd", "error"}
+
+func answerString(res *bool) string {
+	if res == nil {
+		return "undefined"
+	}
+	return strconv.FormatBool(*res)
+}
+
+// recordWriter writes records in one of the output formats.
+type recordWriter interface {
+	Write(rec record) error
+	Flush() error
+}
+
+// newRecordWriter returns a writer for the named output format. batch selects
+// the text format of batch mode, which prefixes each answer with its number.
+func newRecordWriter(format string, batch bool, stdout, stderr io.Writer) (recordWriter, error) {
+	switch format {
+	case "text":
+		return &textWriter{stdout: stdout, stderr: stderr, batch: batch}, nil
+	case "json":
+		return &jsonWriter{enc: json.NewEncoder(stdout)}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(stdout)}, nil
+	case "table":
+		return &tableWriter{w: tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// textWriter prints just the answer, or "N answer" in batch mode. Errors go
+// to stderr.
+type textWriter struct {
+	stdout, stderr io.Writer
+	batch          bool
+}
+
+func (w *textWriter) Write(rec record) error {
+	var err error
+	switch {
+	case rec.err != nil && w.batch:
+		_, err = fmt.Fprintf(w.stderr, "iseven: %d: %v\n", rec.Value, rec.err)
+	case rec.err != nil:
+		_, err = fmt.Fprintf(w.stderr, "iseven: %v\n", rec.err)
+	case w.batch:
+		_, err = fmt.Fprintf(w.stdout, "%d %s\n", rec.Value, answerString(rec.Answer))
+	default:
+		_, err = fmt.Fprintln(w.stdout, answerString(rec.Answer))
+	}
+	return err
+}
+
+func (w *textWriter) Flush() error { return nil }
+
+// jsonWriter prints one JSON object per line.
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonWriter) Write(rec record) error { return w.enc.Encode(rec) }
+
+func (w *jsonWriter) Flush() error { return nil }
+
+// csvWriter prints a header followed by one row per record. Rows are flushed
+// as they are written so that batch results stream.
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (w *csvWriter) Write(rec record) error {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if err := w.w.Write(recordHeader); err != nil {
+			return err
+		}
+	}
+	if err := w.w.Write(rec.fields()); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// tableWriter prints aligned columns for humans. Columns can only be aligned
+// once all rows are known, so nothing is printed before Flush.
+type tableWriter struct {
+	w           *tabwriter.Writer
+	wroteHeader bool
+}
+
+func (w *tableWriter) Write(rec record) error {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if _, err := fmt.Fprintln(w.w, strings.Join(recordHeader, "\t")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w.w, strings.Join(rec.fields(), "\t"))
+	return err
+}
+
+func (w *tableWriter) Flush() error { return w.w.Flush() }
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func testRecords() []record {
+	yes := true
+	md := is_even_ai.CallMetadata{Model: "test-model", Usage: is_even_ai.Usage{CostUSD: 0.000025}}
+	return []record{
+		newRecord(commands["gt"], []int{8, 7}, &yes, nil, 120*time.Millisecond, md),
+		newRecord(commands["even"], []int{3}, nil, errors.New("boom"), time.Second, is_even_ai.CallMetadata{}),
+	}
+}
+
+func TestRecordWriter(t *testing.T) {
+	testCases := []struct {
+		format     string
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			format:     "text",
+			wantStdout: "true\n",
+			wantStderr: "iseven: boom\n",
+		},
+		{
+			format: "json",
+			wantStdout: `{"command":"gt","value":8,"other":7,"answer":true,"model":"test-model","latency_ms":120,"cost_usd":0.000025}` + "\n" +
+				`{"command":"even","value":3,"answer":null,"latency_ms":1000,"cost_usd":0,"error":"boom"}` + "\n",
+		},
+		{
+			format: "csv",
+			wantStdout: "command,value,other,answer,model,latency_ms,cost_usd,error\n" +
+				"gt,8,7,true,test-model,120,0.000025,\n" +
+				"even,3,,undefined,,1000,0,boom\n",
+		},
+		{
+			format: "table",
+			wantStdout: "command  value  other  answer     model       latency_ms  cost_usd  error\n" +
+				"gt       8      7      true       test-model  120         0.000025  \n" +
+				"even     3             undefined              1000        0         boom\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			w, err := newRecordWriter(tc.format, false, &stdout, &stderr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, rec := range testRecords() {
+				if err := w.Write(rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if stdout.String() != tc.wantStdout {
+				t.Errorf("stdout =\n%s\nwant\n%s", stdout.String(), tc.wantStdout)
+			}
+			if stderr.String() != tc.wantStderr {
+				t.Errorf("stderr = %q; want %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRecordWriter_TextBatch(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	w, _ := newRecordWriter("text", true, &stdout, &stderr)
+	for _, rec := range testRecords() {
+		_ = w.Write(rec)
+	}
+	if stdout.String() != "8 true\n" || stderr.String() != "iseven: 3: boom\n" {
+		t.Errorf("Unexpected batch text output %q, %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestRecordWriter_UnknownFormat(t *testing.T) {
+	if _, err := newRecordWriter("xml", false, nil, nil); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestRun_JSONOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--provider", "local", "--output", "json", "--file", "-"}, strings.NewReader("1\n2\n"), &stdout, &stderr)
+	if code != exitFalse {
+		t.Errorf("Expected exit code %d, got %d (stderr: %s)", exitFalse, code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %q", stdout.String())
+	}
+	for _, line := range lines {
+		var rec record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", line, err)
+		}
+		if rec.Command != "even" || rec.Model != "local" || rec.Answer == nil || *rec.Answer != (rec.Value%2 == 0) {
+			t.Errorf("Unexpected record %+v", rec)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Command isevend serves the is-even-ai predicates over HTTP, so services
+// written in other languages can use them. See package httpapi for the API.
+//
+// Clients authenticate with
+// "Authorization: Bearer KEY" or an "X-API-Key" header if ISEVEND_API_KEYS
+// holds a comma separated list of accepted keys; otherwise the API is open.
+// Answers are cached in memory and requests are rate limited per API key.
+//
+// With --tenants, a JSON file configures several tenants sharing the daemon,
+// each with its own API keys, rate limit and budget, replacing
+// ISEVEND_API_KEYS and the --rate and --burst limits. The debug stats then break requests, errors and spending
+// down by tenant:
+//
+//	{"team-a": {"api_keys": ["KEY"], "rate": 5, "burst": 10, "max_cost_usd": 1, "budget_window": "24h"}}
+//
+// If ISEVEND_ADMIN_KEYS holds a comma separated list of admin keys, the
+// admin routes of httpapi.NewAdminHandler are served under /admin/, to
+// inspect and flush the cache, view spending, switch degraded mode (only
+// cached answers are served) and reload the prompt templates.
+//
+// With --debug-addr, a separate listener serves expvar on /debug/vars and the
+// query, cache, token and budget counters on /debug/iseven. Keep it private.
+//
+// The gemini provider reads the API key from GEMINI_API_KEY.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/httpapi"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	providerName := flag.String("provider", "gemini", "AI provider: gemini or local")
+	model := flag.String("model", "", "model to use (default: the provider's default)")
+	cache := flag.Bool("cache", true, "cache answers in memory")
+	undefinedTTL := flag.Duration("undefined-ttl", time.Minute, "how long to cache undefined answers (0 to disable)")
+	rateLimit := flag.Float64("rate", 10, "requests per second allowed per API key (0 for unlimited, ignored with --tenants)")
+	burst := flag.Int("burst", 20, "request burst allowed per API key (ignored with --tenants)")
+	maxBatch := flag.Int("max-batch", 1000, "maximum numbers per batch request")
+	concurrency := flag.Int("concurrency", 8, "maximum parallel queries per batch request")
+	maxInFlight := flag.Int("max-in-flight", 0, "maximum queries sent to the provider at once (0 for unlimited)")
+	maxQueued := flag.Int("max-queued", 1000, "maximum queries waiting when --max-in-flight is reached")
+	debugAddr := flag.String("debug-addr", "", "address to serve debug stats on (disabled if empty)")
+	tenantsFile := flag.String("tenants", "", "JSON file configuring tenants (disabled if empty)")
+	flag.Parse()
+
+	logger := slog.Default()
+	c, err := newClient(*providerName, *model, logger)
+	if err != nil {
+		logger.Error("failed to create client", "err", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+	stats := &is_even_ai.Stats{
+		Usage:            c.usage,
+		UsageByPredicate: c.usageByPredicate,
+		Budget:           c.budget,
+		LabelNames:       []string{"tenant"},
+		UsageByLabel:     c.usageByLabel,
+	}
+	c.Use(stats.Middleware())
+	var tenants *is_even_ai.Tenants
+	if *tenantsFile != "" {
+		configs, err := readTenants(*tenantsFile)
+		if err != nil {
+			logger.Error("failed to read tenants", "err", err)
+			os.Exit(1)
+		}
+		tenants = is_even_ai.NewTenants(configs)
+		// Before the cache, so that cached answers count against the rate
+		// limits too.
+		c.Use(tenants.Middleware())
+	}
+	var cacheLen func() int
+	if *cache {
+		memCache := is_even_ai.NewMemoryCache()
+		c.Use(is_even_ai.CacheMiddlewareWithOptions(stats.Cache(memCache), is_even_ai.CacheOptions{
+			UndefinedTTL: *undefinedTTL,
+		}))
+		c.RegisterCache(memCache)
+		cacheLen = memCache.Len
+	}
+	degraded := &is_even_ai.DegradedMode{}
+	c.Use(degraded.Middleware())
+	if *maxInFlight > 0 {
+		// Behind the cache, so that cached answers don't wait for a slot.
+		c.Use(is_even_ai.NewAdmissionControl(*maxInFlight, *maxQueued).Middleware())
+	}
+	stats.Publish("isevenai")
+	if *debugAddr != "" {
+		go serveDebug(*debugAddr, stats, logger)
+	}
+
+	limit := rate.Limit(*rateLimit)
+	if *rateLimit <= 0 {
+		limit = rate.Inf
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", httpapi.NewHandler(c.IsEvenAiCore, httpapi.Options{
+		APIKeys:     splitKeys(os.Getenv("ISEVEND_API_KEYS")),
+		RateLimit:   limit,
+		Burst:       *burst,
+		MaxBatch:    *maxBatch,
+		Concurrency: *concurrency,
+		Tenants:     tenants,
+		Logger:      logger,
+	}))
+	if adminKeys := splitKeys(os.Getenv("ISEVEND_ADMIN_KEYS")); len(adminKeys) > 0 {
+		mux.Handle("/admin/", httpapi.NewAdminHandler(c.IsEvenAiCore, httpapi.AdminOptions{
+			APIKeys:  adminKeys,
+			Stats:    stats,
+			CacheLen: cacheLen,
+			Degraded: degraded,
+			Logger:   logger,
+		}))
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("shutdown failed", "err", err)
+		}
+	}()
+
+	logger.Info("isevend listening", "addr", *addr, "provider", *providerName)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// serveDebug serves expvar and stats on addr.
+func serveDebug(addr string, stats *is_even_ai.Stats, logger *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.Handle("GET /debug/iseven", stats)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	logger.Info("isevend debug endpoint listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil {
+		logger.Error("debug server failed", "err", err)
+	}
+}
+
+// client is a provider's core, which allows middleware and per-call contexts,
+// together with the provider's Close method.
+type client struct {
+	*is_even_ai.IsEvenAiCore
+	io.Closer
+	// usage, usageByPredicate, usageByLabel and budget report the provider's
+	// spending, if it tracks any.
+	usage            func() is_even_ai.Usage
+	usageByPredicate func() map[string]is_even_ai.Usage
+	usageByLabel     func(name string) map[string]is_even_ai.Usage
+	budget           func() is_even_ai.BudgetStatus
+}
+
+// newClient creates a client for the named provider.
+func newClient(name, model string, logger *slog.Logger) (client, error) {
+	switch name {
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return client{}, errors.New("GEMINI_API_KEY environment variable not set")
+		}
+		ai, err := is_even_ai.NewIsEvenAiGemini(
+			is_even_ai.GeminiClientOptions{APIKey: apiKey, Logger: logger},
+			is_even_ai.GeminiModelOptions{Model: model},
+		)
+		if err != nil {
+			return client{}, err
+		}
+		return client{ai.IsEvenAiCore, ai, ai.Usage, ai.UsageByPredicate, ai.UsageByLabel, ai.BudgetStatus}, nil
+	case "local":
+		local := is_even_ai.NewIsEvenAiLocal()
+		return client{IsEvenAiCore: local.IsEvenAiCore, Closer: local}, nil
+	default:
+		return client{}, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+// tenantConfig is the configuration of a tenant in the --tenants file.
+type tenantConfig struct {
+	APIKeys      []string `json:"api_keys"`
+	Rate         float64  `json:"rate"` // Requests per second, 0 for unlimited
+	Burst        int      `json:"burst"`
+	MaxCostUSD   float64  `json:"max_cost_usd"`
+	MaxTokens    int64    `json:"max_tokens"`
+	BudgetWindow string   `json:"budget_window"` // A time.Duration, lifetime if empty
+}
+
+// readTenants reads the tenant configurations in the JSON file at path.
+func readTenants(path string) (map[string]is_even_ai.TenantConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file map[string]tenantConfig
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	configs := map[string]is_even_ai.TenantConfig{}
+	for id, t := range file {
+		if len(t.APIKeys) == 0 {
+			return nil, fmt.Errorf("%s: tenant %q has no API keys", path, id)
+		}
+		var window time.Duration
+		if t.BudgetWindow != "" {
+			if window, err = time.ParseDuration(t.BudgetWindow); err != nil {
+				return nil, fmt.Errorf("%s: tenant %q: %w", path, id, err)
+			}
+		}
+		configs[id] = is_even_ai.TenantConfig{
+			APIKeys:   t.APIKeys,
+			RateLimit: rate.Limit(t.Rate),
+			Burst:     t.Burst,
+			Budget:    is_even_ai.Budget{MaxCostUSD: t.MaxCostUSD, MaxTokens: t.MaxTokens, Window: window},
+		}
+	}
+	return configs, nil
+}
+
+// splitKeys splits a comma separated list of API keys, ignoring blanks.
+func splitKeys(s string) []string {
+	var keys []string
+	for key := range strings.SplitSeq(s, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitKeys(t *testing.T) {
+	if got := splitKeys(" a, ,b,"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("splitKeys = %q; want [a b]", got)
+	}
+}
+
+func TestReadTenants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"a": {"api_keys": ["k1"], "rate": 5, "burst": 10, "max_cost_usd": 1.5, "budget_window": "24h"}, "b": {"api_keys": ["k2"]}}`)
+	configs, err := readTenants(path)
+	if err != nil {
+		t.Fatalf("readTenants() failed: %v", err)
+	}
+	a := configs["a"]
+	if len(configs) != 2 || a.RateLimit != 5 || a.Burst != 10 || a.Budget.MaxCostUSD != 1.5 || a.Budget.Window != 24*time.Hour {
+		t.Errorf("readTenants() = %+v", configs)
+	}
+
+	for _, content := range []string{`{"a": {}}`, `{"a": {"api_keys": ["k"], "budget_window": "daily"}}`, `[]`} {
+		write(content)
+		if _, err := readTenants(path); err == nil {
+			t.Errorf("readTenants(%s) succeeded, want an error", content)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// complements maps predicates to the predicate that must give the opposite
+// answer about the same arguments.
+var complements = map[string]string{
+	"isEven":      "isOdd",
+	"isOdd":       "isEven",
+	"areEqual":    "areNotEqual",
+	"areNotEqual": "areEqual",
+}
+
+// ConsistencyChecker asks a sample of questions a second time in complementary
+// form (IsOdd when IsEven was asked, AreNotEqual for AreEqual and vice versa)
+// and flags contradictions, i.e. both answers true or both false. Derived
+// answers, such as IsOdd without its own template, assume the model is
+// consistent; the checker finds out whether it is. Enable it with Middleware:
+//
+//	checker := &is_even_ai.ConsistencyChecker{SampleRate: 0.01, OnContradiction: report}
+//	ai.Use(checker.Middleware())
+//
+// Questions are only checked if the complement has its own template, and if
+// both answers are defined. With a TieBreaker, contradictions are resolved
+// instead of surfacing to every caller. Complements are asked through the
+// middleware after the checker, so put it before caches that should serve
+// them. The zero value checks nothing. ConsistencyChecker is safe for
+// concurrent use.
+type ConsistencyChecker struct {
+	// SampleRate is the fraction of questions, between 0 and 1, that are checked.
+	SampleRate float64
+	// TieBreaker, if set, is asked the question again when its answers
+	// contradict each other, e.g. a stronger model or the same model with
+	// differently phrased PromptTemplates. Its defined answer is returned
+	// instead, and CallMetadata.Resolved is set.
+	TieBreaker Provider
+	// FailOnContradiction makes checked questions whose answers contradict
+	// each other, and are not resolved by TieBreaker, fail with an error
+	// wrapping ErrContradiction.
+	FailOnContradiction bool
+	// OnContradiction, if set, is called with every contradiction found. It
+	// runs synchronously in the query, so it should not block for long.
+	OnContradiction func(Contradiction)
+	// Rand is the source of randomness for sampling.
+	// Optional: defaults to a randomly seeded source.
+	Rand *rand.Rand
+
+	mu             sync.Mutex // Guards Rand, which is not safe for concurrent use, and the counts
+	checked        int64
+	contradictions int64
+	resolved       int64
+}
+
+// Contradiction is a question and its complement that were both answered
+// true, or both false.
+type Contradiction struct {
+	Question   CallInfo
+	Answer     bool
+	Complement CallInfo
+	// Resolved is the TieBreaker's answer to Question, or nil if there is no
+	// TieBreaker or it failed to answer.
+	Resolved *bool
+}
+
+func (c Contradiction) String() string {
+	return fmt.Sprintf("%s%v and %s%v both answered %t", c.Question.Predicate, c.Question.Args, c.Complement.Predicate, c.Complement.Args, c.Answer)
+}
+
+// ConsistencyCounts are the totals of a ConsistencyChecker.
+type ConsistencyCounts struct {
+	Checked        int64 `json:"checked"`        // Questions asked in complementary form
+	Contradictions int64 `json:"contradictions"` // Of those, answered inconsistently
+	Resolved       int64 `json:"resolved"`       // Contradictions resolved by the TieBreaker
+}
+
+// Middleware checks a sample of the questions passing through it.
+func (c *ConsistencyChecker) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			res, err := next(ctx, prompt)
+			if err != nil || res == nil || !c.sample() {
+				return res, err
+			}
+			info, ok := CallInfoFromContext(ctx)
+			complement, hasComplement := complements[info.Predicate]
+			prompts := core.PromptsFromContext(ctx)
+			if !ok || !hasComplement || prompts == nil {
+				return res, err
+			}
+			complementPrompt, promptErr := prompts(complement, info.Args...)
+			if promptErr != nil || complementPrompt == "" {
+				return res, err // No template of its own, so the complement would be derived
+			}
+			complementInfo := CallInfo{Predicate: complement, Args: info.Args, Source: info.Source}
+			other, otherErr := next(core.ContextWithCallInfo(ctx, complementInfo), complementPrompt)
+			if otherErr != nil || other == nil {
+				return res, err
+			}
+
+			c.mu.Lock()
+			c.checked++
+			if *other == *res {
+				c.contradictions++
+			}
+			c.mu.Unlock()
+			if *other != *res {
+				return res, err
+			}
+			contradiction := Contradiction{Question: info, Answer: *res, Complement: complementInfo}
+			if c.TieBreaker != nil {
+				contradiction.Resolved = c.tieBreak(ctx, info)
+			}
+			if c.OnContradiction != nil {
+				c.OnContradiction(contradiction)
+			}
+			if contradiction.Resolved != nil {
+				return contradiction.Resolved, nil
+			}
+			if c.FailOnContradiction {
+				return nil, fmt.Errorf("%w: %s", ErrContradiction, contradiction)
+			}
+			return res, err
+		}
+	}
+}
+
+// contextProvider is implemented by providers built on IsEvenAiCore, whose
+// queries can use a context.
+type contextProvider interface {
+	WithContext(ctx context.Context) *IsEvenAiCore
+}
+
+// tieBreak asks the TieBreaker the question in info and returns its answer,
+// or nil if it failed to give a defined one.
+func (c *ConsistencyChecker) tieBreak(ctx context.Context, info CallInfo) *bool {
+	var tieBreaker predicateAsker = c.TieBreaker
+	if p, ok := tieBreaker.(contextProvider); ok {
+		tieBreaker = p.WithContext(ctx) // Reports its usage to CallMetadata
+	}
+	res, err := askProvider(tieBreaker, info.Predicate, info.Args)
+	if err != nil || res == nil {
+		return nil
+	}
+	c.mu.Lock()
+	c.resolved++
+	c.mu.Unlock()
+	if md := core.CallMetadataFromContext(ctx); md != nil {
+		md.Resolved = true
+	}
+	return res
+}
+
+// sample reports whether to check a question.
+func (c *ConsistencyChecker) sample() bool {
+	if c.SampleRate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Rand == nil {
+		c.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return c.Rand.Float64() < c.SampleRate
+}
+
+// Counts returns the number of checked questions and contradictions found.
+func (c *ConsistencyChecker) Counts() ConsistencyCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ConsistencyCounts{Checked: c.checked, Contradictions: c.contradictions, Resolved: c.resolved}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+// promptLogHandler answers every prompt with answer(prompt) and logs the prompts.
+func promptLogHandler(prompts *[]string, answer func(prompt string) *bool) QueryHandler {
+	return func(_ context.Context, prompt string) (*bool, error) {
+		*prompts = append(*prompts, prompt)
+		return answer(prompt), nil
+	}
+}
+
+func TestConsistencyChecker(t *testing.T) {
+	yes := true
+	var prompts []string
+	core := newIsEvenAiCore(testPromptTemplates, promptLogHandler(&prompts, func(string) *bool { return &yes }))
+	var contradictions []Contradiction
+	checker := &ConsistencyChecker{SampleRate: 1, OnContradiction: func(c Contradiction) { contradictions = append(contradictions, c) }}
+	core.Use(checker.Middleware())
+
+	res, err := core.IsEven(3)
+	if err != nil || res == nil || !*res {
+		t.Errorf("Expected the original answer to be returned, got %v, %v", res, err)
+	}
+	if got := strings.Join(prompts, ","); got != "isEven 3,isOdd 3" {
+		t.Errorf("Expected the complement to be asked, got prompts %s", got)
+	}
+	if len(contradictions) != 1 || contradictions[0].String() != "isEven[3] and isOdd[3] both answered true" {
+		t.Errorf("Unexpected contradictions %v", contradictions)
+	}
+
+	prompts = nil
+	_, _ = core.IsGreaterThan(1, 2)
+	if len(prompts) != 1 {
+		t.Errorf("Expected predicates without a complement not to be checked, got prompts %v", prompts)
+	}
+	if c := checker.Counts(); c.Checked != 1 || c.Contradictions != 1 {
+		t.Errorf("Unexpected counts %+v", c)
+	}
+}
+
+func TestConsistencyChecker_Consistent(t *testing.T) {
+	var prompts []string
+	core := newIsEvenAiCore(testPromptTemplates, promptLogHandler(&prompts, func(prompt string) *bool {
+		v := strings.HasPrefix(prompt, "areNotEqual")
+		return &v
+	}))
+	checker := &ConsistencyChecker{SampleRate: 1, FailOnContradiction: true}
+	core.Use(checker.Middleware())
+
+	if res, err := core.AreEqual(1, 2); err != nil || res == nil || *res {
+		t.Errorf("AreEqual(1, 2) = %v, %v; want false", res, err)
+	}
+	if c := checker.Counts(); c.Checked != 1 || c.Contradictions != 0 {
+		t.Errorf("Unexpected counts %+v", c)
+	}
+}
+
+func TestConsistencyChecker_FailOnContradiction(t *testing.T) {
+	no := false
+	var prompts []string
+	core := newIsEvenAiCore(testPromptTemplates, promptLogHandler(&prompts, func(string) *bool { return &no }))
+	core.Use((&ConsistencyChecker{SampleRate: 1, FailOnContradiction: true}).Middleware())
+	if _, err := core.IsOdd(4); !errors.Is(err, ErrContradiction) {
+		t.Errorf("Expected ErrContradiction, got %v", err)
+	}
+}
+
+func TestConsistencyChecker_DerivedComplement(t *testing.T) {
+	yes := true
+	var prompts []string
+	templates := testPromptTemplates
+	templates.IsOdd = nil
+	core := newIsEvenAiCore(templates, promptLogHandler(&prompts, func(string) *bool { return &yes }))
+	checker := &ConsistencyChecker{SampleRate: 1}
+	core.Use(checker.Middleware())
+	_, _ = core.IsEven(3)
+	if len(prompts) != 1 || checker.Counts().Checked != 0 {
+		t.Errorf("Expected no check without an IsOdd template, got prompts %v", prompts)
+	}
+}
+
+func TestConsistencyChecker_ZeroValue(t *testing.T) {
+	var prompts []string
+	core := newIsEvenAiCore(testPromptTemplates, promptLogHandler(&prompts, func(string) *bool { return nil }))
+	core.Use((&ConsistencyChecker{}).Middleware())
+	_, _ = core.IsEven(3)
+	if len(prompts) != 1 {
+		t.Errorf("Expected the zero value to check nothing, got prompts %v", prompts)
+	}
+}
+
+func TestConsistencyChecker_TieBreaker(t *testing.T) {
+	yes := true
+	var prompts []string
+	core := newIsEvenAiCore(testPromptTemplates, promptLogHandler(&prompts, func(string) *bool { return &yes }))
+	tieBreaker := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "false")
+	})
+	var contradictions []Contradiction
+	checker := &ConsistencyChecker{SampleRate: 1, TieBreaker: tieBreaker, FailOnContradiction: true,
+		OnContradiction: func(c Contradiction) { contradictions = append(contradictions, c) }}
+	core.Use(checker.Middleware())
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := core.WithContext(ctx).IsEven(3)
+	if err != nil || res == nil || *res {
+		t.Errorf("Expected the tie-breaker's answer false, got %v, %v", res, err)
+	}
+	if !md.Resolved || md.Requests != 1 {
+		t.Errorf("Expected metadata to report the resolution and the tie-breaker's request, got %+v", *md)
+	}
+	if len(contradictions) != 1 || contradictions[0].Resolved == nil || *contradictions[0].Resolved {
+		t.Errorf("Expected OnContradiction to see the resolved answer, got %+v", contradictions)
+	}
+	if c := checker.Counts(); c.Resolved != 1 {
+		t.Errorf("Unexpected counts %+v", c)
+	}
+
+	// An undefined tie-break leaves the contradiction unresolved.
+	checker.TieBreaker = &stubProvider{}
+	if _, err := core.IsEven(3); !errors.Is(err, ErrContradiction) {
+		t.Errorf("Expected ErrContradiction for an unresolved contradiction, got %v", err)
+	}
+}
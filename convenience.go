@@ -8,7 +8,7 @@ package is_even_ai
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 )
 
@@ -16,8 +16,27 @@ var (
 	globalGeminiInstance *IsEvenAiGemini
 	globalMu             sync.Mutex
 	apiKeyIsSet          bool
+	globalLogger         *slog.Logger
 )
 
+// SetLogger sets the logger used by the convenience functions and passed to the
+// global Gemini instance by subsequent calls to SetAPIKey. By default, the
+// global instance does not log and errors are reported to slog.Default().
+func SetLogger(logger *slog.Logger) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalLogger = logger
+}
+
+// convenienceLogger returns the logger for the convenience functions' own messages.
+// globalMu must be held.
+func convenienceLogger() *slog.Logger {
+	if globalLogger != nil {
+		return globalLogger
+	}
+	return slog.Default()
+}
+
 // SetAPIKey configures the global Gemini client instance with the provided API key.
 // It must be called before using the convenience functions.
 // Additional GeminiModelOptions can be provided to customize model, temperature, etc.
@@ -29,14 +48,14 @@ func SetAPIKey(apiKey string, modelOpts ...GeminiModelOptions) error {
 		apiKeyIsSet = false
 		if globalGeminiInstance != nil {
 			if err := globalGeminiInstance.Close(); err != nil {
-				log.Printf("Error closing previous globalGeminiInstance: %v", err)
+				convenienceLogger().Error("failed to close previous global Gemini instance", "err", err)
 			}
 		}
 		globalGeminiInstance = nil
 		return errors.New("API key cannot be empty")
 	}
 
-	clientOptions := GeminiClientOptions{APIKey: apiKey}
+	clientOptions := GeminiClientOptions{APIKey: apiKey, Logger: globalLogger}
 
 	var mo GeminiModelOptions
 	if len(modelOpts) > 0 {
@@ -48,7 +67,7 @@ func SetAPIKey(apiKey string, modelOpts ...GeminiModelOptions) error {
 		apiKeyIsSet = false
 		if globalGeminiInstance != nil {
 			if errClose := globalGeminiInstance.Close(); errClose != nil {
-				log.Printf("Error closing existing globalGeminiInstance on failure: %v", errClose)
+				convenienceLogger().Error("failed to close global Gemini instance after initialization failure", "err", errClose)
 			}
 		}
 		globalGeminiInstance = nil
@@ -56,7 +75,7 @@ func SetAPIKey(apiKey string, modelOpts ...GeminiModelOptions) error {
 	}
 	if globalGeminiInstance != nil {
 		if errClose := globalGeminiInstance.Close(); errClose != nil {
-			log.Printf("Error closing previous globalGeminiInstance before new assignment: %v", errClose)
+			convenienceLogger().Error("failed to close previous global Gemini instance", "err", errClose)
 		}
 	}
 	globalGeminiInstance = instance
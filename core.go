@@ -4,214 +4,108 @@
 // found in the LICENSE file or at https://opensource.org/licenses/MIT.
 
 // Package is_even_ai checks if a number is even using the power of ✨AI✨ with Google Gemini.
+//
+// IsEvenAiCore, its templates, the answer parser and the result types are
+// defined in the core package, a module without dependencies outside the
+// standard library for building custom providers. They are available here
+// under the same names.
 package is_even_ai
 
 import (
-	"errors"
-	"fmt"
+	"context"
+
+	"github.com/philwo/is-even-ai/core"
 )
 
-// PromptTemplate1 defines a function that takes one integer argument and returns a string prompt.
-type PromptTemplate1 func(n int) string
+// Types of the core module, see the core package.
+type (
+	PromptTemplate1             = core.PromptTemplate1
+	PromptTemplate2             = core.PromptTemplate2
+	IsEvenAiCorePromptTemplates = core.IsEvenAiCorePromptTemplates
+	QueryFunc                   = core.QueryFunc
+	QueryHandler                = core.QueryHandler
+	QueryFuncV2                 = core.QueryFuncV2
+	Result                      = core.Result
+	Middleware                  = core.Middleware
+	CallInfo                    = core.CallInfo
+	AnswerSource                = core.AnswerSource
+	IsEvenAiCore                = core.IsEvenAiCore
+	Answer                      = core.Answer
+	CallMetadata                = core.CallMetadata
+	Usage                       = core.Usage
+	PredicateAccuracy           = core.PredicateAccuracy
+	Offender                    = core.Offender
+	AccuracyReport              = core.AccuracyReport
+	CacheInvalidator            = core.CacheInvalidator
+)
 
-// PromptTemplate2 defines a function that takes two integer arguments and returns a string prompt.
-type PromptTemplate2 func(a, b int) string
+// Answers, see core.Answer.
+const (
+	AnswerUndefined = core.AnswerUndefined
+	AnswerTrue      = core.AnswerTrue
+	AnswerFalse     = core.AnswerFalse
+)
 
-// IsEvenAiCorePromptTemplates holds the templates for generating prompts.
-//   - IsEven, AreEqual, IsGreaterThan are mandatory.
-//   - IsOdd, AreNotEqual, IsLessThan are optional. If a template for an optional
-//     operation is nil, the corresponding method will use a fallback strategy
-//     (e.g., IsOdd will be derived from !IsEven).
-//
-// All prompt template functions are synchronous and return a string.
-type IsEvenAiCorePromptTemplates struct {
-	IsEven        PromptTemplate1
-	IsOdd         PromptTemplate1 // Optional: if nil, IsOdd will be derived from !IsEven
-	AreEqual      PromptTemplate2
-	AreNotEqual   PromptTemplate2 // Optional: if nil, AreNotEqual will be derived from !AreEqual
-	IsGreaterThan PromptTemplate2
-	IsLessThan    PromptTemplate2 // Optional: if nil, IsLessThan will be derived from !IsGreaterThan(b,a)
+// NewIsEvenAiCore creates a new instance of IsEvenAiCore, see core.NewIsEvenAiCore.
+func NewIsEvenAiCore(templates IsEvenAiCorePromptTemplates, query QueryFunc) *IsEvenAiCore {
+	return core.NewIsEvenAiCore(templates, query)
 }
 
-// QueryFunc defines a function that takes a prompt string, queries an AI model,
-// and returns a boolean result or an error. The *bool type allows for true, false,
-// or nil (representing an undefined or indeterminate answer from the AI).
-type QueryFunc func(prompt string) (result *bool, err error)
+// NewIsEvenAiCoreChecked is like NewIsEvenAiCore, but returns an error instead
+// of panicking, see core.NewIsEvenAiCoreChecked.
+func NewIsEvenAiCoreChecked(templates IsEvenAiCorePromptTemplates, query QueryFunc) (*IsEvenAiCore, error) {
+	return core.NewIsEvenAiCoreChecked(templates, query)
+}
 
-// IsEvenAiCore provides the core functionality for querying number properties using AI.
-type IsEvenAiCore struct {
-	promptTemplates IsEvenAiCorePromptTemplates
-	query           QueryFunc
+// NewIsEvenAiCoreV2 is like NewIsEvenAiCore for a QueryFuncV2, see core.NewIsEvenAiCoreV2.
+func NewIsEvenAiCoreV2(templates IsEvenAiCorePromptTemplates, query QueryFuncV2) *IsEvenAiCore {
+	return core.NewIsEvenAiCoreV2(templates, query)
 }
 
-// NewIsEvenAiCore creates a new instance of IsEvenAiCore.
-// It requires a set of prompt templates and a query function to interact with an AI.
-func NewIsEvenAiCore(templates IsEvenAiCorePromptTemplates, query QueryFunc) *IsEvenAiCore {
-	if query == nil {
-		panic("query function cannot be nil") // Or return an error
-	}
-	return &IsEvenAiCore{
-		promptTemplates: templates,
-		query:           query,
-	}
+// AdaptQueryFunc converts a v1 QueryFunc to a QueryFuncV2, see core.AdaptQueryFunc.
+func AdaptQueryFunc(query QueryFunc) QueryFuncV2 {
+	return core.AdaptQueryFunc(query)
+}
+
+// AdaptQueryHandler converts a QueryHandler to a QueryFuncV2, see core.AdaptQueryHandler.
+func AdaptQueryHandler(query QueryHandler) QueryFuncV2 {
+	return core.AdaptQueryHandler(query)
 }
 
-// getPrompt retrieves and formats a prompt string based on the prompt name and arguments.
-// For optional templates that are not provided, it returns an empty string and no error.
-func (c *IsEvenAiCore) getPrompt(promptName string, args ...int) (string, error) {
-	switch promptName {
-	case "isEven":
-		if c.promptTemplates.IsEven == nil {
-			return "", errors.New("isEven prompt template is mandatory and not defined")
-		}
-		if len(args) < 1 {
-			return "", errors.New("not enough arguments for isEven prompt")
-		}
-		return c.promptTemplates.IsEven(args[0]), nil
-	case "isOdd":
-		if c.promptTemplates.IsOdd == nil {
-			return "", nil // Optional, return empty string if not defined
-		}
-		if len(args) < 1 {
-			return "", errors.New("not enough arguments for isOdd prompt")
-		}
-		return c.promptTemplates.IsOdd(args[0]), nil
-	case "areEqual":
-		if c.promptTemplates.AreEqual == nil {
-			return "", errors.New("areEqual prompt template is mandatory and not defined")
-		}
-		if len(args) < 2 {
-			return "", errors.New("not enough arguments for areEqual prompt")
-		}
-		return c.promptTemplates.AreEqual(args[0], args[1]), nil
-	case "areNotEqual":
-		if c.promptTemplates.AreNotEqual == nil {
-			return "", nil // Optional
-		}
-		if len(args) < 2 {
-			return "", errors.New("not enough arguments for areNotEqual prompt")
-		}
-		return c.promptTemplates.AreNotEqual(args[0], args[1]), nil
-	case "isGreaterThan":
-		if c.promptTemplates.IsGreaterThan == nil {
-			return "", errors.New("isGreaterThan prompt template is mandatory and not defined")
-		}
-		if len(args) < 2 {
-			return "", errors.New("not enough arguments for isGreaterThan prompt")
-		}
-		return c.promptTemplates.IsGreaterThan(args[0], args[1]), nil
-	case "isLessThan":
-		if c.promptTemplates.IsLessThan == nil {
-			return "", nil // Optional
-		}
-		if len(args) < 2 {
-			return "", errors.New("not enough arguments for isLessThan prompt")
-		}
-		return c.promptTemplates.IsLessThan(args[0], args[1]), nil
-	default:
-		return "", fmt.Errorf("unknown prompt name: %s", promptName)
-	}
+// Chain combines middleware into one. The first middleware is the outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return core.Chain(middleware...)
 }
 
-// IsEven checks if a number 'n' is even.
-// Returns a pointer to boolean (*bool) and an error.
-// *bool can be true, false, or nil (if the AI's response is undefined).
-func (c *IsEvenAiCore) IsEven(n int) (*bool, error) {
-	prompt, err := c.getPrompt("isEven", n)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prompt for IsEven: %w", err)
-	}
-	return c.query(prompt)
+// HashSystemPrompt returns a short hash of a system prompt for AnswerSource.
+func HashSystemPrompt(prompt string) string {
+	return core.HashSystemPrompt(prompt)
 }
 
-// IsOdd checks if a number 'n' is odd.
-// If an 'isOdd' prompt template is not provided, it derives the result by negating IsEven(n).
-func (c *IsEvenAiCore) IsOdd(n int) (*bool, error) {
-	prompt, err := c.getPrompt("isOdd", n)
-	if err != nil {
-		// This error means getPrompt failed (e.g., not enough args for a defined template,
-		// or a misconfiguration). It should not proceed to fallback.
-		return nil, fmt.Errorf("failed to get prompt for IsOdd: %w", err)
-	}
-
-	if prompt != "" { // Template was provided and prompt generated successfully
-		return c.query(prompt)
-	}
-
-	// Fallback: template was optional and not provided (i.e., prompt == "" and err == nil from getPrompt)
-	isEvenResult, err := c.IsEven(n)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine IsOdd by inverting IsEven: %w", err)
-	}
-	if isEvenResult == nil { // IsEven returned undefined
-		return nil, nil
-	}
-	res := !(*isEvenResult)
-	return &res, nil
+// CallInfoFromContext returns the CallInfo stored in ctx by IsEvenAiCore.
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	return core.CallInfoFromContext(ctx)
 }
 
-// AreEqual checks if numbers 'a' and 'b' are equal.
-func (c *IsEvenAiCore) AreEqual(a, b int) (*bool, error) {
-	prompt, err := c.getPrompt("areEqual", a, b)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prompt for AreEqual: %w", err)
-	}
-	return c.query(prompt)
+// WithOnToken returns a context whose queries stream the model output to
+// onToken while it is generated, see core.WithOnToken.
+func WithOnToken(ctx context.Context, onToken func(chunk string)) context.Context {
+	return core.WithOnToken(ctx, onToken)
 }
 
-// AreNotEqual checks if numbers 'a' and 'b' are not equal.
-// If an 'areNotEqual' prompt template is not provided, it derives the result by negating AreEqual(a,b).
-func (c *IsEvenAiCore) AreNotEqual(a, b int) (*bool, error) {
-	prompt, err := c.getPrompt("areNotEqual", a, b)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prompt for AreNotEqual: %w", err)
-	}
-
-	if prompt != "" { // Template was provided and prompt generated successfully
-		return c.query(prompt)
-	}
-
-	// Fallback: template was optional and not provided
-	areEqualResult, err := c.AreEqual(a, b)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine AreNotEqual by inverting AreEqual: %w", err)
-	}
-	if areEqualResult == nil { // AreEqual returned undefined
-		return nil, nil
-	}
-	res := !(*areEqualResult)
-	return &res, nil
+// ParseAnswer interprets the text of a model answer, see core.ParseAnswer.
+func ParseAnswer(text string) (Answer, error) {
+	return core.ParseAnswer(text)
 }
 
-// IsGreaterThan checks if number 'a' is greater than number 'b'.
-func (c *IsEvenAiCore) IsGreaterThan(a, b int) (*bool, error) {
-	prompt, err := c.getPrompt("isGreaterThan", a, b)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prompt for IsGreaterThan: %w", err)
-	}
-	return c.query(prompt)
+// ParseNumber interprets the text of a model answer that is a whole number,
+// see core.ParseNumber.
+func ParseNumber(text string) (int, error) {
+	return core.ParseNumber(text)
 }
 
-// IsLessThan checks if number 'a' is less than number 'b'.
-// If an 'isLessThan' prompt template is not provided, it derives the result by checking !IsGreaterThan(b,a).
-func (c *IsEvenAiCore) IsLessThan(a, b int) (*bool, error) {
-	prompt, err := c.getPrompt("isLessThan", a, b)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prompt for IsLessThan: %w", err)
-	}
-
-	if prompt != "" { // Template was provided and prompt generated successfully
-		return c.query(prompt)
-	}
-
-	// Fallback: template was optional and not provided
-	isGreaterThanResult, err := c.IsGreaterThan(b, a) // Note: arguments are swapped
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine IsLessThan by inverting IsGreaterThan(b,a): %w", err)
-	}
-	if isGreaterThanResult == nil { // IsGreaterThan(b,a) returned undefined
-		return nil, nil
-	}
-	res := !(*isGreaterThanResult)
-	return &res, nil
+// WithCallMetadata returns a context that collects metadata about the queries
+// made with it, see core.WithCallMetadata.
+func WithCallMetadata(ctx context.Context) (context.Context, *CallMetadata) {
+	return core.WithCallMetadata(ctx)
 }
@@ -0,0 +1,259 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// maxOffenders is the number of numbers listed in AccuracyReport.WorstOffenders.
+const maxOffenders = 10
+
+// PredicateAccuracy counts how a predicate's answers compare to the truth.
+// Undefined answers and errors count as incorrect.
+type PredicateAccuracy struct {
+	Predicate      string `json:"predicate"` // Prompt name, e.g. "isEven"; empty for totals
+	Questions      int    `json:"questions"`
+	TruePositives  int    `json:"true_positives"`  // Answered true, and it is true
+	TrueNegatives  int    `json:"true_negatives"`  // Answered false, and it is false
+	FalsePositives int    `json:"false_positives"` // Answered true, but it is false
+	FalseNegatives int    `json:"false_negatives"` // Answered false, but it is true
+	Undefined      int    `json:"undefined"`
+	Errors         int    `json:"errors"`
+}
+
+// Correct returns the number of correct answers.
+func (a PredicateAccuracy) Correct() int {
+	return a.TruePositives + a.TrueNegatives
+}
+
+// Accuracy returns the fraction of questions answered correctly, or 0 if
+// there were none.
+func (a PredicateAccuracy) Accuracy() float64 {
+	if a.Questions == 0 {
+		return 0
+	}
+	return float64(a.Correct()) / float64(a.Questions)
+}
+
+// Add counts an answer got, or a failure err, to a question whose correct
+// answer is want.
+func (a *PredicateAccuracy) Add(want bool, got *bool, err error) {
+	a.Questions++
+	switch {
+	case err != nil:
+		a.Errors++
+	case got == nil:
+		a.Undefined++
+	case *got && want:
+		a.TruePositives++
+	case *got:
+		a.FalsePositives++
+	case want:
+		a.FalseNegatives++
+	default:
+		a.TrueNegatives++
+	}
+}
+
+// Offender is a number that questions were answered incorrectly about.
+type Offender struct {
+	N         int `json:"n"`
+	Incorrect int `json:"incorrect"` // Incorrect answers to questions involving N
+}
+
+// AccuracyReport is the result of IsEvenAiCore.Audit.
+type AccuracyReport struct {
+	Predicates []PredicateAccuracy `json:"predicates"` // In the order of the Provider methods
+	Total      PredicateAccuracy   `json:"total"`
+	// WorstOffenders lists up to ten numbers with the most incorrect answers,
+	// most incorrect first.
+	WorstOffenders []Offender `json:"worst_offenders"`
+}
+
+// auditPredicates lists the predicates asked by Audit, in report order.
+var auditPredicates = []string{"isEven", "isOdd", "areEqual", "areNotEqual", "isGreaterThan", "isLessThan"}
+
+// Audit asks every predicate about the numbers in sample and compares the
+// answers to arithmetic ground truth. IsEven and IsOdd are asked about each
+// number; the comparisons about each number and the next one in the sample
+// (the last one is paired with the first), and AreEqual and AreNotEqual also
+// about each number and itself, so that both answers occur. Questions are
+// asked one at a time with ctx; if ctx is done, Audit stops and returns
+// ctx.Err().
+func (c *IsEvenAiCore) Audit(ctx context.Context, sample []int) (AccuracyReport, error) {
+	ai := c.WithContext(ctx)
+	accuracy := make(map[string]*PredicateAccuracy, len(auditPredicates))
+	for _, p := range auditPredicates {
+		accuracy[p] = &PredicateAccuracy{Predicate: p}
+	}
+	var total PredicateAccuracy
+	incorrect := map[int]int{}
+
+	for i, a := range sample {
+		b := sample[(i+1)%len(sample)]
+		steps := []struct {
+			predicate string
+			f         func() (*bool, error)
+			args      []int
+		}{
+			{"isEven", func() (*bool, error) { return ai.IsEven(a) }, []int{a}},
+			{"isOdd", func() (*bool, error) { return ai.IsOdd(a) }, []int{a}},
+			{"areEqual", func() (*bool, error) { return ai.AreEqual(a, a) }, []int{a, a}},
+			{"areEqual", func() (*bool, error) { return ai.AreEqual(a, b) }, []int{a, b}},
+			{"areNotEqual", func() (*bool, error) { return ai.AreNotEqual(a, a) }, []int{a, a}},
+			{"areNotEqual", func() (*bool, error) { return ai.AreNotEqual(a, b) }, []int{a, b}},
+			{"isGreaterThan", func() (*bool, error) { return ai.IsGreaterThan(a, b) }, []int{a, b}},
+			{"isLessThan", func() (*bool, error) { return ai.IsLessThan(a, b) }, []int{a, b}},
+		}
+		for _, s := range steps {
+			if err := ctx.Err(); err != nil {
+				return AccuracyReport{}, err
+			}
+			want, err := GroundTruth(s.predicate, s.args)
+			if err != nil {
+				return AccuracyReport{}, err
+			}
+			got, err := s.f()
+			accuracy[s.predicate].Add(want, got, err)
+			total.Add(want, got, err)
+			if err != nil || got == nil || *got != want {
+				for _, n := range slices.Compact(slices.Clone(s.args)) {
+					incorrect[n]++
+				}
+			}
+		}
+	}
+
+	report := AccuracyReport{Total: total}
+	for _, p := range auditPredicates {
+		report.Predicates = append(report.Predicates, *accuracy[p])
+	}
+	for n, count := range incorrect {
+		report.WorstOffenders = append(report.WorstOffenders, Offender{N: n, Incorrect: count})
+	}
+	slices.SortFunc(report.WorstOffenders, func(x, y Offender) int {
+		return cmp.Or(cmp.Compare(y.Incorrect, x.Incorrect), cmp.Compare(x.N, y.N))
+	})
+	if len(report.WorstOffenders) > maxOffenders {
+		report.WorstOffenders = report.WorstOffenders[:maxOffenders]
+	}
+	return report, nil
+}
+
+// groundTruthArity is the number of arguments of the predicates supported by
+// GroundTruth.
+var groundTruthArity = map[string]int{
+	"isEven": 1, "isOdd": 1, "isLeapYear": 1, "isPerfectNumber": 1, "isHappyNumber": 1, "isTriangularNumber": 1,
+	"areEqual": 2, "areNotEqual": 2, "isGreaterThan": 2, "isLessThan": 2, "isFactorOf": 2, "sumIsEven": 2, "productIsEven": 2,
+}
+
+// GroundTruth returns the arithmetically correct answer to predicate, e.g.
+// "isEven", about args. It fails if args holds fewer numbers than predicate
+// takes.
+func GroundTruth(predicate string, args []int) (bool, error) {
+	arity, ok := groundTruthArity[predicate]
+	if !ok {
+		return false, fmt.Errorf("%s is not supported", predicate)
+	}
+	if len(args) < arity {
+		return false, fmt.Errorf("%s takes %d arguments, got %d", predicate, arity, len(args))
+	}
+	switch predicate {
+	case "isEven":
+		return args[0]%2 == 0, nil
+	case "isOdd":
+		return args[0]%2 != 0, nil
+	case "areEqual":
+		return args[0] == args[1], nil
+	case "areNotEqual":
+		return args[0] != args[1], nil
+	case "isGreaterThan":
+		return args[0] > args[1], nil
+	case "isLessThan":
+		return args[0] < args[1], nil
+	case "isLeapYear":
+		return args[0]%4 == 0 && (args[0]%100 != 0 || args[0]%400 == 0), nil
+	case "isPerfectNumber":
+		return isPerfect(args[0]), nil
+	case "isHappyNumber":
+		return isHappy(args[0]), nil
+	case "isTriangularNumber":
+		return isTriangular(args[0]), nil
+	case "isFactorOf":
+		if args[0] == 0 {
+			return args[1] == 0, nil // 0 = 0 * k for every k
+		}
+		return args[1]%args[0] == 0, nil
+	case "sumIsEven":
+		return (args[0]+args[1])%2 == 0, nil
+	case "productIsEven":
+		return args[0]%2 == 0 || args[1]%2 == 0, nil
+	default:
+		return false, fmt.Errorf("%s is not supported", predicate)
+	}
+}
+
+// perfectNumbers are the perfect numbers that fit in an int64. All known
+// perfect numbers are even, of the form 2^(p-1)(2^p-1) for a Mersenne prime
+// 2^p-1, and the next one is far beyond int64.
+var perfectNumbers = []int64{6, 28, 496, 8128, 33550336, 8589869056, 137438691328, 2305843008139952128}
+
+// isPerfect reports whether n is the sum of its proper divisors.
+func isPerfect(n int) bool {
+	return slices.Contains(perfectNumbers, int64(n))
+}
+
+// isHappy reports whether repeatedly replacing n by the sum of the squares of
+// its digits reaches 1. Every other positive number ends up in a cycle
+// containing 4.
+func isHappy(n int) bool {
+	if n < 1 {
+		return false
+	}
+	for n != 1 && n != 4 {
+		sum := 0
+		for ; n > 0; n /= 10 {
+			sum += (n % 10) * (n % 10)
+		}
+		n = sum
+	}
+	return n == 1
+}
+
+// isTriangular reports whether n is 1 + 2 + ... + k for some k >= 0. It
+// searches k in uint64, since k(k+1)/2 overflows int for k >= 1<<32 while
+// n < 1<<63.
+func isTriangular(n int) bool {
+	if n < 0 {
+		return false
+	}
+	target := uint64(n)
+	lo, hi := uint64(0), uint64(1)<<32
+	for lo < hi {
+		k := lo + (hi-lo)/2
+		switch t := triangle(k); {
+		case t == target:
+			return true
+		case t < target:
+			lo = k + 1
+		default:
+			hi = k
+		}
+	}
+	return false
+}
+
+// triangle returns k(k+1)/2 without overflowing for k <= 1<<32.
+func triangle(k uint64) uint64 {
+	if k%2 == 0 {
+		return k / 2 * (k + 1)
+	}
+	return k * ((k + 1) / 2)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	// The model gets everything about 7 wrong and has no idea about 9 being even.
+	core := newIsEvenAiCore(testPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		info, _ := CallInfoFromContext(ctx)
+		answer, err := GroundTruth(info.Predicate, info.Args)
+		switch {
+		case err != nil:
+			return nil, err
+		case info.Predicate == "isEven" && info.Args[0] == 9:
+			return nil, nil
+		case info.Args[0] == 7:
+			answer = !answer
+		}
+		return &answer, nil
+	})
+
+	report, err := core.Audit(context.Background(), []int{2, 7, 9})
+	if err != nil {
+		t.Fatalf("Audit() failed: %v", err)
+	}
+
+	isEven := report.Predicates[0]
+	if isEven.Predicate != "isEven" || isEven.Questions != 3 || isEven.TruePositives != 1 || isEven.FalsePositives != 1 || isEven.Undefined != 1 {
+		t.Errorf("isEven accuracy = %+v", isEven)
+	}
+	// The 8 questions starting with 7 are answered wrong, and IsEven(9) is undefined.
+	if report.Total.Questions != 24 || report.Total.Correct() != 15 {
+		t.Errorf("total accuracy = %+v, want 15 of 24 correct", report.Total)
+	}
+	if got := report.Total.Accuracy(); got != 15.0/24 {
+		t.Errorf("Accuracy() = %v, want %v", got, 15.0/24)
+	}
+	want := []Offender{{N: 7, Incorrect: 8}, {N: 9, Incorrect: 5}}
+	if !slices.Equal(report.WorstOffenders, want) {
+		t.Errorf("WorstOffenders = %v, want %v", report.WorstOffenders, want)
+	}
+}
+
+func TestAudit_Errors(t *testing.T) {
+	core := newIsEvenAiCore(testPromptTemplates, func(context.Context, string) (*bool, error) {
+		return nil, errors.New("quota exceeded")
+	})
+	report, err := core.Audit(context.Background(), []int{1})
+	if err != nil {
+		t.Fatalf("Audit() failed: %v", err)
+	}
+	if report.Total.Errors != 8 || report.Total.Correct() != 0 {
+		t.Errorf("total accuracy = %+v, want 8 errors", report.Total)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := core.Audit(ctx, []int{1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Audit() with canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestGroundTruth_IsTriangularNumber(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want bool
+	}{
+		{-1, false}, {0, true}, {1, true}, {2, false}, {3, true}, {5050, true}, {5051, false},
+		{9223372034707292160, true}, // k = 1<<32 - 1, the largest that fits
+		{math.MaxInt, false},
+		{math.MaxInt - 1, false},
+	} {
+		got, err := GroundTruth("isTriangularNumber", []int{tc.n})
+		if err != nil || got != tc.want {
+			t.Errorf("GroundTruth(isTriangularNumber, %d) = %v, %v; want %v", tc.n, got, err, tc.want)
+		}
+	}
+}
+
+func TestGroundTruth_IsPerfectNumber(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want bool
+	}{
+		{-6, false}, {0, false}, {1, false}, {6, true}, {12, false}, {28, true}, {8128, true},
+		{2305843008139952128, true},
+		{9223372036854775783, false}, // The largest prime below 1<<63
+		{math.MaxInt, false},
+	} {
+		start := time.Now()
+		got, err := GroundTruth("isPerfectNumber", []int{tc.n})
+		if err != nil || got != tc.want {
+			t.Errorf("GroundTruth(isPerfectNumber, %d) = %v, %v; want %v", tc.n, got, err, tc.want)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("GroundTruth(isPerfectNumber, %d) took %v", tc.n, elapsed)
+		}
+	}
+}
+
+func TestGroundTruth_Arity(t *testing.T) {
+	for predicate, arity := range groundTruthArity {
+		if _, err := GroundTruth(predicate, make([]int, arity)); err != nil {
+			t.Errorf("GroundTruth(%s) with %d arguments failed: %v", predicate, arity, err)
+		}
+		if _, err := GroundTruth(predicate, make([]int, arity-1)); err == nil {
+			t.Errorf("GroundTruth(%s) with %d arguments succeeded, want an error", predicate, arity-1)
+		}
+	}
+	if _, err := GroundTruth("isEven", nil); err == nil {
+		t.Error("GroundTruth(isEven) without arguments succeeded, want an error")
+	}
+	if _, err := GroundTruth("isPrime", []int{7}); err == nil {
+		t.Error("GroundTruth(isPrime) succeeded, want an error")
+	}
+}
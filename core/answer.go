@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Answer is a parsed model answer. The zero value is AnswerUndefined.
+type Answer int
+
+const (
+	AnswerUndefined Answer = iota
+	AnswerTrue
+	AnswerFalse
+)
+
+// Bool converts a to the *bool used by the predicates: nil for AnswerUndefined.
+func (a Answer) Bool() *bool {
+	var b bool
+	switch a {
+	case AnswerTrue:
+		b = true
+	case AnswerFalse:
+		b = false
+	default:
+		return nil
+	}
+	return &b
+}
+
+func (a Answer) String() string {
+	switch a {
+	case AnswerTrue:
+		return "true"
+	case AnswerFalse:
+		return "false"
+	default:
+		return "undefined"
+	}
+}
+
+// LogValue implements slog.LogValuer, so answers are logged as "true",
+// "false" or "undefined" by every handler, including JSON ones.
+func (a Answer) LogValue() slog.Value {
+	return slog.StringValue(a.String())
+}
+
+// AnswerOf converts a predicate result to an Answer.
+func AnswerOf(res *bool) Answer {
+	switch {
+	case res == nil:
+		return AnswerUndefined
+	case *res:
+		return AnswerTrue
+	default:
+		return AnswerFalse
+	}
+}
+
+// answerWords maps the accepted words, in lower case, to answers.
+var answerWords = map[string]Answer{
+	"true":      AnswerTrue,
+	"yes":       AnswerTrue,
+	"false":     AnswerFalse,
+	"no":        AnswerFalse,
+	"undefined": AnswerUndefined,
+	"unknown":   AnswerUndefined,
+	"null":      AnswerUndefined,
+}
+
+// ParseAnswer interprets the text of a model answer. The grammar is:
+//
+//	answer = space* wrap* word punct* wrap* space*
+//	word   = "true" | "yes" | "false" | "no" | "undefined" | "unknown" | "null"
+//	wrap   = '"' | "'" | "`" | "*" | "_"
+//	punct  = "." | "!"
+//
+// Words are matched case-insensitively and wrapping characters need not be
+// balanced, so "True", "**false**" and "`yes`." are all accepted. Anything
+// else is AnswerUndefined with an error wrapping ErrUnparsableAnswer.
+func ParseAnswer(text string) (Answer, error) {
+	word := strings.TrimSpace(text)
+	word = strings.Trim(word, "\"'`*_")
+	word = strings.TrimRight(word, ".!")
+	word = strings.Trim(word, "\"'`*_")
+	if a, ok := answerWords[strings.ToLower(word)]; ok {
+		return a, nil
+	}
+	return AnswerUndefined, fmt.Errorf("%w: %q", ErrUnparsableAnswer, text)
+}
+
+// ParseNumber interprets the text of a model answer that is a whole number,
+// the numeric counterpart of ParseAnswer. The grammar is:
+//
+//	answer = space* wrap* sign? digits punct* wrap* space*
+//	digits = digit+ ("," digit digit digit)*
+//	sign   = "-" | "+"
+//
+// with wrap and punct as for ParseAnswer, so "42", "**-7**" and "1,024." are
+// all accepted. Anything else, including numbers that don't fit in an int,
+// fails with an error wrapping ErrUnparsableAnswer.
+func ParseNumber(text string) (int, error) {
+	word := strings.TrimSpace(text)
+	word = strings.Trim(word, "\"'`*_")
+	word = strings.TrimRight(word, ".!")
+	word = strings.Trim(word, "\"'`*_")
+	digits := strings.TrimLeft(word, "+-")
+	groups := strings.Split(digits, ",")
+	for i, g := range groups {
+		if g == "" || (i > 0 && len(g) != 3) || strings.Trim(g, "0123456789") != "" {
+			return 0, fmt.Errorf("%w: %q", ErrUnparsableAnswer, text)
+		}
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(word, ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnparsableAnswer, text)
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswer(t *testing.T) {
+	testCases := []struct {
+		text    string
+		want    Answer
+		wantErr bool
+	}{
+		{"true", AnswerTrue, false},
+		{"False", AnswerFalse, false},
+		{"  TRUE\n", AnswerTrue, false},
+		{"**false**", AnswerFalse, false},
+		{"`yes`.", AnswerTrue, false},
+		{"\"no\"", AnswerFalse, false},
+		{"true!", AnswerTrue, false},
+		{"Unknown", AnswerUndefined, false},
+		{"null", AnswerUndefined, false},
+		{"", AnswerUndefined, true},
+		{"maybe", AnswerUndefined, true},
+		{"true or false", AnswerUndefined, true},
+		{"The answer is true", AnswerUndefined, true},
+	}
+	for _, tc := range testCases {
+		got, err := ParseAnswer(tc.text)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("ParseAnswer(%q) = %v, %v; want %v, error %t", tc.text, got, err, tc.want, tc.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrUnparsableAnswer) {
+			t.Errorf("ParseAnswer(%q) error %v does not wrap ErrUnparsableAnswer", tc.text, err)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	testCases := []struct {
+		text    string
+		want    int
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"  -7\n", -7, false},
+		{"+8", 8, false},
+		{"**1,024**.", 1024, false},
+		{"`0`", 0, false},
+		{"", 0, true},
+		{"-", 0, true},
+		{"--4", 0, true},
+		{"4.5", 0, true},
+		{"1,02", 0, true},
+		{",100", 0, true},
+		{"forty-two", 0, true},
+		{"The number is 42", 0, true},
+		{"99999999999999999999", 0, true},
+	}
+	for _, tc := range testCases {
+		got, err := ParseNumber(tc.text)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("ParseNumber(%q) = %v, %v; want %v, error %t", tc.text, got, err, tc.want, tc.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrUnparsableAnswer) {
+			t.Errorf("ParseNumber(%q) error %v does not wrap ErrUnparsableAnswer", tc.text, err)
+		}
+	}
+}
+
+func TestAnswer_Bool(t *testing.T) {
+	if b := AnswerTrue.Bool(); b == nil || !*b {
+		t.Errorf("AnswerTrue.Bool() = %v", b)
+	}
+	if b := AnswerFalse.Bool(); b == nil || *b {
+		t.Errorf("AnswerFalse.Bool() = %v", b)
+	}
+	if b := AnswerUndefined.Bool(); b != nil {
+		t.Errorf("AnswerUndefined.Bool() = %v", *b)
+	}
+}
+
+func FuzzParseAnswer(f *testing.F) {
+	for _, seed := range []string{"true", "False.", "**yes**", "no!", "unknown", "maybe", "", "\"`*_.!"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		a, err := ParseAnswer(text)
+		if err != nil && a != AnswerUndefined {
+			t.Errorf("ParseAnswer(%q) = %v with error %v; errors must come with AnswerUndefined", text, a, err)
+		}
+		if err == nil {
+			// Accepted answers survive re-parsing of their canonical form and
+			// of the input with extra surrounding whitespace.
+			if again, _ := ParseAnswer(a.String()); again != a {
+				t.Errorf("ParseAnswer(%q) = %v, but its String() parses as %v", text, a, again)
+			}
+			if padded, _ := ParseAnswer(" " + text + "\n"); padded != a {
+				t.Errorf("ParseAnswer(%q) = %v, but with surrounding whitespace %v", text, a, padded)
+			}
+		}
+		if strings.Contains(strings.ToLower(text), "true") && a == AnswerFalse {
+			t.Errorf("ParseAnswer(%q) = false for text containing true", text)
+		}
+	})
+}
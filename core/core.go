@@ -0,0 +1,552 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package core holds IsEvenAiCore, its prompt templates, answer parser and
+// result types. It has no dependencies outside the standard library, so that
+// custom providers can be built against it where the Gemini SDK is not
+// allowed. The is_even_ai package re-exports everything in it.
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// PromptTemplate1 defines a function that takes one integer argument and returns a string prompt.
+type PromptTemplate1 func(n int) string
+
+// PromptTemplate2 defines a function that takes two integer arguments and returns a string prompt.
+type PromptTemplate2 func(a, b int) string
+
+// IsEvenAiCorePromptTemplates holds the templates for generating prompts.
+//   - IsEven, AreEqual, IsGreaterThan are mandatory.
+//   - IsOdd, AreNotEqual, IsLessThan are optional. If a template for an optional
+//     operation is nil, the corresponding method will use a fallback strategy
+//     (e.g., IsOdd will be derived from !IsEven).
+//   - The remaining templates, from IsLeapYear on, are optional too, but have
+//     no fallback: each method fails without its template.
+//
+// All prompt template functions are synchronous and return a string.
+type IsEvenAiCorePromptTemplates struct {
+	IsEven             PromptTemplate1
+	IsOdd              PromptTemplate1 // Optional: if nil, IsOdd will be derived from !IsEven
+	AreEqual           PromptTemplate2
+	AreNotEqual        PromptTemplate2 // Optional: if nil, AreNotEqual will be derived from !AreEqual
+	IsGreaterThan      PromptTemplate2
+	IsLessThan         PromptTemplate2 // Optional: if nil, IsLessThan will be derived from !IsGreaterThan(b,a)
+	IsLeapYear         PromptTemplate1 // Optional: if nil, IsLeapYear fails
+	IsPerfectNumber    PromptTemplate1 // Optional: if nil, IsPerfectNumber fails
+	IsHappyNumber      PromptTemplate1 // Optional: if nil, IsHappyNumber fails
+	IsTriangularNumber PromptTemplate1 // Optional: if nil, IsTriangularNumber fails
+	IsFactorOf         PromptTemplate2 // Optional: if nil, IsFactorOf fails. Asks whether a divides b
+	SumIsEven          PromptTemplate2 // Optional: if nil, SumIsEven fails
+	ProductIsEven      PromptTemplate2 // Optional: if nil, ProductIsEven fails
+	// Optional: identifies this set of templates in cache keys. Change it when
+	// editing the templates, so that answers to the old prompts are not reused.
+	Version string
+}
+
+// QueryFunc defines a function that takes a prompt string, queries an AI model,
+// and returns a boolean result or an error. The *bool type allows for true, false,
+// or nil (representing an undefined or indeterminate answer from the AI).
+type QueryFunc func(prompt string) (result *bool, err error)
+
+// QueryHandler is the context-aware form of QueryFunc that Middleware wraps.
+// The context carries the CallInfo of the question being asked.
+type QueryHandler func(ctx context.Context, prompt string) (*bool, error)
+
+// Middleware wraps a QueryHandler to add behavior such as caching, retries,
+// logging or metrics. Add middleware to a provider with IsEvenAiCore.Use.
+type Middleware func(next QueryHandler) QueryHandler
+
+// Chain combines middleware into one. The first middleware is the outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next QueryHandler) QueryHandler {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// CallInfo describes which predicate a prompt asks about, for providers and
+// middleware that need more than the prompt (e.g. audit logs and metrics).
+type CallInfo struct {
+	Predicate string // Prompt name, e.g. "isEven"
+	Args      []int
+	Source    AnswerSource
+}
+
+// AnswerSource identifies the configuration answering a prompt. Answers produced
+// under a different source may differ, so CacheKey includes it.
+type AnswerSource struct {
+	Provider         string // e.g. "gemini"; empty for custom query functions
+	Model            string
+	TemplateVersion  string // IsEvenAiCorePromptTemplates.Version
+	SystemPromptHash string // See HashSystemPrompt; empty if there is no system prompt
+}
+
+// HashSystemPrompt returns a short hash of a system prompt for AnswerSource.
+func HashSystemPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+type callInfoKey struct{}
+
+// CallInfoFromContext returns the CallInfo stored in ctx by IsEvenAiCore.
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(CallInfo)
+	return info, ok
+}
+
+// ContextWithCallInfo returns a copy of ctx carrying info, for middleware that
+// asks a different question than the one it was given.
+func ContextWithCallInfo(ctx context.Context, info CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+type promptsKey struct{}
+
+// PromptsFromContext returns the function building prompts of the
+// IsEvenAiCore asking the query in ctx, or nil. It returns an empty prompt
+// for optional templates that are not defined.
+func PromptsFromContext(ctx context.Context) func(predicate string, args ...int) (string, error) {
+	prompts, _ := ctx.Value(promptsKey{}).(func(string, ...int) (string, error))
+	return prompts
+}
+
+// IsEvenAiCore provides the core functionality for querying number properties using AI.
+type IsEvenAiCore struct {
+	// promptTemplates is shared with copies made by WithContext, so that
+	// SetPromptTemplates affects them too.
+	promptTemplates *atomic.Pointer[IsEvenAiCorePromptTemplates]
+	handler         QueryHandler // Provider's own query function
+	middleware      []Middleware
+	query           QueryHandler // handler wrapped in middleware
+	ctx             context.Context
+	source          AnswerSource // Set by providers; TemplateVersion is filled in by ask
+	drainer         *Drainer     // Shared with copies made by WithContext
+	caches          []CacheInvalidator
+}
+
+// Validate checks that the mandatory templates IsEven, AreEqual and
+// IsGreaterThan are defined. The error lists all missing ones and wraps
+// ErrInvalidTemplates.
+func (t IsEvenAiCorePromptTemplates) Validate() error {
+	var missing []string
+	if t.IsEven == nil {
+		missing = append(missing, "IsEven")
+	}
+	if t.AreEqual == nil {
+		missing = append(missing, "AreEqual")
+	}
+	if t.IsGreaterThan == nil {
+		missing = append(missing, "IsGreaterThan")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidTemplates, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// NewIsEvenAiCoreChecked is like NewIsEvenAiCore, but returns an error instead
+// of panicking on a nil query function, and validates the templates up front
+// instead of failing the first query that needs a missing one.
+func NewIsEvenAiCoreChecked(templates IsEvenAiCorePromptTemplates, query QueryFunc) (*IsEvenAiCore, error) {
+	if query == nil {
+		return nil, errors.New("query function cannot be nil")
+	}
+	if err := templates.Validate(); err != nil {
+		return nil, err
+	}
+	return NewIsEvenAiCore(templates, query), nil
+}
+
+// NewIsEvenAiCore creates a new instance of IsEvenAiCore.
+// It requires a set of prompt templates and a query function to interact with an AI.
+// It panics if query is nil, and accepts templates missing mandatory entries;
+// see NewIsEvenAiCoreChecked for a constructor that reports both as errors.
+func NewIsEvenAiCore(templates IsEvenAiCorePromptTemplates, query QueryFunc) *IsEvenAiCore {
+	if query == nil {
+		panic("query function cannot be nil") // Or return an error
+	}
+	return NewIsEvenAiCoreWithHandler(templates, func(_ context.Context, prompt string) (*bool, error) {
+		return query(prompt)
+	}, AnswerSource{})
+}
+
+// NewIsEvenAiCoreWithHandler is like NewIsEvenAiCore for providers whose
+// query function needs the call context, e.g. for its CallInfo. source
+// identifies the provider and model in CallInfo and cache keys.
+func NewIsEvenAiCoreWithHandler(templates IsEvenAiCorePromptTemplates, query QueryHandler, source AnswerSource) *IsEvenAiCore {
+	if query == nil {
+		panic("query function cannot be nil")
+	}
+	c := &IsEvenAiCore{
+		promptTemplates: &atomic.Pointer[IsEvenAiCorePromptTemplates]{},
+		handler:         query,
+		query:           query,
+		ctx:             context.Background(),
+		source:          source,
+		drainer:         &Drainer{},
+	}
+	c.promptTemplates.Store(&templates)
+	return c
+}
+
+// Use adds middleware around the query function. The first middleware added
+// is the outermost, i.e. it sees each query first. Use is not safe to call
+// concurrently with queries, so call it right after construction.
+func (c *IsEvenAiCore) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+	c.query = Chain(c.middleware...)(c.handler)
+}
+
+// SetPromptTemplates replaces the prompt templates, e.g. to reload them from
+// a file without a restart. It fails like IsEvenAiCorePromptTemplates.Validate
+// if a mandatory template is missing. Unlike Use, it is safe to call
+// concurrently with queries, and affects copies made by WithContext.
+func (c *IsEvenAiCore) SetPromptTemplates(templates IsEvenAiCorePromptTemplates) error {
+	if err := templates.Validate(); err != nil {
+		return err
+	}
+	c.promptTemplates.Store(&templates)
+	return nil
+}
+
+// PromptTemplates returns the current prompt templates.
+func (c *IsEvenAiCore) PromptTemplates() IsEvenAiCorePromptTemplates {
+	return *c.promptTemplates.Load()
+}
+
+// WithContext returns a shallow copy of c whose queries use ctx, e.g. for
+// cancellation or to collect CallMetadata:
+//
+//	ctx, md := is_even_ai.WithCallMetadata(ctx)
+//	even, err := ai.WithContext(ctx).IsEven(42)
+//	fmt.Println(md.Model, md.CostUSD)
+func (c *IsEvenAiCore) WithContext(ctx context.Context) *IsEvenAiCore {
+	if ctx == nil {
+		panic("nil context")
+	}
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// ask sends prompt, which asks about predicate applied to args, to the query function.
+func (c *IsEvenAiCore) ask(predicate, prompt string, args ...int) (*bool, error) {
+	source := c.source
+	source.TemplateVersion = c.promptTemplates.Load().Version
+	if md := CallMetadataFromContext(c.ctx); md != nil {
+		md.TemplateVersion = source.TemplateVersion
+	}
+	ctx := ContextWithCallInfo(c.ctx, CallInfo{Predicate: predicate, Args: args, Source: source})
+	ctx = context.WithValue(ctx, promptsKey{}, c.Prompt) // For middleware asking related questions
+	if err := c.drainer.Enter(); err != nil {
+		return nil, err
+	}
+	defer c.drainer.Leave()
+	return c.query(ctx, prompt)
+}
+
+// Prompt retrieves and formats a prompt string based on the prompt name and arguments.
+// For optional templates with a fallback that are not provided, it returns an empty
+// string and no error.
+func (c *IsEvenAiCore) Prompt(promptName string, args ...int) (string, error) {
+	t := c.promptTemplates.Load()
+	switch promptName {
+	case "isEven":
+		if t.IsEven == nil {
+			return "", errors.New("isEven prompt template is mandatory and not defined")
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isEven prompt")
+		}
+		return t.IsEven(args[0]), nil
+	case "isOdd":
+		if t.IsOdd == nil {
+			return "", nil // Optional, return empty string if not defined
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isOdd prompt")
+		}
+		return t.IsOdd(args[0]), nil
+	case "areEqual":
+		if t.AreEqual == nil {
+			return "", errors.New("areEqual prompt template is mandatory and not defined")
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for areEqual prompt")
+		}
+		return t.AreEqual(args[0], args[1]), nil
+	case "areNotEqual":
+		if t.AreNotEqual == nil {
+			return "", nil // Optional
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for areNotEqual prompt")
+		}
+		return t.AreNotEqual(args[0], args[1]), nil
+	case "isGreaterThan":
+		if t.IsGreaterThan == nil {
+			return "", errors.New("isGreaterThan prompt template is mandatory and not defined")
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for isGreaterThan prompt")
+		}
+		return t.IsGreaterThan(args[0], args[1]), nil
+	case "isLessThan":
+		if t.IsLessThan == nil {
+			return "", nil // Optional
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for isLessThan prompt")
+		}
+		return t.IsLessThan(args[0], args[1]), nil
+	case "isLeapYear":
+		if t.IsLeapYear == nil {
+			return "", errors.New("isLeapYear prompt template is not defined")
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isLeapYear prompt")
+		}
+		return t.IsLeapYear(args[0]), nil
+	case "isPerfectNumber":
+		if t.IsPerfectNumber == nil {
+			return "", errors.New("isPerfectNumber prompt template is not defined")
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isPerfectNumber prompt")
+		}
+		return t.IsPerfectNumber(args[0]), nil
+	case "isHappyNumber":
+		if t.IsHappyNumber == nil {
+			return "", errors.New("isHappyNumber prompt template is not defined")
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isHappyNumber prompt")
+		}
+		return t.IsHappyNumber(args[0]), nil
+	case "isTriangularNumber":
+		if t.IsTriangularNumber == nil {
+			return "", errors.New("isTriangularNumber prompt template is not defined")
+		}
+		if len(args) < 1 {
+			return "", errors.New("not enough arguments for isTriangularNumber prompt")
+		}
+		return t.IsTriangularNumber(args[0]), nil
+	case "isFactorOf":
+		if t.IsFactorOf == nil {
+			return "", errors.New("isFactorOf prompt template is not defined")
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for isFactorOf prompt")
+		}
+		return t.IsFactorOf(args[0], args[1]), nil
+	case "sumIsEven":
+		if t.SumIsEven == nil {
+			return "", errors.New("sumIsEven prompt template is not defined")
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for sumIsEven prompt")
+		}
+		return t.SumIsEven(args[0], args[1]), nil
+	case "productIsEven":
+		if t.ProductIsEven == nil {
+			return "", errors.New("productIsEven prompt template is not defined")
+		}
+		if len(args) < 2 {
+			return "", errors.New("not enough arguments for productIsEven prompt")
+		}
+		return t.ProductIsEven(args[0], args[1]), nil
+	default:
+		return "", fmt.Errorf("unknown prompt name: %s", promptName)
+	}
+}
+
+// IsEven checks if a number 'n' is even.
+// Returns a pointer to boolean (*bool) and an error.
+// *bool can be true, false, or nil (if the AI's response is undefined).
+func (c *IsEvenAiCore) IsEven(n int) (*bool, error) {
+	prompt, err := c.Prompt("isEven", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsEven: %w", err)
+	}
+	return c.ask("isEven", prompt, n)
+}
+
+// IsOdd checks if a number 'n' is odd.
+// If an 'isOdd' prompt template is not provided, it derives the result by negating IsEven(n).
+func (c *IsEvenAiCore) IsOdd(n int) (*bool, error) {
+	prompt, err := c.Prompt("isOdd", n)
+	if err != nil {
+		// This error means Prompt failed (e.g., not enough args for a defined template,
+		// or a misconfiguration). It should not proceed to fallback.
+		return nil, fmt.Errorf("failed to get prompt for IsOdd: %w", err)
+	}
+
+	if prompt != "" { // Template was provided and prompt generated successfully
+		return c.ask("isOdd", prompt, n)
+	}
+
+	// Fallback: template was optional and not provided (i.e., prompt == "" and err == nil from Prompt)
+	isEvenResult, err := c.IsEven(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine IsOdd by inverting IsEven: %w", err)
+	}
+	if isEvenResult == nil { // IsEven returned undefined
+		return nil, nil
+	}
+	res := !(*isEvenResult)
+	return &res, nil
+}
+
+// AreEqual checks if numbers 'a' and 'b' are equal.
+func (c *IsEvenAiCore) AreEqual(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("areEqual", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for AreEqual: %w", err)
+	}
+	return c.ask("areEqual", prompt, a, b)
+}
+
+// AreNotEqual checks if numbers 'a' and 'b' are not equal.
+// If an 'areNotEqual' prompt template is not provided, it derives the result by negating AreEqual(a,b).
+func (c *IsEvenAiCore) AreNotEqual(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("areNotEqual", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for AreNotEqual: %w", err)
+	}
+
+	if prompt != "" { // Template was provided and prompt generated successfully
+		return c.ask("areNotEqual", prompt, a, b)
+	}
+
+	// Fallback: template was optional and not provided
+	areEqualResult, err := c.AreEqual(a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine AreNotEqual by inverting AreEqual: %w", err)
+	}
+	if areEqualResult == nil { // AreEqual returned undefined
+		return nil, nil
+	}
+	res := !(*areEqualResult)
+	return &res, nil
+}
+
+// IsGreaterThan checks if number 'a' is greater than number 'b'.
+func (c *IsEvenAiCore) IsGreaterThan(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("isGreaterThan", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsGreaterThan: %w", err)
+	}
+	return c.ask("isGreaterThan", prompt, a, b)
+}
+
+// IsLessThan checks if number 'a' is less than number 'b'.
+// If an 'isLessThan' prompt template is not provided, it derives the result by checking !IsGreaterThan(b,a).
+func (c *IsEvenAiCore) IsLessThan(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("isLessThan", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsLessThan: %w", err)
+	}
+
+	if prompt != "" { // Template was provided and prompt generated successfully
+		return c.ask("isLessThan", prompt, a, b)
+	}
+
+	// Fallback: template was optional and not provided
+	isGreaterThanResult, err := c.IsGreaterThan(b, a) // Note: arguments are swapped
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine IsLessThan by inverting IsGreaterThan(b,a): %w", err)
+	}
+	if isGreaterThanResult == nil { // IsGreaterThan(b,a) returned undefined
+		return nil, nil
+	}
+	res := !(*isGreaterThanResult)
+	return &res, nil
+}
+
+// IsLeapYear checks if 'year' is a leap year of the Gregorian calendar.
+// It requires an 'isLeapYear' prompt template; there is no fallback.
+func (c *IsEvenAiCore) IsLeapYear(year int) (*bool, error) {
+	prompt, err := c.Prompt("isLeapYear", year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsLeapYear: %w", err)
+	}
+	return c.ask("isLeapYear", prompt, year)
+}
+
+// IsPerfectNumber checks if 'n' is a perfect number, i.e. the sum of its
+// proper divisors. It requires an 'isPerfectNumber' prompt template; there is
+// no fallback.
+func (c *IsEvenAiCore) IsPerfectNumber(n int) (*bool, error) {
+	prompt, err := c.Prompt("isPerfectNumber", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsPerfectNumber: %w", err)
+	}
+	return c.ask("isPerfectNumber", prompt, n)
+}
+
+// IsHappyNumber checks if 'n' is a happy number, i.e. repeatedly replacing it
+// by the sum of the squares of its digits eventually reaches 1. It requires an
+// 'isHappyNumber' prompt template; there is no fallback.
+func (c *IsEvenAiCore) IsHappyNumber(n int) (*bool, error) {
+	prompt, err := c.Prompt("isHappyNumber", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsHappyNumber: %w", err)
+	}
+	return c.ask("isHappyNumber", prompt, n)
+}
+
+// IsTriangularNumber checks if 'n' is a triangular number, i.e. the sum
+// 1 + 2 + ... + k for some k >= 0. It requires an 'isTriangularNumber' prompt
+// template; there is no fallback.
+func (c *IsEvenAiCore) IsTriangularNumber(n int) (*bool, error) {
+	prompt, err := c.Prompt("isTriangularNumber", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsTriangularNumber: %w", err)
+	}
+	return c.ask("isTriangularNumber", prompt, n)
+}
+
+// IsFactorOf checks if 'a' is a factor of 'b', i.e. 'b' is a multiple of 'a'.
+// Note the order: IsFactorOf(3, 12) is true, IsFactorOf(12, 3) is false. It
+// requires an 'isFactorOf' prompt template; there is no fallback.
+func (c *IsEvenAiCore) IsFactorOf(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("isFactorOf", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for IsFactorOf: %w", err)
+	}
+	return c.ask("isFactorOf", prompt, a, b)
+}
+
+// SumIsEven checks if a + b is even. The model is asked about the sum as a
+// single question; computing it here would defeat the purpose. It requires a
+// 'sumIsEven' prompt template; there is no fallback.
+func (c *IsEvenAiCore) SumIsEven(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("sumIsEven", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for SumIsEven: %w", err)
+	}
+	return c.ask("sumIsEven", prompt, a, b)
+}
+
+// ProductIsEven checks if a * b is even, asked like SumIsEven. It requires a
+// 'productIsEven' prompt template; there is no fallback.
+func (c *IsEvenAiCore) ProductIsEven(a, b int) (*bool, error) {
+	prompt, err := c.Prompt("productIsEven", a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for ProductIsEven: %w", err)
+	}
+	return c.ask("productIsEven", prompt, a, b)
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import "errors"
+
+// ErrInvalidTemplates is returned by NewIsEvenAiCoreChecked for templates
+// missing a mandatory entry.
+var ErrInvalidTemplates = errors.New("invalid prompt templates")
+
+// ErrUnparsableAnswer is returned by ParseAnswer for text that is not a
+// recognized answer.
+var ErrUnparsableAnswer = errors.New("unparsable answer")
+
+// ErrShutdown is returned for queries made after Shutdown was called.
+var ErrShutdown = errors.New("client is shut down")
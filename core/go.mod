@@ -0,0 +1,3 @@
+module github.com/philwo/is-even-ai/core
+
+go 1.24.3
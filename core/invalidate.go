@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// CacheInvalidator is implemented by caches that can purge their answers,
+// e.g. after a hallucination was discovered.
+type CacheInvalidator interface {
+	// InvalidateNumber removes the answers to questions about n, including
+	// those comparing n to another number.
+	InvalidateNumber(ctx context.Context, n int) error
+	// InvalidatePredicate removes the answers to questions about predicate,
+	// e.g. "isEven".
+	InvalidatePredicate(ctx context.Context, predicate string) error
+	// FlushCache removes all answers.
+	FlushCache(ctx context.Context) error
+}
+
+// RegisterCache makes InvalidateNumber, InvalidatePredicate and FlushCache
+// purge caches, typically those used by caching middleware added with Use.
+// Like Use, it is not safe to call concurrently with queries.
+func (c *IsEvenAiCore) RegisterCache(caches ...CacheInvalidator) {
+	c.caches = append(slices.Clip(c.caches), caches...)
+}
+
+// InvalidateNumber removes the cached answers to questions about n from the
+// registered caches, so that the model is asked again. It returns the errors
+// of all caches that failed.
+func (c *IsEvenAiCore) InvalidateNumber(n int) error {
+	return c.invalidate(func(cache CacheInvalidator) error { return cache.InvalidateNumber(c.ctx, n) })
+}
+
+// InvalidatePredicate removes the cached answers to questions about
+// predicate, e.g. "isEven", from the registered caches.
+func (c *IsEvenAiCore) InvalidatePredicate(predicate string) error {
+	return c.invalidate(func(cache CacheInvalidator) error { return cache.InvalidatePredicate(c.ctx, predicate) })
+}
+
+// FlushCache removes all answers from the registered caches.
+func (c *IsEvenAiCore) FlushCache() error {
+	return c.invalidate(func(cache CacheInvalidator) error { return cache.FlushCache(c.ctx) })
+}
+
+// invalidate calls purge for each registered cache.
+func (c *IsEvenAiCore) invalidate(purge func(CacheInvalidator) error) error {
+	var errs []error
+	for _, cache := range c.caches {
+		if err := purge(cache); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
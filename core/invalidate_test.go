@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// recordingInvalidator records the purges asked of it.
+type recordingInvalidator struct {
+	calls []string
+	err   error
+}
+
+func (r *recordingInvalidator) InvalidateNumber(_ context.Context, n int) error {
+	r.calls = append(r.calls, fmt.Sprintf("number %d", n))
+	return r.err
+}
+
+func (r *recordingInvalidator) InvalidatePredicate(_ context.Context, predicate string) error {
+	r.calls = append(r.calls, "predicate "+predicate)
+	return r.err
+}
+
+func (r *recordingInvalidator) FlushCache(context.Context) error {
+	r.calls = append(r.calls, "flush")
+	return r.err
+}
+
+func TestRegisterCache(t *testing.T) {
+	core := newIsEvenAiCore(testPromptTemplates, func(context.Context, string) (*bool, error) { return nil, nil })
+	if err := core.FlushCache(); err != nil {
+		t.Errorf("FlushCache() without caches = %v", err)
+	}
+
+	ok, broken := &recordingInvalidator{}, &recordingInvalidator{err: errors.New("cache down")}
+	core.RegisterCache(ok, broken)
+	if err := core.InvalidateNumber(7); !errors.Is(err, broken.err) {
+		t.Errorf("InvalidateNumber() = %v, want %v", err, broken.err)
+	}
+	_ = core.InvalidatePredicate("isEven")
+	_ = core.WithContext(context.Background()).FlushCache()
+	want := []string{"number 7", "predicate isEven", "flush"}
+	if !slices.Equal(ok.calls, want) || !slices.Equal(broken.calls, want) {
+		t.Errorf("calls = %v and %v, want %v", ok.calls, broken.calls, want)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// CallMetadata describes how the queries made with a context were answered.
+// Derived answers (e.g. IsOdd without its own template) and verification
+// requests are included, so Usage may count more than one request.
+type CallMetadata struct {
+	Model string // Model that answered last, which may be a fallback model
+	// TemplateVersion is the IsEvenAiCorePromptTemplates.Version of the
+	// prompts asked, for finding them again when reproducing the answer.
+	TemplateVersion string
+	// Resolved is set if a consistency check found contradicting answers
+	// and a tie-breaker gave the answer returned.
+	Resolved bool
+	Usage
+}
+
+func (md CallMetadata) String() string {
+	s := fmt.Sprintf("%s: %d requests, %d tokens, $%.6f", md.Model, md.Requests, md.TotalTokens(), md.CostUSD)
+	if md.Resolved {
+		s += " (resolved)"
+	}
+	return s
+}
+
+// LogValue implements slog.LogValuer, logging the metadata as a group of its
+// fields.
+func (md CallMetadata) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("model", md.Model),
+		slog.Int64("requests", md.Requests),
+		slog.Int64("prompt_tokens", md.PromptTokens),
+		slog.Int64("completion_tokens", md.CompletionTokens),
+		slog.Float64("cost_usd", md.CostUSD),
+	}
+	if md.TemplateVersion != "" {
+		attrs = append(attrs, slog.String("template_version", md.TemplateVersion))
+	}
+	if md.Resolved {
+		attrs = append(attrs, slog.Bool("resolved", true))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+type callMetadataKey struct{}
+
+// WithCallMetadata returns a context that collects metadata about the queries
+// made with it (see IsEvenAiCore.WithContext) into the returned CallMetadata.
+// The CallMetadata is filled in by the provider while it answers, so read it
+// only after the call has returned, and don't share the context between
+// concurrent calls.
+func WithCallMetadata(ctx context.Context) (context.Context, *CallMetadata) {
+	md := &CallMetadata{}
+	return context.WithValue(ctx, callMetadataKey{}, md), md
+}
+
+// CallMetadataFromContext returns the CallMetadata collecting into ctx, or
+// nil. Providers report their requests to it with Record.
+func CallMetadataFromContext(ctx context.Context) *CallMetadata {
+	md, _ := ctx.Value(callMetadataKey{}).(*CallMetadata)
+	return md
+}
+
+// Record adds one request to model to md, if md is not nil.
+func (md *CallMetadata) Record(model string, promptTokens, completionTokens int64, cost float64) {
+	if md == nil {
+		return
+	}
+	md.Model = model
+	md.Requests++
+	md.PromptTokens += promptTokens
+	md.CompletionTokens += completionTokens
+	md.CostUSD += cost
+}
+
+// Usage summarizes the tokens consumed and the estimated cost of the requests
+// made by a client. Requests to models without a known price are counted but
+// add nothing to CostUSD.
+type Usage struct {
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// TotalTokens returns the sum of prompt and completion tokens.
+func (u Usage) TotalTokens() int64 {
+	return u.PromptTokens + u.CompletionTokens
+}
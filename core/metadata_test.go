@@ -0,0 +1,26 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithCallMetadata(t *testing.T) {
+	ctx, md := WithCallMetadata(context.Background())
+	if got := CallMetadataFromContext(ctx); got != md {
+		t.Fatalf("CallMetadataFromContext(ctx) = %p; want %p", got, md)
+	}
+	md.Record("model-a", 10, 1, 0.5)
+	md.Record("model-b", 20, 2, 0.25)
+	want := CallMetadata{Model: "model-b", Usage: Usage{Requests: 2, PromptTokens: 30, CompletionTokens: 3, CostUSD: 0.75}}
+	if *md != want {
+		t.Errorf("CallMetadata = %+v; want %+v", *md, want)
+	}
+
+	CallMetadataFromContext(context.Background()).Record("model", 1, 1, 1) // Must not panic
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import "context"
+
+// Result is the answer of a QueryFuncV2 together with what the query function
+// knows about how it came about.
+type Result struct {
+	Answer Answer
+	Text   string // Model output the answer was parsed from, if any
+	Model  string // Model that answered; empty if unknown
+	Usage  Usage  // Requests made for the answer; zero if unknown
+}
+
+// QueryFuncV2 is the query function planned for the next major version,
+// where it replaces QueryFunc: it takes the call context for cancellation and
+// CallInfo, and returns a typed answer with its metadata instead of a *bool.
+// Build a core with it using NewIsEvenAiCoreV2, and keep using v1 query
+// functions with AdaptQueryFunc and AdaptQueryHandler.
+type QueryFuncV2 func(ctx context.Context, prompt string) (Result, error)
+
+// AdaptQueryFunc converts a v1 QueryFunc to a QueryFuncV2. The context is
+// not passed on, and the result carries no metadata.
+func AdaptQueryFunc(query QueryFunc) QueryFuncV2 {
+	return func(_ context.Context, prompt string) (Result, error) {
+		res, err := query(prompt)
+		return Result{Answer: AnswerOf(res)}, err
+	}
+}
+
+// AdaptQueryHandler converts a QueryHandler to a QueryFuncV2. The result
+// carries no metadata; handlers report it to CallMetadataFromContext.
+func AdaptQueryHandler(query QueryHandler) QueryFuncV2 {
+	return func(ctx context.Context, prompt string) (Result, error) {
+		res, err := query(ctx, prompt)
+		return Result{Answer: AnswerOf(res)}, err
+	}
+}
+
+// Handler converts q to the QueryHandler wrapped by Middleware. The Model and
+// Usage of each result are added to the CallMetadata of the context.
+func (q QueryFuncV2) Handler() QueryHandler {
+	return func(ctx context.Context, prompt string) (*bool, error) {
+		res, err := q(ctx, prompt)
+		if md := CallMetadataFromContext(ctx); md != nil {
+			if res.Model != "" {
+				md.Model = res.Model
+			}
+			md.Requests += res.Usage.Requests
+			md.PromptTokens += res.Usage.PromptTokens
+			md.CompletionTokens += res.Usage.CompletionTokens
+			md.CostUSD += res.Usage.CostUSD
+		}
+		if err != nil {
+			return nil, err
+		}
+		return res.Answer.Bool(), nil
+	}
+}
+
+// NewIsEvenAiCoreV2 is like NewIsEvenAiCore for a QueryFuncV2. It panics if
+// query is nil.
+func NewIsEvenAiCoreV2(templates IsEvenAiCorePromptTemplates, query QueryFuncV2) *IsEvenAiCore {
+	if query == nil {
+		panic("query function cannot be nil")
+	}
+	return NewIsEvenAiCoreWithHandler(templates, query.Handler(), AnswerSource{})
+}
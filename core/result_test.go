@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewIsEvenAiCoreV2(t *testing.T) {
+	core := NewIsEvenAiCoreV2(testPromptTemplates, func(ctx context.Context, prompt string) (Result, error) {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		info, _ := CallInfoFromContext(ctx)
+		truth, err := GroundTruth(info.Predicate, info.Args)
+		answer := AnswerOf(&truth)
+		return Result{Answer: answer, Text: answer.String(), Model: "model", Usage: Usage{Requests: 1, PromptTokens: 10, CompletionTokens: 1, CostUSD: 0.5}}, err
+	})
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := core.WithContext(ctx).IsOdd(3)
+	if err != nil || res == nil || !*res {
+		t.Fatalf("IsOdd(3) = %v, %v; want true", res, err)
+	}
+	want := CallMetadata{Model: "model", Usage: Usage{Requests: 1, PromptTokens: 10, CompletionTokens: 1, CostUSD: 0.5}}
+	if *md != want {
+		t.Errorf("CallMetadata = %+v; want %+v", *md, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := core.WithContext(ctx).IsEven(2); !errors.Is(err, context.Canceled) {
+		t.Errorf("IsEven() with canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestAdaptQueryFunc(t *testing.T) {
+	yes := true
+	tests := []struct {
+		res    *bool
+		err    error
+		answer Answer
+	}{
+		{&yes, nil, AnswerTrue},
+		{nil, nil, AnswerUndefined},
+		{nil, errors.New("boom"), AnswerUndefined},
+	}
+	for _, tc := range tests {
+		v1 := func(string) (*bool, error) { return tc.res, tc.err }
+		got, err := AdaptQueryFunc(v1)(context.Background(), "prompt")
+		if got != (Result{Answer: tc.answer}) || err != tc.err {
+			t.Errorf("AdaptQueryFunc() returned %+v, %v; want %v, %v", got, err, tc.answer, tc.err)
+		}
+		got, err = AdaptQueryHandler(func(context.Context, string) (*bool, error) { return v1("") })(context.Background(), "prompt")
+		if got != (Result{Answer: tc.answer}) || err != tc.err {
+			t.Errorf("AdaptQueryHandler() returned %+v, %v; want %v, %v", got, err, tc.answer, tc.err)
+		}
+	}
+
+	// A v1 function keeps working through the v2 constructor.
+	core := NewIsEvenAiCoreV2(testPromptTemplates, AdaptQueryFunc(func(string) (*bool, error) { return &yes, nil }))
+	if res, err := core.IsEven(2); err != nil || res == nil || !*res {
+		t.Errorf("IsEven(2) = %v, %v; want true", res, err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// Drainer tracks the queries in flight so that they can be drained on
+// shutdown. The zero value accepts queries.
+type Drainer struct {
+	mu       sync.Mutex
+	closing  bool
+	inFlight int
+	idle     chan struct{} // Closed once closing with nothing in flight
+}
+
+// Enter registers a query, or fails with ErrShutdown once draining has begun.
+func (d *Drainer) Enter() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closing {
+		return ErrShutdown
+	}
+	d.inFlight++
+	return nil
+}
+
+// Leave unregisters a query registered with Enter.
+func (d *Drainer) Leave() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.closing && d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+// Drain stops accepting queries and waits until those in flight are done, or
+// until ctx is done.
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closing {
+		d.closing = true
+		d.idle = make(chan struct{})
+		if d.inFlight == 0 {
+			close(d.idle)
+		}
+	}
+	idle := d.idle
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Draining reports whether Drain has been called.
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closing
+}
+
+// Drainer returns the Drainer tracking the queries of c, which is shared with
+// the copies made by WithContext. Providers register queries made outside the
+// predicates with it, so that Shutdown waits for them too.
+func (c *IsEvenAiCore) Drainer() *Drainer {
+	return c.drainer
+}
+
+// Shutdown stops accepting queries, which then fail with ErrShutdown, and
+// waits for the queries in flight to finish or for ctx to be done, in which
+// case it returns ctx.Err(). It does not release any resources; providers
+// built on IsEvenAiCore offer their own Shutdown that also closes them.
+func (c *IsEvenAiCore) Shutdown(ctx context.Context) error {
+	return c.drainer.Drain(ctx)
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingHandler answers true once unblock is closed, reporting each query
+// on started.
+func blockingHandler(started chan<- int, unblock <-chan struct{}) QueryHandler {
+	return func(ctx context.Context, _ string) (*bool, error) {
+		info, _ := CallInfoFromContext(ctx)
+		started <- info.Args[0]
+		<-unblock
+		yes := true
+		return &yes, nil
+	}
+}
+
+// waitDraining waits until d has started draining.
+func waitDraining(t *testing.T, d *Drainer) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if d.Draining() {
+			return
+		}
+	}
+	t.Fatal("Timed out waiting for Shutdown to start draining")
+}
+
+func TestIsEvenAiCore_Shutdown(t *testing.T) {
+	started, unblock := make(chan int, 1), make(chan struct{})
+	core := newIsEvenAiCore(testPromptTemplates, blockingHandler(started, unblock))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := core.WithContext(context.Background()).IsEven(2)
+		done <- err
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- core.Shutdown(context.Background()) }()
+	waitDraining(t, core.Drainer())
+	if _, err := core.IsOdd(3); !errors.Is(err, ErrShutdown) {
+		t.Errorf("IsOdd() during Shutdown() = %v, want ErrShutdown", err)
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() returned %v with a query in flight", err)
+	default:
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Errorf("In-flight query failed: %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() failed: %v", err)
+	}
+}
+
+func TestIsEvenAiCore_ShutdownTimeout(t *testing.T) {
+	started, unblock := make(chan int, 1), make(chan struct{})
+	defer close(unblock)
+	core := newIsEvenAiCore(testPromptTemplates, blockingHandler(started, unblock))
+	go core.IsEven(2)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := core.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import "context"
+
+type onTokenKey struct{}
+
+// WithOnToken returns a context whose queries (see IsEvenAiCore.WithContext)
+// pass the model output to onToken while it is generated, e.g. to show the
+// model thinking before the answer is parsed:
+//
+//	ctx := core.WithOnToken(ctx, func(chunk string) { fmt.Print(chunk) })
+//	even, err := ai.WithContext(ctx).IsEven(7)
+//
+// onToken is called with consecutive chunks of the text, from the goroutine
+// making the query. Providers that do not stream call it once with the whole
+// text, or not at all if there is none.
+func WithOnToken(ctx context.Context, onToken func(chunk string)) context.Context {
+	return context.WithValue(ctx, onTokenKey{}, onToken)
+}
+
+// OnTokenFromContext returns the callback set with WithOnToken, or nil.
+// Providers that stream pass each chunk of model output to it.
+func OnTokenFromContext(ctx context.Context) func(chunk string) {
+	onToken, _ := ctx.Value(onTokenKey{}).(func(string))
+	return onToken
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithOnToken(t *testing.T) {
+	if OnTokenFromContext(context.Background()) != nil {
+		t.Error("OnTokenFromContext() without WithOnToken is not nil")
+	}
+
+	var chunks []string
+	core := newIsEvenAiCore(testPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		onToken := OnTokenFromContext(ctx)
+		for _, chunk := range []string{"Seven is odd, ", "so: ", "false"} {
+			onToken(chunk)
+		}
+		no := false
+		return &no, nil
+	})
+	ctx := WithOnToken(context.Background(), func(chunk string) { chunks = append(chunks, chunk) })
+	if res, err := core.WithContext(ctx).IsEven(7); err != nil || res == nil || *res {
+		t.Fatalf("IsEven(7) = %v, %v; want false", res, err)
+	}
+	if len(chunks) != 3 || chunks[2] != "false" {
+		t.Errorf("Streamed chunks = %q", chunks)
+	}
+}
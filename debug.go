@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// debugTransport logs every HTTP exchange with the API, including full request
+// and response bodies. Headers are not logged, so credentials in Authorization
+// or API key headers never reach the log; the logger is expected to redact the
+// key from URLs and bodies (see redactHandler). Response bodies are buffered,
+// so streamed responses are only passed on once complete.
+type debugTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+// newDebugLogger returns logger, or a debug-level text logger writing to
+// stderr if it is nil, so that debug mode produces output without further setup.
+func newDebugLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	t.logger.Debug("api request", "method", req.Method, "url", req.URL.String(), "body", string(reqBody))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.logger.Debug("api request failed", "url", req.URL.String(), "latency", time.Since(start), "err", err)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.logger.Debug("api response failed", "url", req.URL.String(), "status", resp.StatusCode, "latency", time.Since(start), "err", err)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.logger.Debug("api response", "status", resp.StatusCode, "latency", time.Since(start), "body", string(respBody))
+	return resp, nil
+}
+
+// redactHandler is a slog.Handler that replaces a secret in messages and
+// attribute values before passing records on, so that API keys echoed in URLs,
+// response bodies or error messages never reach the log.
+type redactHandler struct {
+	slog.Handler
+	secret string
+}
+
+// newRedactingLogger returns a logger that writes to logger with secret redacted.
+func newRedactingLogger(logger *slog.Logger, secret string) *slog.Logger {
+	if secret == "" {
+		return logger
+	}
+	return slog.New(&redactHandler{Handler: logger.Handler(), secret: secret})
+}
+
+// Handle implements slog.Handler.
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, strings.ReplaceAll(r.Message, h.secret, redacted), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redactAttr(a)
+	}
+	return &redactHandler{Handler: h.Handler.WithAttrs(redactedAttrs), secret: h.secret}
+}
+
+// WithGroup implements slog.Handler.
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{Handler: h.Handler.WithGroup(name), secret: h.secret}
+}
+
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		redactedGroup := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redactedGroup[i] = h.redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redactedGroup...)}
+	case slog.KindString, slog.KindAny:
+		if s := v.String(); strings.Contains(s, h.secret) {
+			return slog.String(a.Key, strings.ReplaceAll(s, h.secret, redacted))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// apiKeyTransport authenticates requests with a Gemini API key. It replaces the
+// SDK's own API key handling when a custom HTTP client is in use.
+type apiKeyTransport struct {
+	base   http.RoundTripper
+	apiKey string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("x-goog-api-key", t.apiKey)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newRedactingLogger(slog.New(slog.NewTextHandler(&buf, nil)), "s3cr3t")
+
+	logger.With("url", "https://example.com/?key=s3cr3t").
+		WithGroup("req").
+		Info("key s3cr3t rejected", "err", errors.New("bad key s3cr3t"), slog.Group("g", "body", "s3cr3t"), "n", 42)
+
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") {
+		t.Errorf("Log output leaks the secret: %s", out)
+	}
+	for _, want := range []string{`msg="key REDACTED rejected"`, `url="https://example.com/?key=REDACTED"`, `req.err="bad key REDACTED"`, `req.g.body=REDACTED`, `req.n=42`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log output to contain %q, got: %s", want, out)
+		}
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// failingBody fails reads after returning its prefix.
+type failingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		err = errors.New("connection reset")
+	}
+	return n, err
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDebugTransport_ResponseReadError(t *testing.T) {
+	var logs bytes.Buffer
+	body := &failingBody{Reader: strings.NewReader(`{"candidates":`)}
+	transport := &debugTransport{
+		base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+		}),
+		logger: slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	req, err := http.NewRequest("GET", "https://example.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if resp != nil || err == nil {
+		t.Errorf("RoundTrip = %v, %v; want nil and the read error", resp, err)
+	}
+	if !body.closed {
+		t.Error("Expected the half-read response body to be closed")
+	}
+	if !strings.Contains(logs.String(), `msg="api response failed"`) {
+		t.Errorf("Expected the read error to be logged, got: %s", logs.String())
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// DegradedMode is a switch that keeps queries from the provider while it is
+// on, e.g. during a provider outage or when the budget runs low. Add its
+// Middleware after the cache, so that cached answers are still served while
+// other queries fail with ErrDegraded:
+//
+//	degraded := &is_even_ai.DegradedMode{}
+//	ai.Use(is_even_ai.CacheMiddleware(cache), degraded.Middleware())
+//	...
+//	degraded.Set(true)
+//
+// The zero value is off. DegradedMode is safe for concurrent use.
+type DegradedMode struct {
+	on atomic.Bool
+}
+
+// Set turns degraded mode on or off.
+func (d *DegradedMode) Set(on bool) {
+	d.on.Store(on)
+}
+
+// Enabled reports whether degraded mode is on.
+func (d *DegradedMode) Enabled() bool {
+	return d.on.Load()
+}
+
+// Middleware fails queries with ErrDegraded while degraded mode is on.
+func (d *DegradedMode) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			if d.Enabled() {
+				return nil, ErrDegraded
+			}
+			return next(ctx, prompt)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDegradedMode(t *testing.T) {
+	calls := 0
+	degraded := &DegradedMode{}
+	ai := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true)))
+	ai.Use(CacheMiddleware(NewMemoryCache()), degraded.Middleware())
+
+	_, _ = ai.IsEven(2)
+	degraded.Set(true)
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if _, err := ai.IsEven(4); !errors.Is(err, ErrDegraded) {
+		t.Errorf("IsEven(4) in degraded mode = %v, want ErrDegraded", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected only the first query to reach the provider, got %d", calls)
+	}
+
+	degraded.Set(false)
+	res, err = ai.IsEven(4)
+	checkGeminiResult(t, res, err, true, "IsEven", 4)
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// defaultDivergenceWindow is the default of DivergenceMonitor.Window.
+const defaultDivergenceWindow = 100
+
+// DivergenceMonitor checks answers against arithmetic and alerts when too many
+// of the recent ones contradict it, e.g. when a model update starts believing
+// that odd numbers are even. Enable the check with Middleware:
+//
+//	monitor := &is_even_ai.DivergenceMonitor{
+//		Threshold: 0.05,
+//		OnAlert: func(a is_even_ai.DivergenceAlert) {
+//			page("%.0f%% of recent answers contradict arithmetic", 100*a.Rate)
+//		},
+//	}
+//	ai.Use(monitor.Middleware())
+//
+// Answers are returned unchanged. Undefined answers, errors and prompts without
+// CallInfo are not checked. The zero value checks answers but never alerts.
+// DivergenceMonitor is safe for concurrent use.
+type DivergenceMonitor struct {
+	// Window is the number of recent answers the divergence rate is computed
+	// over. Defaults to 100.
+	Window int
+	// Threshold is the divergence rate, between 0 and 1, at which OnAlert is
+	// called. Zero disables alerts.
+	Threshold float64
+	// OnAlert is called when a full window of answers reaches Threshold. It is
+	// not called again until the rate has dropped below Threshold. It runs
+	// synchronously in the query that crossed the threshold, so it should not
+	// block for long.
+	OnAlert func(DivergenceAlert)
+
+	mu        sync.Mutex
+	recent    []bool // Ring buffer of the last Window answers; true if divergent
+	next      int    // Position of the next answer in recent
+	divergent int    // Divergent answers in recent
+	alerting  bool
+	checked   int64
+	total     int64 // Divergent answers overall
+}
+
+// DivergenceAlert describes the answers that triggered DivergenceMonitor.OnAlert.
+type DivergenceAlert struct {
+	Rate      float64 // Divergent fraction of the last Window answers
+	Window    int
+	Divergent int
+	Last      CallInfo // The question whose answer crossed the threshold
+}
+
+// DivergenceCounts are the totals of a DivergenceMonitor.
+type DivergenceCounts struct {
+	Checked   int64   `json:"checked"`   // Answers compared to arithmetic
+	Divergent int64   `json:"divergent"` // Answers that contradicted it
+	Rate      float64 `json:"rate"`      // Divergent fraction of the current window
+}
+
+// Middleware checks every defined answer passing through it.
+func (m *DivergenceMonitor) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			res, err := next(ctx, prompt)
+			if err != nil || res == nil {
+				return res, err
+			}
+			info, ok := CallInfoFromContext(ctx)
+			if !ok {
+				return res, err
+			}
+			if want, truthErr := core.GroundTruth(info.Predicate, info.Args); truthErr == nil {
+				m.observe(info, *res != want)
+			}
+			return res, err
+		}
+	}
+}
+
+// observe adds one checked answer and calls OnAlert if it crossed the threshold.
+func (m *DivergenceMonitor) observe(info CallInfo, divergent bool) {
+	m.mu.Lock()
+	if m.recent == nil {
+		window := m.Window
+		if window <= 0 {
+			window = defaultDivergenceWindow
+		}
+		m.recent = make([]bool, 0, window)
+	}
+	if len(m.recent) < cap(m.recent) {
+		m.recent = append(m.recent, divergent)
+	} else {
+		if m.recent[m.next] {
+			m.divergent--
+		}
+		m.recent[m.next] = divergent
+	}
+	m.next = (m.next + 1) % cap(m.recent)
+	m.checked++
+	if divergent {
+		m.divergent++
+		m.total++
+	}
+
+	var alert *DivergenceAlert
+	rate := float64(m.divergent) / float64(len(m.recent))
+	switch {
+	case m.Threshold <= 0:
+	case rate < m.Threshold:
+		m.alerting = false
+	case !m.alerting && len(m.recent) == cap(m.recent):
+		m.alerting = true
+		alert = &DivergenceAlert{Rate: rate, Window: len(m.recent), Divergent: m.divergent, Last: info}
+	}
+	m.mu.Unlock()
+
+	if alert != nil && m.OnAlert != nil {
+		m.OnAlert(*alert)
+	}
+}
+
+// Counts returns the number of checked and divergent answers, and the
+// divergence rate of the current window.
+func (m *DivergenceMonitor) Counts() DivergenceCounts {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := DivergenceCounts{Checked: m.checked, Divergent: m.total}
+	if len(m.recent) > 0 {
+		counts.Rate = float64(m.divergent) / float64(len(m.recent))
+	}
+	return counts
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDivergenceMonitor(t *testing.T) {
+	// The model believes that every number is even.
+	core := newIsEvenAiCore(DefaultGeminiPromptTemplates, func(context.Context, string) (*bool, error) {
+		yes := true
+		return &yes, nil
+	})
+	var alerts []DivergenceAlert
+	monitor := &DivergenceMonitor{Window: 4, Threshold: 0.5, OnAlert: func(a DivergenceAlert) { alerts = append(alerts, a) }}
+	core.Use(monitor.Middleware())
+
+	for _, n := range []int{2, 4, 1, 6} { // 1 of 4 wrong
+		_, _ = core.IsEven(n)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("Expected no alert below the threshold, got %v", alerts)
+	}
+	_, _ = core.IsEven(3) // 2 of the last 4 wrong
+	if len(alerts) != 1 || alerts[0].Rate != 0.5 || alerts[0].Divergent != 2 || alerts[0].Last.Args[0] != 3 {
+		t.Fatalf("Expected one alert at 2 of 4, got %+v", alerts)
+	}
+	_, _ = core.IsEven(5) // 3 of the last 4 wrong, still alerting
+	if len(alerts) != 1 {
+		t.Errorf("Expected no repeated alert, got %d", len(alerts))
+	}
+
+	for _, n := range []int{8, 10, 12, 14} { // Recovers
+		_, _ = core.IsEven(n)
+	}
+	_, _ = core.IsEven(7)
+	_, _ = core.IsEven(9)
+	if len(alerts) != 2 {
+		t.Errorf("Expected a new alert after recovering, got %d", len(alerts))
+	}
+
+	want := DivergenceCounts{Checked: 12, Divergent: 5, Rate: 0.5}
+	if got := monitor.Counts(); got != want {
+		t.Errorf("Counts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDivergenceMonitor_SkipsUndefined(t *testing.T) {
+	core := newIsEvenAiCore(DefaultGeminiPromptTemplates, func(context.Context, string) (*bool, error) { return nil, nil })
+	monitor := &DivergenceMonitor{}
+	core.Use(monitor.Middleware())
+	_, _ = core.IsEven(1)
+	if got := monitor.Counts(); got.Checked != 0 {
+		t.Errorf("Counts() = %+v, want undefined answers to be skipped", got)
+	}
+}
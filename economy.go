@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import "errors"
+
+// defaultTrivialLimit makes single-digit numbers trivial.
+const defaultTrivialLimit = 9
+
+// EconomyOptions configures IsEvenAiEconomy.
+type EconomyOptions struct {
+	// TrivialLimit is the largest absolute value a question may involve to be
+	// sent to the cheap provider first. Defaults to 9 (single-digit numbers).
+	TrivialLimit int
+}
+
+// IsEvenAiEconomy routes trivial questions (all numbers within TrivialLimit) to
+// a cheap provider and escalates to a strong provider only when the cheap one
+// returns an undefined answer. Everything else goes straight to the strong provider.
+type IsEvenAiEconomy struct {
+	cheap        Provider
+	strong       Provider
+	trivialLimit int
+}
+
+var _ Provider = (*IsEvenAiEconomy)(nil)
+
+// NewIsEvenAiEconomy creates an economy-mode provider on top of a cheap and a strong provider,
+// for example two IsEvenAiGemini instances configured with different models.
+func NewIsEvenAiEconomy(cheap, strong Provider, opts ...EconomyOptions) *IsEvenAiEconomy {
+	if cheap == nil || strong == nil {
+		panic("cheap and strong providers cannot be nil")
+	}
+	e := &IsEvenAiEconomy{cheap: cheap, strong: strong, trivialLimit: defaultTrivialLimit}
+	if len(opts) > 0 && opts[0].TrivialLimit > 0 {
+		e.trivialLimit = opts[0].TrivialLimit
+	}
+	return e
+}
+
+// route asks the cheap provider first if all args are trivial, escalating to the
+// strong provider if the cheap answer is undefined.
+func (e *IsEvenAiEconomy) route(ask func(Provider) (*bool, error), args ...int) (*bool, error) {
+	for _, n := range args {
+		if n > e.trivialLimit || n < -e.trivialLimit {
+			return ask(e.strong)
+		}
+	}
+	res, err := ask(e.cheap)
+	if err != nil || res != nil {
+		return res, err
+	}
+	return ask(e.strong)
+}
+
+// IsEven checks if n is even.
+func (e *IsEvenAiEconomy) IsEven(n int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.IsEven(n) }, n)
+}
+
+// IsOdd checks if n is odd.
+func (e *IsEvenAiEconomy) IsOdd(n int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.IsOdd(n) }, n)
+}
+
+// AreEqual checks if a and b are equal.
+func (e *IsEvenAiEconomy) AreEqual(a, b int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.AreEqual(a, b) }, a, b)
+}
+
+// AreNotEqual checks if a and b are not equal.
+func (e *IsEvenAiEconomy) AreNotEqual(a, b int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.AreNotEqual(a, b) }, a, b)
+}
+
+// IsGreaterThan checks if a is greater than b.
+func (e *IsEvenAiEconomy) IsGreaterThan(a, b int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.IsGreaterThan(a, b) }, a, b)
+}
+
+// IsLessThan checks if a is less than b.
+func (e *IsEvenAiEconomy) IsLessThan(a, b int) (*bool, error) {
+	return e.route(func(p Provider) (*bool, error) { return p.IsLessThan(a, b) }, a, b)
+}
+
+// Close closes both underlying providers.
+func (e *IsEvenAiEconomy) Close() error {
+	return errors.Join(e.cheap.Close(), e.strong.Close())
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsEvenAiEconomy_Routing(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cheapAnswer func(string, ...int) (*bool, error)
+		call        func(e *IsEvenAiEconomy) (*bool, error)
+		wantCheap   int
+		wantStrong  int
+	}{
+		{"TrivialAnsweredByCheap", answerAlways(true), func(e *IsEvenAiEconomy) (*bool, error) { return e.IsEven(4) }, 1, 0},
+		{"TrivialNegativeAnsweredByCheap", answerAlways(true), func(e *IsEvenAiEconomy) (*bool, error) { return e.IsOdd(-7) }, 1, 0},
+		{"TrivialUndefinedEscalates", nil, func(e *IsEvenAiEconomy) (*bool, error) { return e.IsEven(4) }, 1, 1},
+		{"LargeNumberGoesStrong", answerAlways(true), func(e *IsEvenAiEconomy) (*bool, error) { return e.IsEven(42) }, 0, 1},
+		{"OneLargeArgumentGoesStrong", answerAlways(true), func(e *IsEvenAiEconomy) (*bool, error) { return e.IsGreaterThan(3, 10) }, 0, 1},
+		{"TrivialPairAnsweredByCheap", answerAlways(true), func(e *IsEvenAiEconomy) (*bool, error) { return e.AreEqual(3, 3) }, 1, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cheap := &stubProvider{answer: tc.cheapAnswer}
+			strong := &stubProvider{answer: answerAlways(true)}
+			e := NewIsEvenAiEconomy(cheap, strong)
+
+			res, err := tc.call(e)
+			if err != nil || res == nil || !*res {
+				t.Fatalf("Expected true answer, got %v, %v", res, err)
+			}
+			if got := len(cheap.callLog()); got != tc.wantCheap {
+				t.Errorf("Cheap provider called %d times; want %d", got, tc.wantCheap)
+			}
+			if got := len(strong.callLog()); got != tc.wantStrong {
+				t.Errorf("Strong provider called %d times; want %d", got, tc.wantStrong)
+			}
+		})
+	}
+}
+
+func TestIsEvenAiEconomy_CheapErrorIsReturned(t *testing.T) {
+	cheapErr := errors.New("cheap model failed")
+	cheap := &stubProvider{answer: func(string, ...int) (*bool, error) { return nil, cheapErr }}
+	strong := &stubProvider{answer: answerAlways(true)}
+	e := NewIsEvenAiEconomy(cheap, strong, EconomyOptions{TrivialLimit: 99})
+
+	if _, err := e.IsEven(42); !errors.Is(err, cheapErr) {
+		t.Errorf("Expected cheap provider error, got %v", err)
+	}
+	if len(strong.callLog()) != 0 {
+		t.Error("Strong provider should not be asked when the cheap one fails")
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if !cheap.closed || !strong.closed {
+		t.Error("Close should close both providers")
+	}
+}
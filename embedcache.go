@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// Embedder computes embedding vectors of text, e.g. IsEvenAiGemini.Embed.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbeddingCache is an experimental cache that answers a question from the
+// answer to the most similar earlier question, as measured by the cosine
+// similarity of their embeddings, if that similarity reaches a threshold.
+// Otherwise the model is asked and its answer remembered. Only defined
+// answers are remembered.
+//
+// Questions about different numbers can be very similar, e.g. "Is 2 an even
+// number?" and "Is 3 an even number?", so set the threshold close to 1 and
+// check the accuracy (see IsEvenAiCore.Audit) before relying on it. Answers
+// are only reused for the same predicate and AnswerSource (see CacheKey).
+// Lookups are linear in the number of remembered answers.
+//
+// EmbeddingCache is safe for concurrent use.
+type EmbeddingCache struct {
+	embedder  Embedder
+	threshold float64
+
+	mu      sync.RWMutex
+	entries map[string][]embeddingEntry // By predicate and answer source
+}
+
+type embeddingEntry struct {
+	vector []float32
+	norm   float64
+	answer bool
+	info   CallInfo // Question answered
+}
+
+var _ CacheInvalidator = (*EmbeddingCache)(nil)
+
+// NewEmbeddingCache returns an empty EmbeddingCache using embedder, which
+// reuses answers to questions with a cosine similarity of at least threshold.
+func NewEmbeddingCache(embedder Embedder, threshold float64) *EmbeddingCache {
+	return &EmbeddingCache{embedder: embedder, threshold: threshold, entries: make(map[string][]embeddingEntry)}
+}
+
+// Middleware returns middleware that answers from c when it can. Embedding
+// errors are not fatal: the question is then asked without using the cache.
+func (c *EmbeddingCache) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			info, _ := CallInfoFromContext(ctx)
+			partition := info.Predicate + ":" + CacheKey(ctx, "")
+			vector, err := c.embedder.Embed(ctx, prompt)
+			if err != nil {
+				return next(ctx, prompt)
+			}
+			norm := vectorNorm(vector)
+			if answer, ok := c.lookup(partition, vector, norm); ok {
+				eventsFromContext(ctx).CacheHit(ctx, info, core.AnswerOf(&answer))
+				return &answer, nil
+			}
+			res, err := next(ctx, prompt)
+			if err == nil && res != nil && norm > 0 {
+				c.mu.Lock()
+				c.entries[partition] = append(c.entries[partition], embeddingEntry{vector: vector, norm: norm, answer: *res, info: info})
+				c.mu.Unlock()
+			}
+			return res, err
+		}
+	}
+}
+
+// lookup returns the answer of the entry in partition most similar to
+// vector, if it is similar enough.
+func (c *EmbeddingCache) lookup(partition string, vector []float32, norm float64) (bool, bool) {
+	if norm == 0 {
+		return false, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	best, found := math.Inf(-1), false
+	var answer bool
+	for _, e := range c.entries[partition] {
+		if len(e.vector) != len(vector) {
+			continue
+		}
+		var dot float64
+		for i, v := range vector {
+			dot += float64(v) * float64(e.vector[i])
+		}
+		if sim := dot / (norm * e.norm); sim >= c.threshold && sim > best {
+			best, found, answer = sim, true, e.answer
+		}
+	}
+	return answer, found
+}
+
+// Len returns the number of remembered answers.
+func (c *EmbeddingCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	n := 0
+	for _, entries := range c.entries {
+		n += len(entries)
+	}
+	return n
+}
+
+// InvalidateNumber implements CacheInvalidator. Questions about other
+// numbers may still be answered like those about n were, if they are similar
+// enough to remaining ones.
+func (c *EmbeddingCache) InvalidateNumber(_ context.Context, n int) error {
+	c.deleteFunc(func(e embeddingEntry) bool { return slices.Contains(e.info.Args, n) })
+	return nil
+}
+
+// InvalidatePredicate implements CacheInvalidator.
+func (c *EmbeddingCache) InvalidatePredicate(_ context.Context, predicate string) error {
+	c.deleteFunc(func(e embeddingEntry) bool { return e.info.Predicate == predicate })
+	return nil
+}
+
+// FlushCache implements CacheInvalidator.
+func (c *EmbeddingCache) FlushCache(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	return nil
+}
+
+// deleteFunc removes the entries for which del returns true.
+func (c *EmbeddingCache) deleteFunc(del func(embeddingEntry) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for partition, entries := range c.entries {
+		if entries = slices.DeleteFunc(entries, del); len(entries) > 0 {
+			c.entries[partition] = entries
+		} else {
+			delete(c.entries, partition)
+		}
+	}
+}
+
+// vectorNorm returns the Euclidean norm of v.
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeEmbedder embeds prompts as given by its map, failing for other prompts.
+type fakeEmbedder map[string][]float32
+
+func (e fakeEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	if v, ok := e[text]; ok {
+		return v, nil
+	}
+	return nil, errors.New("no embedding")
+}
+
+func TestEmbeddingCache(t *testing.T) {
+	embedder := fakeEmbedder{
+		"Is 2 an even number?":   {1, 0, 0},
+		"Is 2 an odd number?":    {1, 0, 0}, // Identical, but another predicate
+		"Is two an even number?": {0.99, 0.1, 0},
+		"Is 3 an even number?":   {0, 1, 0},
+	}
+	calls := 0
+	spellOut := false // Phrases the next question differently
+	templates := DefaultGeminiPromptTemplates
+	templates.IsEven = func(n int) string {
+		if spellOut {
+			spellOut = false
+			return "Is two an even number?"
+		}
+		return DefaultGeminiPromptTemplates.IsEven(n)
+	}
+	core := newIsEvenAiCore(templates, countingHandler(&calls, answered(true)))
+	cache := NewEmbeddingCache(embedder, 0.95)
+	core.Use(cache.Middleware())
+
+	steps := []struct {
+		ask       func() (*bool, error)
+		wantCalls int
+	}{
+		{func() (*bool, error) { return core.IsEven(2) }, 1},
+		{func() (*bool, error) { return core.IsEven(2) }, 1},
+		{func() (*bool, error) { spellOut = true; return core.IsEven(2) }, 1},
+		{func() (*bool, error) { return core.IsEven(3) }, 2},
+		{func() (*bool, error) { return core.IsOdd(2) }, 3},
+		{func() (*bool, error) { return core.IsEven(4) }, 4}, // Not embeddable
+		{func() (*bool, error) { return core.IsEven(4) }, 5},
+	}
+	for i, s := range steps {
+		res, err := s.ask()
+		if err != nil || res == nil || !*res {
+			t.Errorf("Step %d: got %v, %v; want true", i, res, err)
+		}
+		if calls != s.wantCalls {
+			t.Errorf("Step %d: %d queries reached the provider, want %d", i, calls, s.wantCalls)
+		}
+	}
+	if cache.Len() != 3 {
+		t.Errorf("Expected 3 remembered answers, got %d", cache.Len())
+	}
+}
+
+func TestEmbeddingCache_Invalidate(t *testing.T) {
+	embedder := fakeEmbedder{
+		"Is 2 an even number?": {1, 0, 0},
+		"Is 3 an even number?": {0, 1, 0},
+		"Is 2 an odd number?":  {1, 0, 0},
+	}
+	calls := 0
+	ai := newIsEvenAiCore(DefaultGeminiPromptTemplates, countingHandler(&calls, answered(true)))
+	cache := NewEmbeddingCache(embedder, 0.95)
+	ai.Use(cache.Middleware())
+	ai.RegisterCache(cache)
+	_, _ = ai.IsEven(2)
+	_, _ = ai.IsEven(3)
+	_, _ = ai.IsOdd(2)
+
+	if err := ai.InvalidateNumber(3); err != nil || cache.Len() != 2 {
+		t.Errorf("InvalidateNumber(3) = %v, leaving %d answers; want 2", err, cache.Len())
+	}
+	if err := ai.InvalidatePredicate("isOdd"); err != nil || cache.Len() != 1 {
+		t.Errorf("InvalidatePredicate(isOdd) = %v, leaving %d answers; want 1", err, cache.Len())
+	}
+	if err := ai.FlushCache(); err != nil || cache.Len() != 0 {
+		t.Errorf("FlushCache() = %v, leaving %d answers; want none", err, cache.Len())
+	}
+}
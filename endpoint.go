@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// parseEndpoint parses the endpoint option name, which must be an absolute URL.
+func parseEndpoint(name, endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid %s %q: must be an absolute URL", name, endpoint)
+	}
+	return u, nil
+}
+
+// endpointTransport sends generateContent requests to a fixed URL, for
+// gateways that don't follow the Gemini API's URL layout.
+type endpointTransport struct {
+	base     http.RoundTripper
+	endpoint *url.URL
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, ":generateContent") {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	query := req.URL.RawQuery
+	req.URL = t.endpoint.JoinPath() // A copy
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = query
+	}
+	req.Host = ""
+	return t.base.RoundTrip(req)
+}
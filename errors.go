@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// ErrModelNotFound is returned when the configured model does not exist or is
+// not available to the credentials in use.
+var ErrModelNotFound = errors.New("model not found")
+
+// ErrBudgetExceeded is returned instead of making a request once the configured
+// Budget has been used up.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// ErrRateLimited is returned when the provider rejected a request because of
+// rate limits or exhausted quota (HTTP 429).
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrUnauthenticated is returned when the provider rejected the credentials
+// in use (HTTP 401 or 403).
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrInvalidRequest is returned when the provider rejected a request as
+// malformed (HTTP 400). Sending it again will not help.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ErrContextTooLong is returned when a prompt exceeds the model's context window.
+var ErrContextTooLong = errors.New("context length exceeded")
+
+// ErrContentFiltered is returned when the provider's safety filters blocked
+// the prompt or the answer.
+var ErrContentFiltered = errors.New("content filtered")
+
+// ErrUnavailable is returned when the provider failed with a server error
+// (HTTP 5xx), which is usually temporary.
+var ErrUnavailable = errors.New("provider unavailable")
+
+// ErrQueueFull is returned by AdmissionControl when a query arrives while the
+// maximum number of queries is both in flight and waiting.
+var ErrQueueFull = errors.New("admission queue full")
+
+// ErrContradiction is returned by ConsistencyChecker with FailOnContradiction
+// when a question and its complement got the same answer.
+var ErrContradiction = errors.New("contradictory answers")
+
+// ErrUnknownTemplateVersion is returned by PromptTemplateHistory for versions
+// it does not hold.
+var ErrUnknownTemplateVersion = errors.New("unknown prompt template version")
+
+// ErrInvalidSignature is returned by VerifyWebhook for requests without a
+// valid, recent signature.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrJobNotFound is returned by JobStore and JobQueue for unknown job IDs.
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrDegraded is returned by DegradedMode for queries it keeps from the
+// provider.
+var ErrDegraded = errors.New("degraded mode: only cached answers are served")
+
+// ErrAnswerRejected is returned by AskInt when none of the model's answers
+// passed validation.
+var ErrAnswerRejected = errors.New("answer rejected")
+
+// ErrNotGenerated is returned by GenerateEven and GenerateOdd when no number
+// of the requested parity and range could be produced.
+var ErrNotGenerated = errors.New("no number generated")
+
+// ErrUnknownTenant is returned by Tenants for queries without a tenant, or
+// with one it does not know.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// ErrTenantRateLimited is returned by Tenants when a tenant exceeds its rate
+// limit. It wraps ErrRateLimited.
+var ErrTenantRateLimited = fmt.Errorf("tenant %w", ErrRateLimited)
+
+// Errors of the core module, see the core package.
+var (
+	ErrInvalidTemplates = core.ErrInvalidTemplates
+	ErrUnparsableAnswer = core.ErrUnparsableAnswer
+	ErrShutdown         = core.ErrShutdown
+)
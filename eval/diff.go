@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package eval
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Disagreement is a question that the targets of Diff answered differently.
+type Disagreement struct {
+	Question Question `json:"question"`
+	// Answers holds one answer per target, in order: "true", "false",
+	// "undefined" or "error".
+	Answers []string `json:"answers"`
+}
+
+// RangeStats summarizes the questions whose largest number, by absolute
+// value, has the same number of decimal digits.
+type RangeStats struct {
+	Range     string `json:"range"` // e.g. "10-99"
+	Questions int    `json:"questions"`
+	// Agreed is the number of questions all targets answered the same.
+	Agreed int `json:"agreed"`
+	// Correct holds the number of correct answers per target, in order.
+	Correct []int `json:"correct"`
+}
+
+// DiffReport is the result of Diff.
+type DiffReport struct {
+	Targets       []string       `json:"targets"` // Target names
+	Ranges        []RangeStats   `json:"ranges"`  // By increasing magnitude
+	Disagreements []Disagreement `json:"disagreements"`
+}
+
+// Diff asks every target every question of dataset and reports where their
+// answers differ, and how often each one is right per range of magnitudes,
+// to tell which provider to trust for which numbers. If ctx is done, Diff
+// stops and returns ctx.Err().
+func Diff(ctx context.Context, dataset []Question, targets []Target, opts Options) (DiffReport, error) {
+	for i, q := range dataset {
+		if err := q.validate(); err != nil {
+			return DiffReport{}, fmt.Errorf("question %d: %w", i, err)
+		}
+	}
+	report := DiffReport{}
+	answers := make([][]string, len(dataset)) // Per question, per target
+	for i := range answers {
+		answers[i] = make([]string, len(targets))
+	}
+	correct := make([][]bool, len(dataset))
+	for i := range correct {
+		correct[i] = make([]bool, len(targets))
+	}
+	for j, t := range targets {
+		report.Targets = append(report.Targets, t.Name)
+		outcomes, err := askAll(ctx, dataset, t.Provider, max(opts.Concurrency, 1))
+		if err != nil {
+			return DiffReport{}, err
+		}
+		for i, o := range outcomes {
+			switch {
+			case o.err != nil:
+				answers[i][j] = "error"
+			case o.res == nil:
+				answers[i][j] = "undefined"
+			default:
+				answers[i][j] = strconv.FormatBool(*o.res)
+				correct[i][j] = *o.res == dataset[i].Want
+			}
+		}
+	}
+
+	ranges := map[int]*RangeStats{} // By number of digits
+	for i, q := range dataset {
+		digits := magnitude(q.Args)
+		r, ok := ranges[digits]
+		if !ok {
+			r = &RangeStats{Range: rangeLabel(digits), Correct: make([]int, len(targets))}
+			ranges[digits] = r
+		}
+		r.Questions++
+		agreed := !slices.ContainsFunc(answers[i], func(a string) bool { return a != answers[i][0] })
+		if agreed {
+			r.Agreed++
+		} else {
+			report.Disagreements = append(report.Disagreements, Disagreement{Question: q, Answers: answers[i]})
+		}
+		for j, ok := range correct[i] {
+			if ok {
+				r.Correct[j]++
+			}
+		}
+	}
+	for _, digits := range slices.Sorted(maps.Keys(ranges)) {
+		report.Ranges = append(report.Ranges, *ranges[digits])
+	}
+	return report, nil
+}
+
+// magnitude returns the number of decimal digits of the largest absolute
+// value among args.
+func magnitude(args []int) int {
+	digits := 1
+	for _, n := range args {
+		digits = max(digits, len(strings.TrimPrefix(strconv.Itoa(n), "-")))
+	}
+	return digits
+}
+
+// rangeLabel describes the absolute values with the given number of digits,
+// e.g. "100-999" for three.
+func rangeLabel(digits int) string {
+	if digits == 1 {
+		return "0-9"
+	}
+	return "1" + strings.Repeat("0", digits-1) + "-" + strings.Repeat("9", digits)
+}
+
+// WriteText writes the per-range statistics as a table, followed by the
+// disagreements, one per line.
+func (r DiffReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "RANGE\tQUESTIONS\tAGREED")
+	for _, name := range r.Targets {
+		fmt.Fprintf(tw, "\t%s CORRECT", strings.ToUpper(name))
+	}
+	fmt.Fprintln(tw)
+	for _, rs := range r.Ranges {
+		fmt.Fprintf(tw, "%s\t%d\t%d", rs.Range, rs.Questions, rs.Agreed)
+		for _, c := range rs.Correct {
+			fmt.Fprintf(tw, "\t%d", c)
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(r.Disagreements) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "\n%d disagreements:\n", len(r.Disagreements)); err != nil {
+		return err
+	}
+	for _, d := range r.Disagreements {
+		parts := make([]string, len(d.Answers))
+		for j, a := range d.Answers {
+			parts[j] = r.Targets[j] + "=" + a
+		}
+		if _, err := fmt.Fprintf(w, "%s%v (want %t): %s\n", d.Question.Predicate, d.Question.Args, d.Question.Want, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package eval
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/isevenaitest"
+)
+
+func TestDiff(t *testing.T) {
+	// The mock only knows small numbers and thinks everything else is even.
+	mock := isevenaitest.NewMock()
+	mock.On(isevenaitest.IsEven).Return(true)
+	mock.On(isevenaitest.IsEven, 3).Return(false)
+	dataset := []Question{
+		{Predicate: "isEven", Args: []int{3}, Want: false},
+		{Predicate: "isEven", Args: []int{4}, Want: true},
+		{Predicate: "isEven", Args: []int{-17}, Want: false},
+		{Predicate: "isEven", Args: []int{1001}, Want: false},
+	}
+	report, err := Diff(context.Background(), dataset, []Target{
+		{Name: "local", Provider: is_even_ai.NewIsEvenAiLocal()},
+		{Name: "mock", Provider: mock},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(report.Disagreements) != 2 || report.Disagreements[0].Question.Args[0] != -17 ||
+		strings.Join(report.Disagreements[0].Answers, ",") != "false,true" {
+		t.Errorf("Unexpected disagreements %+v", report.Disagreements)
+	}
+	var ranges []string
+	for _, r := range report.Ranges {
+		ranges = append(ranges, r.Range)
+	}
+	if strings.Join(ranges, ",") != "0-9,10-99,1000-9999" {
+		t.Errorf("Unexpected ranges %v", ranges)
+	}
+	if r := report.Ranges[0]; r.Questions != 2 || r.Agreed != 2 || r.Correct[0] != 2 || r.Correct[1] != 2 {
+		t.Errorf("Unexpected stats for small numbers %+v", r)
+	}
+	if r := report.Ranges[1]; r.Agreed != 0 || r.Correct[0] != 1 || r.Correct[1] != 0 {
+		t.Errorf("Unexpected stats for two-digit numbers %+v", r)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "isEven[1001] (want false): local=false mock=true") {
+		t.Errorf("Expected disagreements in output, got\n%s", buf.String())
+	}
+}
+
+func TestRangeLabel(t *testing.T) {
+	for args, want := range map[[2]int]string{{0, 9}: "0-9", {-10, 5}: "10-99", {123, 4}: "100-999"} {
+		if got := rangeLabel(magnitude(args[:])); got != want {
+			t.Errorf("rangeLabel(magnitude(%v)) = %s, want %s", args, got, want)
+		}
+	}
+}
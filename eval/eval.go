@@ -0,0 +1,292 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package eval runs a labeled dataset of questions against one or more
+// providers and compares their accuracy, latency, undefined rate and cost,
+// e.g. before migrating to another model:
+//
+//	dataset, err := eval.LoadDataset(f)
+//	...
+//	report, err := eval.Run(ctx, dataset, []eval.Target{
+//		{Name: "current", Model: "gemini-1.5-flash", Provider: current},
+//		{Name: "candidate", Model: "gemini-2.0-flash-lite", Provider: candidate},
+//	}, eval.Options{Concurrency: 8})
+//	...
+//	report.WriteTable(os.Stdout)
+package eval
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/core"
+)
+
+// arity is the number of arguments of each predicate.
+var arity = map[string]int{
+	"isEven":        1,
+	"isOdd":         1,
+	"areEqual":      2,
+	"areNotEqual":   2,
+	"isGreaterThan": 2,
+	"isLessThan":    2,
+	"isHappyNumber": 1,
+}
+
+// Question is a question of a dataset and its correct answer.
+type Question struct {
+	Predicate string `json:"predicate"` // Prompt name, e.g. "isEven"
+	Args      []int  `json:"args"`
+	Want      bool   `json:"want"`
+}
+
+func (q Question) validate() error {
+	n, ok := arity[q.Predicate]
+	if !ok {
+		return fmt.Errorf("unknown predicate %q", q.Predicate)
+	}
+	if len(q.Args) != n {
+		return fmt.Errorf("%s takes %d arguments, got %d", q.Predicate, n, len(q.Args))
+	}
+	return nil
+}
+
+// ask asks p the question, which must be valid.
+func (q Question) ask(p is_even_ai.Provider) (*bool, error) {
+	switch q.Predicate {
+	case "isEven":
+		return p.IsEven(q.Args[0])
+	case "isOdd":
+		return p.IsOdd(q.Args[0])
+	case "areEqual":
+		return p.AreEqual(q.Args[0], q.Args[1])
+	case "areNotEqual":
+		return p.AreNotEqual(q.Args[0], q.Args[1])
+	case "isGreaterThan":
+		return p.IsGreaterThan(q.Args[0], q.Args[1])
+	case "isHappyNumber":
+		h, ok := p.(interface{ IsHappyNumber(n int) (*bool, error) })
+		if !ok {
+			return nil, fmt.Errorf("%T cannot answer isHappyNumber", p)
+		}
+		return h.IsHappyNumber(q.Args[0])
+	default:
+		return p.IsLessThan(q.Args[0], q.Args[1])
+	}
+}
+
+// ParityDataset returns IsEven and IsOdd questions about each of numbers.
+func ParityDataset(numbers []int) []Question {
+	dataset := make([]Question, 0, 2*len(numbers))
+	for _, n := range numbers {
+		dataset = append(dataset,
+			Question{Predicate: "isEven", Args: []int{n}, Want: n%2 == 0},
+			Question{Predicate: "isOdd", Args: []int{n}, Want: n%2 != 0})
+	}
+	return dataset
+}
+
+// HappyDataset returns IsHappyNumber questions about each of numbers. Happy
+// numbers are easy to compute but hard for small models to guess, which makes
+// the dataset good at telling models apart.
+func HappyDataset(numbers []int) []Question {
+	dataset := make([]Question, 0, len(numbers))
+	for _, n := range numbers {
+		want, _ := core.GroundTruth("isHappyNumber", []int{n})
+		dataset = append(dataset, Question{Predicate: "isHappyNumber", Args: []int{n}, Want: want})
+	}
+	return dataset
+}
+
+// LoadDataset reads questions from r as JSON Lines, one Question per line,
+// e.g. {"predicate":"isEven","args":[4],"want":true}. Empty lines are skipped.
+func LoadDataset(r io.Reader) ([]Question, error) {
+	var dataset []Question
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var q Question
+		if err := json.Unmarshal(scanner.Bytes(), &q); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := q.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		dataset = append(dataset, q)
+	}
+	return dataset, scanner.Err()
+}
+
+// Target is a provider to evaluate.
+type Target struct {
+	Name     string // Shown in the report
+	Model    string // Optional: shown in the report
+	Provider is_even_ai.Provider
+}
+
+// Options configures Run.
+type Options struct {
+	// Concurrency is the number of questions asked at the same time per
+	// target. Optional: defaults to 1, which gives the most faithful latencies.
+	Concurrency int
+}
+
+// Result is the evaluation of one target.
+type Result struct {
+	Name  string `json:"name"`
+	Model string `json:"model,omitempty"`
+	// Accuracy compares the answers with the dataset. Undefined answers and
+	// errors count as incorrect.
+	Accuracy is_even_ai.PredicateAccuracy `json:"accuracy"`
+	// Latency percentiles of all questions, including failed ones.
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	// Usage is the tokens and cost spent on the dataset, for providers that
+	// track their usage, like IsEvenAiGemini.
+	Usage *is_even_ai.Usage `json:"usage,omitempty"`
+}
+
+// UndefinedRate returns the fraction of questions answered with undefined,
+// or 0 if there were none.
+func (r Result) UndefinedRate() float64 {
+	if r.Accuracy.Questions == 0 {
+		return 0
+	}
+	return float64(r.Accuracy.Undefined) / float64(r.Accuracy.Questions)
+}
+
+// Report is the result of Run, with one Result per target in order.
+type Report []Result
+
+// usageReporter is implemented by providers that track their usage.
+type usageReporter interface {
+	Usage() is_even_ai.Usage
+}
+
+// Run asks each target every question of dataset, one target after the
+// other. If ctx is done, Run stops and returns ctx.Err().
+func Run(ctx context.Context, dataset []Question, targets []Target, opts Options) (Report, error) {
+	for i, q := range dataset {
+		if err := q.validate(); err != nil {
+			return nil, fmt.Errorf("question %d: %w", i, err)
+		}
+	}
+	report := make(Report, 0, len(targets))
+	for _, t := range targets {
+		res, err := run(ctx, dataset, t, max(opts.Concurrency, 1))
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, res)
+	}
+	return report, nil
+}
+
+// run evaluates one target.
+func run(ctx context.Context, dataset []Question, t Target, concurrency int) (Result, error) {
+	usage, hasUsage := t.Provider.(usageReporter)
+	var before is_even_ai.Usage
+	if hasUsage {
+		before = usage.Usage()
+	}
+
+	outcomes, err := askAll(ctx, dataset, t.Provider, concurrency)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{Name: t.Name, Model: t.Model}
+	latencies := make([]time.Duration, len(outcomes))
+	for i, o := range outcomes {
+		res.Accuracy.Add(dataset[i].Want, o.res, o.err)
+		latencies[i] = o.latency
+	}
+	slices.Sort(latencies)
+	res.P50, res.P90, res.P99 = percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99)
+	if hasUsage {
+		after := usage.Usage()
+		res.Usage = &is_even_ai.Usage{
+			Requests:         after.Requests - before.Requests,
+			PromptTokens:     after.PromptTokens - before.PromptTokens,
+			CompletionTokens: after.CompletionTokens - before.CompletionTokens,
+			CostUSD:          after.CostUSD - before.CostUSD,
+		}
+	}
+	return res, nil
+}
+
+// outcome is the answer to one question.
+type outcome struct {
+	res     *bool
+	err     error
+	latency time.Duration
+}
+
+// askAll asks p every question of dataset, concurrency at a time, and returns
+// the outcomes in order. If ctx is done, it stops and returns ctx.Err().
+func askAll(ctx context.Context, dataset []Question, p is_even_ai.Provider, concurrency int) ([]outcome, error) {
+	outcomes := make([]outcome, len(dataset))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				start := time.Now()
+				res, err := dataset[i].ask(p)
+				outcomes[i] = outcome{res: res, err: err, latency: time.Since(start)}
+			}
+		}()
+	}
+	for i := range dataset {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// percentile returns the p-th percentile of sorted latencies, using the
+// nearest-rank method, or 0 if there are none.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank, 1)-1]
+}
+
+// WriteTable writes the report as an aligned text table, one row per target.
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tMODEL\tQUESTIONS\tACCURACY\tUNDEFINED\tERRORS\tP50\tP90\tP99\tCOST (USD)")
+	for _, res := range r {
+		cost := "-"
+		if res.Usage != nil {
+			cost = fmt.Sprintf("%.6f", res.Usage.CostUSD)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f%%\t%.1f%%\t%d\t%v\t%v\t%v\t%s\n",
+			res.Name, res.Model, res.Accuracy.Questions, 100*res.Accuracy.Accuracy(), 100*res.UndefinedRate(),
+			res.Accuracy.Errors, res.P50, res.P90, res.P99, cost)
+	}
+	return tw.Flush()
+}
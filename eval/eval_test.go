@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/geminitest"
+	"github.com/philwo/is-even-ai/isevenaitest"
+)
+
+func TestLoadDataset(t *testing.T) {
+	dataset, err := LoadDataset(strings.NewReader(`{"predicate":"isEven","args":[4],"want":true}
+
+{"predicate":"isLessThan","args":[1,2],"want":true}
+`))
+	if err != nil {
+		t.Fatalf("LoadDataset failed: %v", err)
+	}
+	if len(dataset) != 2 || dataset[1].Predicate != "isLessThan" || dataset[1].Args[1] != 2 {
+		t.Errorf("Unexpected dataset %+v", dataset)
+	}
+
+	for _, line := range []string{`{"predicate":"isPrime","args":[4]}`, `{"predicate":"isEven","args":[1,2]}`, `not json`} {
+		if _, err := LoadDataset(strings.NewReader(line)); err == nil {
+			t.Errorf("LoadDataset(%q) succeeded, want error", line)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := geminitest.NewServer(geminitest.Always("true"))
+	defer server.Close()
+	gemini, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini failed: %v", err)
+	}
+	defer gemini.Close()
+	flaky := isevenaitest.MustParseScript("undefined, error, true, false")
+
+	dataset := ParityDataset([]int{1, 2})
+	report, err := Run(context.Background(), dataset, []Target{
+		{Name: "local", Provider: is_even_ai.NewIsEvenAiLocal()},
+		{Name: "gemini", Model: "gemini-1.5-flash", Provider: gemini},
+		{Name: "flaky", Provider: flaky},
+	}, Options{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(report))
+	}
+	if a := report[0].Accuracy; a.Questions != 4 || a.Accuracy() != 1 || report[0].Usage != nil {
+		t.Errorf("Unexpected result for local provider: %+v", report[0])
+	}
+	if a := report[1].Accuracy; a.Correct() != 2 || a.FalsePositives != 2 {
+		t.Errorf("Unexpected accuracy for an always true provider: %+v", a)
+	}
+	if u := report[1].Usage; u == nil || u.Requests != 4 || u.PromptTokens != 4*geminitest.PromptTokens {
+		t.Errorf("Unexpected usage %+v", u)
+	}
+	if r := report[2]; r.Accuracy.Undefined != 1 || r.Accuracy.Errors != 1 || r.UndefinedRate() != 0.25 {
+		t.Errorf("Unexpected result for flaky provider: %+v", r)
+	}
+	for _, r := range report {
+		if r.P50 > r.P90 || r.P90 > r.P99 {
+			t.Errorf("Latency percentiles of %s out of order: %v, %v, %v", r.Name, r.P50, r.P90, r.P99)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteTable(&buf); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "gemini") || !strings.Contains(lines[2], "50.0%") {
+		t.Errorf("Unexpected table:\n%s", buf.String())
+	}
+}
+
+func TestRun_HappyDataset(t *testing.T) {
+	dataset := HappyDataset([]int{1, 4, 7, 20})
+	if want := []bool{true, false, true, false}; len(dataset) != 4 || dataset[0].Want != want[0] || dataset[1].Want != want[1] || dataset[2].Want != want[2] || dataset[3].Want != want[3] {
+		t.Errorf("HappyDataset() = %+v, want answers %v", dataset, want)
+	}
+	report, err := Run(context.Background(), dataset, []Target{
+		{Name: "local", Provider: is_even_ai.NewIsEvenAiLocal()},
+		{Name: "predicates only", Provider: struct{ is_even_ai.Provider }{is_even_ai.NewIsEvenAiLocal()}},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if a := report[0].Accuracy; a.Questions != 4 || a.Accuracy() != 1 {
+		t.Errorf("Unexpected result for local provider: %+v", report[0])
+	}
+	if a := report[1].Accuracy; a.Errors != 4 {
+		t.Errorf("Expected errors from a provider without IsHappyNumber, got %+v", a)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Run(ctx, ParityDataset([]int{1}), []Target{{Name: "local", Provider: is_even_ai.NewIsEvenAiLocal()}}, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	for p, want := range map[int]time.Duration{50: 50, 90: 90, 99: 99} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(1..100, %d) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(sorted[:1], 99); got != 1 {
+		t.Errorf("percentile of one latency = %v, want 1", got)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Candidate is a set of prompt templates to evaluate.
+type Candidate struct {
+	Name string
+	File is_even_ai.PromptTemplateFile
+}
+
+// NewProviderFunc creates a provider asking model with templates, e.g.
+//
+//	func(model string, templates is_even_ai.IsEvenAiCorePromptTemplates) (is_even_ai.Provider, error) {
+//		return is_even_ai.NewIsEvenAiGemini(clientOpts, is_even_ai.GeminiModelOptions{Model: model, PromptTemplates: templates})
+//	}
+type NewProviderFunc func(model string, templates is_even_ai.IsEvenAiCorePromptTemplates) (is_even_ai.Provider, error)
+
+// SearchResult is the outcome of SearchPrompts for one model.
+type SearchResult struct {
+	Model string
+	// Report has one Result per candidate, named after it, in order.
+	Report Report
+	// Best is the candidate with the highest accuracy; ties go to the one with
+	// fewer undefined answers, then to the earlier one.
+	Best Candidate
+}
+
+// SearchPrompts evaluates every candidate with every model on dataset and
+// picks the most accurate candidate per model. Save the winner with
+// Best.File.Write and load it again with is_even_ai.ReadPromptTemplateFile.
+func SearchPrompts(ctx context.Context, dataset []Question, candidates []Candidate, models []string, newProvider NewProviderFunc, opts Options) ([]SearchResult, error) {
+	if len(candidates) == 0 {
+		return nil, errors.New("no candidate templates to evaluate")
+	}
+	templates := make([]is_even_ai.IsEvenAiCorePromptTemplates, len(candidates))
+	for i, c := range candidates {
+		var err error
+		if templates[i], err = c.File.Templates(); err != nil {
+			return nil, fmt.Errorf("candidate %s: %w", c.Name, err)
+		}
+	}
+
+	var results []SearchResult
+	for _, model := range models {
+		report, err := searchModel(ctx, dataset, candidates, templates, model, newProvider, opts)
+		if err != nil {
+			return nil, err
+		}
+		best := 0
+		for i, r := range report {
+			b := report[best]
+			if a := r.Accuracy.Accuracy(); a > b.Accuracy.Accuracy() || (a == b.Accuracy.Accuracy() && r.Accuracy.Undefined < b.Accuracy.Undefined) {
+				best = i
+			}
+		}
+		results = append(results, SearchResult{Model: model, Report: report, Best: candidates[best]})
+	}
+	return results, nil
+}
+
+// searchModel evaluates every candidate with model.
+func searchModel(ctx context.Context, dataset []Question, candidates []Candidate, templates []is_even_ai.IsEvenAiCorePromptTemplates, model string, newProvider NewProviderFunc, opts Options) (report Report, err error) {
+	targets := make([]Target, 0, len(candidates))
+	defer func() {
+		for _, t := range targets {
+			err = errors.Join(err, t.Provider.Close())
+		}
+	}()
+	for i, c := range candidates {
+		p, err := newProvider(model, templates[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider for model %s and candidate %s: %w", model, c.Name, err)
+		}
+		targets = append(targets, Target{Name: c.Name, Model: model, Provider: p})
+	}
+	return Run(ctx, dataset, targets, opts)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package eval
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestSearchPrompts(t *testing.T) {
+	// The fake model only understands questions ending in "parity" and is
+	// unsure about all others.
+	server := geminitest.NewServer(func(prompt string) string {
+		fields := strings.Fields(prompt)
+		if n, err := strconv.Atoi(fields[0]); err == nil && strings.HasSuffix(prompt, "parity even?") {
+			return strconv.FormatBool(n%2 == 0)
+		}
+		return "maybe"
+	})
+	defer server.Close()
+	var models []string
+	newProvider := func(model string, templates is_even_ai.IsEvenAiCorePromptTemplates) (is_even_ai.Provider, error) {
+		models = append(models, model)
+		return is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: server.URL},
+			is_even_ai.GeminiModelOptions{Model: model, PromptTemplates: templates})
+	}
+
+	comparisons := is_even_ai.PromptTemplateFile{AreEqual: "{a} = {b}?", IsGreaterThan: "{a} > {b}?"}
+	plain, parity := comparisons, comparisons
+	plain.IsEven = "Is {n} even?"
+	parity.IsEven = "{n} parity even?"
+	results, err := SearchPrompts(context.Background(), ParityDataset([]int{1, 2, 3}),
+		[]Candidate{{"plain", plain}, {"parity", parity}}, []string{"model-a", "model-b"}, newProvider, Options{})
+	if err != nil {
+		t.Fatalf("SearchPrompts failed: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Model != "model-a" || results[1].Model != "model-b" {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+	for _, r := range results {
+		if r.Best.Name != "parity" {
+			t.Errorf("Best candidate for %s is %s, want parity", r.Model, r.Best.Name)
+		}
+		if len(r.Report) != 2 || r.Report[0].Name != "plain" || r.Report[0].Accuracy.Undefined != 6 || r.Report[1].Accuracy.Accuracy() != 1 {
+			t.Errorf("Unexpected report for %s: %+v", r.Model, r.Report)
+		}
+	}
+	if len(models) != 4 {
+		t.Errorf("Expected 4 providers, got %d", len(models))
+	}
+
+	if _, err := SearchPrompts(context.Background(), nil, []Candidate{{"bad", is_even_ai.PromptTemplateFile{}}}, []string{"m"}, newProvider, Options{}); err == nil {
+		t.Error("Expected an error for invalid candidate templates")
+	}
+}
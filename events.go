@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"time"
+)
+
+// Events receives notifications about queries, to feed StatsD, Datadog or an
+// internal event bus without this package depending on them. Every method
+// gets the CallInfo of the query. Methods are called synchronously on the
+// query's goroutine, possibly concurrently, so they should return quickly.
+// Embed NopEvents to implement only some of them.
+type Events interface {
+	// QueryStarted is called before a query is answered.
+	QueryStarted(ctx context.Context, info CallInfo)
+	// QueryFinished is called after a query was answered with res, or failed with err.
+	QueryFinished(ctx context.Context, info CallInfo, res *bool, err error, latency time.Duration)
+	// CacheHit is called when a cache answers a query.
+	CacheHit(ctx context.Context, info CallInfo, answer Answer)
+	// RetryScheduled is called when RetryMiddleware will make another attempt
+	// after waiting wait, because attempt failed with err or, if err is nil,
+	// answered undefined.
+	RetryScheduled(ctx context.Context, info CallInfo, attempt int, wait time.Duration, err error)
+	// FallbackUsed is called when a provider falls back from model from to
+	// model to because of err, e.g. with GeminiModelOptions.FallbackModels.
+	FallbackUsed(ctx context.Context, info CallInfo, from, to string, err error)
+}
+
+// NopEvents implements Events by doing nothing.
+type NopEvents struct{}
+
+func (NopEvents) QueryStarted(context.Context, CallInfo)                               {}
+func (NopEvents) QueryFinished(context.Context, CallInfo, *bool, error, time.Duration) {}
+func (NopEvents) CacheHit(context.Context, CallInfo, Answer)                           {}
+func (NopEvents) RetryScheduled(context.Context, CallInfo, int, time.Duration, error)  {}
+func (NopEvents) FallbackUsed(context.Context, CallInfo, string, string, error)        {}
+
+type eventsKey struct{}
+
+// EventsMiddleware reports queries to events. Add it before (i.e. outside of)
+// caching and retry middleware: it also makes them, and the provider, report
+// cache hits, retries and fallbacks to events.
+func EventsMiddleware(events Events) Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			info, _ := CallInfoFromContext(ctx)
+			ctx = context.WithValue(ctx, eventsKey{}, events)
+			events.QueryStarted(ctx, info)
+			start := time.Now()
+			res, err := next(ctx, prompt)
+			events.QueryFinished(ctx, info, res, err, time.Since(start))
+			return res, err
+		}
+	}
+}
+
+// eventsFromContext returns the Events added to ctx by EventsMiddleware, or
+// NopEvents.
+func eventsFromContext(ctx context.Context) Events {
+	if events, ok := ctx.Value(eventsKey{}).(Events); ok {
+		return events
+	}
+	return NopEvents{}
+}
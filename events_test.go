@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+// recordingEvents records events as strings.
+type recordingEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingEvents) add(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingEvents) QueryStarted(_ context.Context, info CallInfo) {
+	r.add("started %s%v", info.Predicate, info.Args)
+}
+
+func (r *recordingEvents) QueryFinished(_ context.Context, info CallInfo, res *bool, err error, _ time.Duration) {
+	r.add("finished %s%v %s %v", info.Predicate, info.Args, core.AnswerOf(res), err != nil)
+}
+
+func (r *recordingEvents) CacheHit(_ context.Context, info CallInfo, answer Answer) {
+	r.add("cache hit %s%v %s", info.Predicate, info.Args, answer)
+}
+
+func (r *recordingEvents) RetryScheduled(_ context.Context, info CallInfo, attempt int, _ time.Duration, err error) {
+	r.add("retry %s%v after attempt %d: %v", info.Predicate, info.Args, attempt, err)
+}
+
+func (r *recordingEvents) FallbackUsed(_ context.Context, info CallInfo, from, to string, _ error) {
+	r.add("fallback %s%v from %s to %s", info.Predicate, info.Args, from, to)
+}
+
+func TestEventsMiddleware(t *testing.T) {
+	calls := 0
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, failed(errors.New("transient")), answered(true)))
+	events := &recordingEvents{}
+	core.Use(EventsMiddleware(events), CacheMiddleware(NewMemoryCache()), RetryMiddleware(2, time.Millisecond))
+
+	for range 2 {
+		if _, err := core.IsEven(4); err != nil {
+			t.Fatalf("IsEven(4) failed: %v", err)
+		}
+	}
+
+	want := []string{
+		"started isEven[4]",
+		"retry isEven[4] after attempt 1: transient",
+		"finished isEven[4] true false",
+		"started isEven[4]",
+		"cache hit isEven[4] true",
+		"finished isEven[4] true false",
+	}
+	if got := strings.Join(events.events, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Got events\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestEventsMiddleware_GeminiFallback(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/models/retired-model:") {
+			geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+			return
+		}
+		geminitest.WriteAnswer(w, r, "false")
+	}, GeminiModelOptions{Model: "retired-model", FallbackModels: []string{"healthy-model"}})
+	events := &recordingEvents{}
+	ai.Use(EventsMiddleware(events))
+
+	res, err := ai.IsEven(3)
+	checkGeminiResult(t, res, err, false, "IsEven", 3)
+	want := "started isEven[3]\nfallback isEven[3] from retired-model to healthy-model\nfinished isEven[3] false false"
+	if got := strings.Join(events.events, "\n"); got != want {
+		t.Errorf("Got events\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNopEvents(t *testing.T) {
+	var _ Events = NopEvents{}
+	if _, ok := eventsFromContext(context.Background()).(NopEvents); !ok {
+		t.Error("Expected NopEvents for a context without events")
+	}
+}
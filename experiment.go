@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// ExperimentOptions configures IsEvenAiExperiment.
+type ExperimentOptions struct {
+	// TreatmentShare is the fraction of queries, between 0 and 1, sent to the
+	// treatment provider. The rest go to the control provider.
+	TreatmentShare float64
+	// Names of the arms in reports. Optional: default to "control" and "treatment".
+	ControlName, TreatmentName string
+	// OnResult, if set, is called after every query with its outcome, tagged
+	// with the arm that answered it.
+	OnResult func(ExperimentResult)
+	// Rand is the source of randomness; set it for reproducible runs.
+	// Optional: defaults to a randomly seeded source.
+	Rand *rand.Rand
+}
+
+// ExperimentResult is the outcome of one query of an IsEvenAiExperiment.
+type ExperimentResult struct {
+	Arm       string
+	Predicate string // Prompt name, e.g. "isEven"
+	Args      []int
+	Result    *bool
+	Err       error
+	Correct   bool // Whether Result matches arithmetic
+	Latency   time.Duration
+}
+
+// ArmStats summarizes the queries answered by one arm of an experiment.
+type ArmStats struct {
+	Name string `json:"name"`
+	// Accuracy compares the answers with arithmetic.
+	Accuracy PredicateAccuracy `json:"accuracy"`
+	// TotalLatency is the sum of the latencies of all queries.
+	TotalLatency time.Duration `json:"total_latency"`
+	// Usage is the arm's provider's Usage, if it reports any (like
+	// IsEvenAiGemini), so that it includes queries made outside the experiment.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// MeanLatency returns the average latency of the arm's queries, or 0 if there were none.
+func (s ArmStats) MeanLatency() time.Duration {
+	if s.Accuracy.Questions == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Accuracy.Questions)
+}
+
+// IsEvenAiExperiment splits queries between a control and a treatment
+// provider, e.g. two models, and tracks the accuracy, latency and cost of
+// each arm, to back model upgrades with data. IsEvenAiExperiment is safe for
+// concurrent use.
+type IsEvenAiExperiment struct {
+	arms     [2]Provider // Control, treatment
+	share    float64
+	onResult func(ExperimentResult)
+
+	mu    sync.Mutex // Guards rand, which is not safe for concurrent use, and stats
+	rand  *rand.Rand
+	stats [2]ArmStats
+}
+
+var _ Provider = (*IsEvenAiExperiment)(nil)
+
+// usageReporter is implemented by providers that track their usage.
+type usageReporter interface {
+	Usage() Usage
+}
+
+// NewIsEvenAiExperiment creates an experiment between control and treatment.
+func NewIsEvenAiExperiment(control, treatment Provider, opts ExperimentOptions) *IsEvenAiExperiment {
+	if control == nil || treatment == nil {
+		panic("control and treatment providers cannot be nil")
+	}
+	e := &IsEvenAiExperiment{
+		arms:     [2]Provider{control, treatment},
+		share:    min(max(opts.TreatmentShare, 0), 1),
+		onResult: opts.OnResult,
+		rand:     opts.Rand,
+	}
+	if e.rand == nil {
+		e.rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	e.stats[0].Name, e.stats[1].Name = "control", "treatment"
+	if opts.ControlName != "" {
+		e.stats[0].Name = opts.ControlName
+	}
+	if opts.TreatmentName != "" {
+		e.stats[1].Name = opts.TreatmentName
+	}
+	return e
+}
+
+// assign picks an arm for a query and asks it.
+func (e *IsEvenAiExperiment) assign(predicate string, ask func(Provider) (*bool, error), args ...int) (*bool, error) {
+	e.mu.Lock()
+	arm := 0
+	if e.rand.Float64() < e.share {
+		arm = 1
+	}
+	e.mu.Unlock()
+
+	start := time.Now()
+	res, err := ask(e.arms[arm])
+	latency := time.Since(start)
+	want, _ := core.GroundTruth(predicate, args)
+
+	e.mu.Lock()
+	stats := &e.stats[arm]
+	stats.Accuracy.Add(want, res, err)
+	stats.TotalLatency += latency
+	name := stats.Name
+	e.mu.Unlock()
+
+	if e.onResult != nil {
+		e.onResult(ExperimentResult{
+			Arm:       name,
+			Predicate: predicate,
+			Args:      args,
+			Result:    res,
+			Err:       err,
+			Correct:   err == nil && res != nil && *res == want,
+			Latency:   latency,
+		})
+	}
+	return res, err
+}
+
+// Report returns the statistics of the control and the treatment arm, in
+// this order.
+func (e *IsEvenAiExperiment) Report() []ArmStats {
+	e.mu.Lock()
+	report := []ArmStats{e.stats[0], e.stats[1]}
+	e.mu.Unlock()
+	for i, p := range e.arms {
+		if r, ok := p.(usageReporter); ok {
+			u := r.Usage()
+			report[i].Usage = &u
+		}
+	}
+	return report
+}
+
+// IsEven checks if n is even.
+func (e *IsEvenAiExperiment) IsEven(n int) (*bool, error) {
+	return e.assign("isEven", func(p Provider) (*bool, error) { return p.IsEven(n) }, n)
+}
+
+// IsOdd checks if n is odd.
+func (e *IsEvenAiExperiment) IsOdd(n int) (*bool, error) {
+	return e.assign("isOdd", func(p Provider) (*bool, error) { return p.IsOdd(n) }, n)
+}
+
+// AreEqual checks if a and b are equal.
+func (e *IsEvenAiExperiment) AreEqual(a, b int) (*bool, error) {
+	return e.assign("areEqual", func(p Provider) (*bool, error) { return p.AreEqual(a, b) }, a, b)
+}
+
+// AreNotEqual checks if a and b are not equal.
+func (e *IsEvenAiExperiment) AreNotEqual(a, b int) (*bool, error) {
+	return e.assign("areNotEqual", func(p Provider) (*bool, error) { return p.AreNotEqual(a, b) }, a, b)
+}
+
+// IsGreaterThan checks if a is greater than b.
+func (e *IsEvenAiExperiment) IsGreaterThan(a, b int) (*bool, error) {
+	return e.assign("isGreaterThan", func(p Provider) (*bool, error) { return p.IsGreaterThan(a, b) }, a, b)
+}
+
+// IsLessThan checks if a is less than b.
+func (e *IsEvenAiExperiment) IsLessThan(a, b int) (*bool, error) {
+	return e.assign("isLessThan", func(p Provider) (*bool, error) { return p.IsLessThan(a, b) }, a, b)
+}
+
+// Close closes both providers.
+func (e *IsEvenAiExperiment) Close() error {
+	return errors.Join(e.arms[0].Close(), e.arms[1].Close())
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestIsEvenAiExperiment(t *testing.T) {
+	control := &stubProvider{answer: answerAlways(true)}
+	treatment := NewIsEvenAiLocal()
+	var results []ExperimentResult
+	e := NewIsEvenAiExperiment(control, treatment, ExperimentOptions{
+		TreatmentShare: 0.5,
+		TreatmentName:  "local",
+		OnResult:       func(r ExperimentResult) { results = append(results, r) },
+		Rand:           rand.New(rand.NewPCG(1, 2)),
+	})
+
+	for n := range 100 {
+		if _, err := e.IsEven(n); err != nil {
+			t.Fatalf("IsEven(%d) failed: %v", n, err)
+		}
+	}
+
+	report := e.Report()
+	if len(report) != 2 || report[0].Name != "control" || report[1].Name != "local" {
+		t.Fatalf("Unexpected arms in report %+v", report)
+	}
+	c, l := report[0].Accuracy, report[1].Accuracy
+	if c.Questions+l.Questions != 100 || c.Questions < 30 || l.Questions < 30 {
+		t.Errorf("Expected about an even split of 100 queries, got %d and %d", c.Questions, l.Questions)
+	}
+	if l.Accuracy() != 1 || c.FalsePositives == 0 || c.Correct()+c.FalsePositives != c.Questions {
+		t.Errorf("Unexpected accuracy: control %+v, local %+v", c, l)
+	}
+	if got := len(control.callLog()); got != c.Questions {
+		t.Errorf("Control provider answered %d queries, report says %d", got, c.Questions)
+	}
+	if report[0].Usage != nil {
+		t.Errorf("Expected no usage for a provider that doesn't report it, got %+v", report[0].Usage)
+	}
+
+	if len(results) != 100 {
+		t.Fatalf("Expected 100 results, got %d", len(results))
+	}
+	for _, r := range results {
+		wantCorrect := r.Arm == "local" || r.Args[0]%2 == 0
+		if r.Predicate != "isEven" || r.Correct != wantCorrect {
+			t.Errorf("Unexpected result %+v", r)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+const geminiExplainInstruction = " Answer true or false, and explain your reasoning in one or two sentences."
+
+// geminiExplainSchema is the structured output of IsEvenWithExplanation.
+var geminiExplainSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"answer":      {Type: genai.TypeBoolean},
+		"explanation": {Type: genai.TypeString},
+	},
+	Required: []string{"answer", "explanation"},
+}
+
+// ExplainedAnswer is an answer of the model together with its natural-language
+// justification, e.g. for UIs that show why the AI believes 42 is even.
+type ExplainedAnswer struct {
+	Answer      *bool  `json:"answer"`
+	Explanation string `json:"explanation"`
+}
+
+// IsEvenWithExplanation asks the model whether n is even, using the IsEven
+// prompt template, and why. The answer and the explanation are separate fields
+// of a structured response, so the explanation cannot change how the answer
+// is parsed; a response without an answer fails with an error wrapping
+// ErrUnparsableAnswer.
+//
+// Like ExtractAndCheck, the question bypasses middleware, but counts towards
+// Usage and the Budget and is recorded in the audit log.
+func (ai *IsEvenAiGemini) IsEvenWithExplanation(ctx context.Context, n int) (ExplainedAnswer, error) {
+	prompt, err := ai.Prompt("isEven", n)
+	if err != nil {
+		return ExplainedAnswer{}, fmt.Errorf("failed to get prompt for IsEvenWithExplanation: %w", err)
+	}
+	var out ExplainedAnswer
+	info := CallInfo{Predicate: "isEvenWithExplanation", Args: []int{n}}
+	if _, err := ai.askStructured(ctx, info, prompt+geminiExplainInstruction, geminiExplainSchema, &out, func() *bool { return out.Answer }); err != nil {
+		return ExplainedAnswer{}, err
+	}
+	return out, nil
+}
+
+// IsEvenWithExplanation reports whether n is even, explained by its remainder
+// when divided by two.
+func (l *IsEvenAiLocal) IsEvenWithExplanation(ctx context.Context, n int) (ExplainedAnswer, error) {
+	if err := ctx.Err(); err != nil {
+		return ExplainedAnswer{}, err
+	}
+	even := n%2 == 0
+	explanation := fmt.Sprintf("%d is divisible by 2 without a remainder.", n)
+	if !even {
+		explanation = fmt.Sprintf("%d leaves a remainder of 1 when divided by 2.", n)
+	}
+	return ExplainedAnswer{Answer: &even, Explanation: explanation}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_IsEvenWithExplanation(t *testing.T) {
+	var gotBody string
+	answer := `{"answer": true, "explanation": "42 ends in 2, so it is divisible by two."}`
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		geminitest.WriteAnswer(w, r, answer)
+	})
+	ctx := context.Background()
+
+	got, err := ai.IsEvenWithExplanation(ctx, 42)
+	if err != nil {
+		t.Fatalf("IsEvenWithExplanation() failed: %v", err)
+	}
+	checkGeminiResult(t, got.Answer, nil, true, "IsEvenWithExplanation", 42)
+	if got.Explanation != "42 ends in 2, so it is divisible by two." {
+		t.Errorf("Explanation = %q, want the model's justification", got.Explanation)
+	}
+	if !strings.Contains(gotBody, "Is 42 an even number?") || !strings.Contains(gotBody, `"responseMimeType":"application/json"`) {
+		t.Errorf("Request should ask the IsEven prompt for JSON, got: %s", gotBody)
+	}
+	if u := ai.UsageByPredicate()["isEvenWithExplanation"]; u.Requests != 1 {
+		t.Errorf("Expected 1 isEvenWithExplanation request in the usage, got %+v", u)
+	}
+
+	for _, answer = range []string{
+		`{"explanation": "It is even."}`,
+		`{"answer": "probably", "explanation": "It is even."}`,
+		"true, because it ends in 2",
+	} {
+		if got, err := ai.IsEvenWithExplanation(ctx, 42); !errors.Is(err, ErrUnparsableAnswer) {
+			t.Errorf("IsEvenWithExplanation() answering %s = %+v, %v; want ErrUnparsableAnswer", answer, got, err)
+		}
+	}
+}
+
+func TestIsEvenAiLocal_IsEvenWithExplanation(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	for _, n := range []int{-3, 0, 7, 42} {
+		got, err := l.IsEvenWithExplanation(context.Background(), n)
+		checkGeminiResult(t, got.Answer, err, n%2 == 0, "IsEvenWithExplanation", n)
+		if got.Explanation == "" {
+			t.Errorf("IsEvenWithExplanation(%d) has no explanation", n)
+		}
+	}
+}
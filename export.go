@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// ResultWriter encodes BatchResults for reports. Results are written as they
+// arrive, so a writer can stream a running batch to a file or pipe.
+type ResultWriter interface {
+	Write(res BatchResult) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// WriteResults writes every result from results to rw and flushes it, e.g.
+//
+//	err := is_even_ai.WriteResults(is_even_ai.NewCSVResultWriter(f), is_even_ai.Batch(ctx, numbers, 8, ask))
+//
+// If writing fails, the remaining results are still received, so that the
+// goroutines of a Batch can finish, and the first error is returned.
+func WriteResults(rw ResultWriter, results <-chan BatchResult) error {
+	var err error
+	for res := range results {
+		if err == nil {
+			err = rw.Write(res)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return rw.Flush()
+}
+
+// resultHeader names the CSV columns, which are also the JSON field names.
+var resultHeader = []string{"index", "n", "answer", "error", "latency_ms", "model", "template_version", "requests", "prompt_tokens", "completion_tokens", "cost_usd"}
+
+// resultRecord is the JSON encoding of a BatchResult.
+type resultRecord struct {
+	Index     int    `json:"index"`
+	N         int    `json:"n"`
+	Answer    *bool  `json:"answer"` // null if the answer was undefined
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Model     string `json:"model,omitempty"`
+	// TemplateVersion is omitted if the templates have no version.
+	TemplateVersion string `json:"template_version,omitempty"`
+	Usage
+}
+
+func newResultRecord(res BatchResult) resultRecord {
+	rec := resultRecord{
+		Index:           res.Index,
+		N:               res.N,
+		Answer:          res.Result,
+		LatencyMs:       res.Latency.Milliseconds(),
+		Model:           res.Metadata.Model,
+		TemplateVersion: res.Metadata.TemplateVersion,
+		Usage:           res.Metadata.Usage,
+	}
+	if res.Err != nil {
+		rec.Error = res.Err.Error()
+	}
+	return rec
+}
+
+// csvResultWriter writes a header followed by one row per result.
+type csvResultWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVResultWriter returns a ResultWriter that writes CSV with the columns
+// index, n, answer (true, false or undefined), error, latency_ms, model,
+// template_version, requests, prompt_tokens, completion_tokens and cost_usd. Every row is
+// flushed as it is written.
+func NewCSVResultWriter(w io.Writer) ResultWriter {
+	return &csvResultWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvResultWriter) Write(res BatchResult) error {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if err := w.w.Write(resultHeader); err != nil {
+			return err
+		}
+	}
+	errText := ""
+	if res.Err != nil {
+		errText = res.Err.Error()
+	}
+	md := res.Metadata
+	row := []string{
+		strconv.Itoa(res.Index),
+		strconv.Itoa(res.N),
+		core.AnswerOf(res.Result).String(),
+		errText,
+		strconv.FormatInt(res.Latency.Milliseconds(), 10),
+		md.Model,
+		md.TemplateVersion,
+		strconv.FormatInt(md.Requests, 10),
+		strconv.FormatInt(md.PromptTokens, 10),
+		strconv.FormatInt(md.CompletionTokens, 10),
+		strconv.FormatFloat(md.CostUSD, 'f', -1, 64),
+	}
+	if err := w.w.Write(row); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (w *csvResultWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// jsonlResultWriter writes one JSON object per line.
+type jsonlResultWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONLResultWriter returns a ResultWriter that writes JSON Lines, one
+// object per result with the same fields as the columns of
+// NewCSVResultWriter. Undefined answers are null and error is omitted for
+// successful queries.
+func NewJSONLResultWriter(w io.Writer) ResultWriter {
+	return &jsonlResultWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *jsonlResultWriter) Write(res BatchResult) error {
+	return w.enc.Encode(newResultRecord(res))
+}
+
+func (w *jsonlResultWriter) Flush() error { return nil }
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testResults() <-chan BatchResult {
+	yes := true
+	results := make(chan BatchResult, 3)
+	results <- BatchResult{Index: 0, N: 2, Result: &yes, Latency: 1500 * time.Microsecond,
+		Metadata: CallMetadata{Model: "gemini-2.0-flash-lite", TemplateVersion: "1", Usage: Usage{Requests: 1, PromptTokens: 10, CompletionTokens: 1, CostUSD: 0.00000105}}}
+	results <- BatchResult{Index: 2, N: 5, Latency: 3 * time.Millisecond}
+	results <- BatchResult{Index: 1, N: 3, Err: errors.New("quota, exceeded")}
+	close(results)
+	return results
+}
+
+func TestCSVResultWriter(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteResults(NewCSVResultWriter(&sb), testResults()); err != nil {
+		t.Fatalf("WriteResults() failed: %v", err)
+	}
+	want := `index,n,answer,error,latency_ms,model,template_version,requests,prompt_tokens,completion_tokens,cost_usd
+0,2,true,,1,gemini-2.0-flash-lite,1,1,10,1,0.00000105
+2,5,undefined,,3,,,0,0,0,0
+1,3,undefined,"quota, exceeded",0,,,0,0,0,0
+`
+	if got := sb.String(); got != want {
+		t.Errorf("CSV output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestJSONLResultWriter(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteResults(NewJSONLResultWriter(&sb), testResults()); err != nil {
+		t.Fatalf("WriteResults() failed: %v", err)
+	}
+	want := `{"index":0,"n":2,"answer":true,"latency_ms":1,"model":"gemini-2.0-flash-lite","template_version":"1","requests":1,"prompt_tokens":10,"completion_tokens":1,"cost_usd":0.00000105}
+{"index":2,"n":5,"answer":null,"latency_ms":3,"requests":0,"prompt_tokens":0,"completion_tokens":0,"cost_usd":0}
+{"index":1,"n":3,"answer":null,"error":"quota, exceeded","latency_ms":0,"requests":0,"prompt_tokens":0,"completion_tokens":0,"cost_usd":0}
+`
+	if got := sb.String(); got != want {
+		t.Errorf("JSONL output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteResults_Error(t *testing.T) {
+	results := testResults()
+	if err := WriteResults(NewJSONLResultWriter(failingWriter{}), results); err == nil {
+		t.Error("WriteResults() to a failing writer succeeded")
+	}
+	if _, ok := <-results; ok {
+		t.Error("WriteResults() did not drain the results after failing")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/philwo/is-even-ai/core"
+)
+
+const geminiExtractPrompt = "Find all whole numbers in the following text, written as digits or as words, and report for each whether it is even. " +
+	"List them in the order they appear, repeating numbers that appear more than once. Text:\n\n"
+
+// geminiExtractSchema is the structured output of ExtractAndCheck.
+var geminiExtractSchema = &genai.Schema{
+	Type: genai.TypeArray,
+	Items: &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"number": {Type: genai.TypeInteger, Format: "int64"},
+			"even":   {Type: genai.TypeBoolean},
+		},
+		Required: []string{"number", "even"},
+	},
+}
+
+// NumberParity is a number found by ExtractAndCheck and the model's answer
+// to whether it is even.
+type NumberParity struct {
+	Number int  `json:"number"`
+	Even   bool `json:"even"`
+}
+
+// ExtractAndCheck asks the model to find all numbers in text, e.g. a support
+// ticket, and whether each of them is even. Numbers are reported in the order
+// they appear, as often as they appear; the result is empty if text has none.
+//
+// Like IsEvenFromImage, the question bypasses middleware, but counts towards
+// Usage and the Budget and is recorded in the audit log. A response that is
+// not the requested JSON fails with an error wrapping ErrUnparsableAnswer.
+func (ai *IsEvenAiGemini) ExtractAndCheck(ctx context.Context, text string) ([]NumberParity, error) {
+	if err := ai.Drainer().Enter(); err != nil {
+		return nil, err
+	}
+	defer ai.Drainer().Leave()
+	if err := ai.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+
+	info := CallInfo{Predicate: "extractAndCheck"}
+	callCtx, cancel := withCallTimeout(core.ContextWithCallInfo(ctx, info), ai.timeout)
+	defer cancel()
+	start := time.Now()
+	call := geminiCall{model: ai.modelName}
+	prompt := geminiExtractPrompt + text
+	ai.logger.Debug("gemini request started", "model", call.model, "predicate", info.Predicate)
+	numbers, err := ai.extract(callCtx, prompt, &call)
+	if err != nil {
+		ai.logger.Debug("gemini request failed", "model", call.model, "latency", time.Since(start), "err", err)
+	}
+	ai.audit(start, info, LabelsFromContext(ctx), prompt, call, nil, err)
+	return numbers, err
+}
+
+// extract sends prompt to a model answering in JSON and decodes the answer.
+func (ai *IsEvenAiGemini) extract(ctx context.Context, prompt string, call *geminiCall) ([]NumberParity, error) {
+	model := ai.freeformModel()
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = geminiExtractSchema
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content from Gemini API: %w", geminiError(call.model, err))
+	}
+	ai.recordUsage(ctx, call.model, resp)
+	call.rawAnswer = geminiResponseText(resp)
+	numbers := []NumberParity{}
+	if err := json.Unmarshal([]byte(call.rawAnswer), &numbers); err != nil {
+		ai.logger.Warn("gemini answer not understood", "model", call.model, "text", call.rawAnswer)
+		return nil, fmt.Errorf("%w: %v", ErrUnparsableAnswer, err)
+	}
+	return numbers, nil
+}
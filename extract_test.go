@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_ExtractAndCheck(t *testing.T) {
+	var gotBody string
+	answer := `[{"number": 3, "even": false}, {"number": 12, "even": true}]`
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		geminitest.WriteAnswer(w, r, answer)
+	})
+
+	ticket := "My order of three widgets arrived, but box 12 was missing."
+	got, err := ai.ExtractAndCheck(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("ExtractAndCheck() failed: %v", err)
+	}
+	if want := []NumberParity{{3, false}, {12, true}}; !slices.Equal(got, want) {
+		t.Errorf("ExtractAndCheck() = %v, want %v", got, want)
+	}
+	if !strings.Contains(gotBody, ticket) || !strings.Contains(gotBody, `"responseMimeType":"application/json"`) || strings.Contains(gotBody, geminiSystemPrompt) {
+		t.Errorf("Request should ask for JSON about the text without the true/false system prompt, got: %s", gotBody)
+	}
+	if u := ai.Usage(); u.Requests != 1 {
+		t.Errorf("Expected the query to count towards usage, got %+v", u)
+	}
+
+	answer = "[]"
+	if got, err := ai.ExtractAndCheck(context.Background(), "No numbers here."); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("ExtractAndCheck() without numbers = %v, %v; want an empty result", got, err)
+	}
+	answer = "true"
+	if _, err := ai.ExtractAndCheck(context.Background(), ticket); !errors.Is(err, ErrUnparsableAnswer) {
+		t.Errorf("ExtractAndCheck() with a plain answer = %v, want ErrUnparsableAnswer", err)
+	}
+}
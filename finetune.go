@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTuningBaseModel is the model tuned by StartTuning unless configured otherwise.
+const defaultTuningBaseModel = "models/gemini-1.5-flash-001-tuning"
+
+// TrainingExample is a prompt and the answer a model should give to it.
+type TrainingExample struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"` // "true" or "false"
+}
+
+// ParityTrainingSet returns training examples asking IsEven and, if templates
+// has an IsOdd template, IsOdd about each of numbers, answered arithmetically.
+func ParityTrainingSet(templates IsEvenAiCorePromptTemplates, numbers []int) []TrainingExample {
+	var examples []TrainingExample
+	for _, n := range numbers {
+		if templates.IsEven != nil {
+			examples = append(examples, TrainingExample{Prompt: templates.IsEven(n), Answer: strconv.FormatBool(n%2 == 0)})
+		}
+		if templates.IsOdd != nil {
+			examples = append(examples, TrainingExample{Prompt: templates.IsOdd(n), Answer: strconv.FormatBool(n%2 != 0)})
+		}
+	}
+	return examples
+}
+
+// WriteTrainingSet writes examples to w as JSON Lines, one object with
+// "prompt" and "answer" fields per line, for tuning with other tools.
+func WriteTrainingSet(w io.Writer, examples []TrainingExample) error {
+	enc := json.NewEncoder(w)
+	for _, e := range examples {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GeminiTuningOptions configures StartTuning. All fields are optional; zero
+// hyperparameters are chosen by the API.
+type GeminiTuningOptions struct {
+	BaseModel    string // Defaults to "models/gemini-1.5-flash-001-tuning"
+	DisplayName  string
+	EpochCount   int
+	BatchSize    int
+	LearningRate float64
+}
+
+// GeminiTuningJob is the tuning of a model with the Gemini API.
+type GeminiTuningJob struct {
+	// Model is the name of the tuned model, e.g. "tunedModels/is-even-abc123".
+	// Once the job is done, use it as GeminiModelOptions.Model. Tuned models
+	// are billed like their base model, so add a PriceTable entry for it.
+	Model string
+
+	ai *IsEvenAiGemini
+}
+
+// StartTuning starts tuning a model on examples, e.g. from ParityTrainingSet,
+// and returns without waiting for it to finish. Use Wait to do so. The
+// examples do not include the system instruction, which queries still send.
+func (ai *IsEvenAiGemini) StartTuning(ctx context.Context, examples []TrainingExample, opts GeminiTuningOptions) (*GeminiTuningJob, error) {
+	if len(examples) == 0 {
+		return nil, errors.New("tuning needs at least one training example")
+	}
+	if opts.BaseModel == "" {
+		opts.BaseModel = defaultTuningBaseModel
+	}
+	type example struct {
+		TextInput string `json:"textInput"`
+		Output    string `json:"output"`
+	}
+	data := make([]example, len(examples))
+	for i, e := range examples {
+		data[i] = example{TextInput: e.Prompt, Output: e.Answer}
+	}
+	hyperparameters := map[string]any{}
+	if opts.EpochCount > 0 {
+		hyperparameters["epochCount"] = opts.EpochCount
+	}
+	if opts.BatchSize > 0 {
+		hyperparameters["batchSize"] = opts.BatchSize
+	}
+	if opts.LearningRate > 0 {
+		hyperparameters["learningRate"] = opts.LearningRate
+	}
+	body := map[string]any{
+		"baseModel": opts.BaseModel,
+		"tuningTask": map[string]any{
+			"hyperparameters": hyperparameters,
+			"trainingData":    map[string]any{"examples": map[string]any{"examples": data}},
+		},
+	}
+	if opts.DisplayName != "" {
+		body["displayName"] = opts.DisplayName
+	}
+
+	var op struct {
+		Name     string `json:"name"`
+		Metadata struct {
+			TunedModel string `json:"tunedModel"`
+		} `json:"metadata"`
+	}
+	if err := ai.rest(ctx, http.MethodPost, "v1beta/tunedModels", body, &op); err != nil {
+		return nil, fmt.Errorf("failed to start Gemini tuning: %w", err)
+	}
+	model := op.Metadata.TunedModel
+	if model == "" {
+		model, _, _ = strings.Cut(op.Name, "/operations/")
+	}
+	if !strings.HasPrefix(model, "tunedModels/") {
+		return nil, fmt.Errorf("failed to start Gemini tuning: unexpected operation %q", op.Name)
+	}
+	ai.logger.Debug("gemini tuning started", "model", model, "base", opts.BaseModel, "examples", len(examples))
+	return &GeminiTuningJob{Model: model, ai: ai}, nil
+}
+
+// Ready reports whether the tuned model is ready for use. It returns an error
+// if tuning failed.
+func (j *GeminiTuningJob) Ready(ctx context.Context) (bool, error) {
+	var tuned struct {
+		State string `json:"state"`
+	}
+	if err := j.ai.rest(ctx, http.MethodGet, "v1beta/"+j.Model, nil, &tuned); err != nil {
+		return false, fmt.Errorf("failed to get Gemini tuning status: %w", err)
+	}
+	switch tuned.State {
+	case "ACTIVE":
+		return true, nil
+	case "FAILED":
+		return false, fmt.Errorf("gemini tuning of %s failed", j.Model)
+	default:
+		return false, nil
+	}
+}
+
+// Wait polls every interval (at least one second) until the tuned model is
+// ready, tuning failed or ctx is done.
+func (j *GeminiTuningJob) Wait(ctx context.Context, interval time.Duration) error {
+	interval = max(interval, time.Second)
+	for {
+		ready, err := j.Ready(ctx)
+		if ready || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestParityTrainingSet(t *testing.T) {
+	examples := ParityTrainingSet(DefaultGeminiPromptTemplates, []int{2, -3})
+	want := []TrainingExample{
+		{"Is 2 an even number?", "true"},
+		{"Is 2 an odd number?", "false"},
+		{"Is -3 an even number?", "false"},
+		{"Is -3 an odd number?", "true"},
+	}
+	if !slices.Equal(examples, want) {
+		t.Errorf("ParityTrainingSet() = %+v; want %+v", examples, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTrainingSet(&buf, examples[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != `{"prompt":"Is 2 an even number?","answer":"true"}`+"\n" {
+		t.Errorf("WriteTrainingSet() wrote %q", got)
+	}
+}
+
+func TestIsEvenAiGemini_StartTuning(t *testing.T) {
+	var req struct {
+		BaseModel  string `json:"baseModel"`
+		TuningTask struct {
+			Hyperparameters map[string]any `json:"hyperparameters"`
+			TrainingData    struct {
+				Examples struct {
+					Examples []map[string]string `json:"examples"`
+				} `json:"examples"`
+			} `json:"trainingData"`
+		} `json:"tuningTask"`
+	}
+	polls := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1beta/tunedModels":
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &req); err != nil {
+				t.Errorf("Invalid tuning request %s: %v", body, err)
+			}
+			_, _ = io.WriteString(w, `{"name":"tunedModels/is-even-1/operations/op-1","metadata":{"tunedModel":"tunedModels/is-even-1"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1beta/tunedModels/is-even-1":
+			polls++
+			state := "CREATING"
+			if polls > 1 {
+				state = "ACTIVE"
+			}
+			_, _ = io.WriteString(w, `{"name":"tunedModels/is-even-1","state":"`+state+`"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	job, err := ai.StartTuning(ctx, ParityTrainingSet(DefaultGeminiPromptTemplates, []int{1, 2}), GeminiTuningOptions{EpochCount: 5})
+	if err != nil {
+		t.Fatalf("StartTuning failed: %v", err)
+	}
+	if job.Model != "tunedModels/is-even-1" {
+		t.Errorf("Expected tuned model tunedModels/is-even-1, got %q", job.Model)
+	}
+	examples := req.TuningTask.TrainingData.Examples.Examples
+	if req.BaseModel != defaultTuningBaseModel || req.TuningTask.Hyperparameters["epochCount"] != 5.0 || len(examples) != 4 ||
+		examples[0]["textInput"] != "Is 1 an even number?" || examples[0]["output"] != "false" {
+		t.Errorf("Unexpected tuning request: %+v", req)
+	}
+
+	if ready, err := job.Ready(ctx); ready || err != nil {
+		t.Errorf("Ready() = %v, %v while creating", ready, err)
+	}
+	if err := job.Wait(ctx, 0); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+}
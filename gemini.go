@@ -9,15 +9,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/philwo/is-even-ai/core"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
+const defaultSessionMaxTurns = 10
+
+const defaultGeminiEndpoint = "https://generativelanguage.googleapis.com"
+
+const defaultEmbeddingModel = ModelGeminiEmbedding004
+
 const geminiSystemPrompt = "You are an AI assistant designed to answer questions about numbers. You will only answer with only the word true or false."
 
+const geminiVerifyPrompt = "Carefully verify your previous answer. Answer with only the word true or false."
+
+const geminiDoubleCheckPrompt = "Are you sure? Answer true or false."
+
 // DefaultGeminiPromptTemplates provides standard prompt templates suitable for Gemini.
 var DefaultGeminiPromptTemplates = IsEvenAiCorePromptTemplates{
 	IsEven:        func(n int) string { return fmt.Sprintf("Is %d an even number?", n) },
@@ -26,18 +46,184 @@ var DefaultGeminiPromptTemplates = IsEvenAiCorePromptTemplates{
 	AreNotEqual:   func(a, b int) string { return fmt.Sprintf("Are %d and %d not equal?", a, b) },
 	IsGreaterThan: func(a, b int) string { return fmt.Sprintf("Is %d greater than %d?", a, b) },
 	IsLessThan:    func(a, b int) string { return fmt.Sprintf("Is %d less than %d?", a, b) },
+	IsLeapYear:    func(year int) string { return fmt.Sprintf("Is %d a leap year?", year) },
+	IsPerfectNumber: func(n int) string {
+		return fmt.Sprintf("Is %d a perfect number, i.e. equal to the sum of its proper divisors?", n)
+	},
+	IsHappyNumber: func(n int) string {
+		return fmt.Sprintf("Is %d a happy number, i.e. does repeatedly summing the squares of its digits reach 1?", n)
+	},
+	IsTriangularNumber: func(n int) string {
+		return fmt.Sprintf("Is %d a triangular number, i.e. equal to 1 + 2 + ... + k for some k?", n)
+	},
+	IsFactorOf: func(a, b int) string {
+		return fmt.Sprintf("Is %d a factor of %d, i.e. can %d be divided by %d without a remainder?", a, b, b, a)
+	},
+	SumIsEven:     func(a, b int) string { return fmt.Sprintf("Is the sum of %d and %d an even number?", a, b) },
+	ProductIsEven: func(a, b int) string { return fmt.Sprintf("Is the product of %d and %d an even number?", a, b) },
+	Version:       "1",
 }
 
 // GeminiClientOptions holds configuration for the Gemini client.
 type GeminiClientOptions struct {
-	APIKey  string
-	BaseURL string // Optional: To override the default Gemini API endpoint
+	APIKey string
+	// Optional: authenticates requests with OAuth2 bearer tokens instead of
+	// APIKey, e.g. for gateways that mint short-lived tokens. See
+	// BearerTokenFunc for token callbacks.
+	TokenSource oauth2.TokenSource
+	// Optional: overrides the default Gemini API endpoint. It may include a
+	// path prefix, e.g. "https://gateway.example.com/gemini", under which the
+	// API paths such as /v1beta/models/... are appended.
+	BaseURL string
+	// Optional: the complete URL that content generation requests are sent
+	// to, for gateways that expose it under a path of their own. Its query
+	// string, if any, replaces the SDK's. Other requests, such as streaming in
+	// session mode, token counting and model validation, still use BaseURL.
+	FullEndpointURL string
+	// Optional: if true, the constructor checks that the configured model exists
+	// and returns ErrModelNotFound instead of failing on the first query.
+	ValidateModel bool
+	// Optional: receives request lifecycle, fallback and parse failure logs,
+	// with the API key redacted. Defaults to discarding them.
+	Logger *slog.Logger
+	// Optional: if true, every HTTP exchange with the API is logged at debug
+	// level, including full prompts, raw responses and status codes, with the
+	// API key redacted. Logs go to Logger, or to stderr if Logger is nil.
+	Debug bool
+	// Optional: if set, every query is appended to the audit log.
+	AuditLog *AuditLog
+	// Optional: the transport used for all API requests, e.g. a vcr.Recorder.
+	// Defaults to the SDK's transport, or to the fetch API on js/wasm. On
+	// wasip1, which has no sockets, it must be set for Gemini queries to work.
+	HTTPTransport http.RoundTripper
+	// Optional: connection pool and protocol settings for the default
+	// transport. Ignored if HTTPTransport is set.
+	Transport HTTPTransportOptions
+}
+
+// String describes the options with the API key redacted, so that they can
+// be printed safely.
+func (o GeminiClientOptions) String() string {
+	type plain GeminiClientOptions // Without this method
+	p := plain(o)
+	if p.APIKey != "" {
+		p.APIKey = redacted
+	}
+	p.BaseURL, p.FullEndpointURL = withoutQuery(p.BaseURL), withoutQuery(p.FullEndpointURL)
+	return fmt.Sprintf("%+v", p)
+}
+
+// LogValue implements slog.LogValuer, logging the options that identify the
+// client, but never the API key.
+func (o GeminiClientOptions) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Bool("api_key", o.APIKey != ""), slog.Bool("token_source", o.TokenSource != nil)}
+	if o.BaseURL != "" {
+		attrs = append(attrs, slog.String("base_url", withoutQuery(o.BaseURL)))
+	}
+	if o.FullEndpointURL != "" {
+		attrs = append(attrs, slog.String("full_endpoint_url", withoutQuery(o.FullEndpointURL)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// withoutQuery strips the query string, which may hold a key, from rawURL.
+func withoutQuery(rawURL string) string {
+	before, _, _ := strings.Cut(rawURL, "?")
+	return before
 }
 
 // GeminiModelOptions specifies options for the Gemini model.
 type GeminiModelOptions struct {
+	// Model is a model name, e.g. ModelGeminiFlash, or an alias such as
+	// "cheap" (see SetModelAlias). Optional: defaults to DefaultGeminiModel.
 	Model       string
 	Temperature *float32 // Pointer to allow distinguishing between 0 and not set.
+	// SessionMode keeps one multi-turn chat per instance instead of sending each
+	// question as an independent request. The Gemini API is stateless, so earlier
+	// turns are still sent along; SessionMaxTurns bounds how many are kept.
+	SessionMode     bool
+	SessionMaxTurns int // Optional: defaults to 10 when SessionMode is set
+	// FallbackModels are tried in order when a model is not found (404) or
+	// out of capacity (429 or 503), e.g. []string{"gemini-1.5-flash", "accurate"}.
+	// The SDK itself retries 503 responses until the call times out, so in
+	// practice fallback happens on 404 and 429. Fallback requests are always
+	// standalone, even in session mode.
+	FallbackModels []string
+	// VerifyAnswers enables accuracy mode: every defined answer is followed by a
+	// second turn asking the model to verify it, and the verified answer is returned.
+	// This doubles the number of requests.
+	VerifyAnswers bool
+	// DoubleCheck enables self-reflection: every defined answer is followed by
+	// a second turn asking "Are you sure?", and the answer is only returned if
+	// both turns agree, otherwise it is undefined. Like VerifyAnswers, this
+	// doubles the number of requests; it takes precedence if both are set.
+	DoubleCheck bool
+	// OnVerificationFlip, if set, is called when verification or a double
+	// check changes an answer. verified is nil if the model gave no clear
+	// answer the second time.
+	OnVerificationFlip func(prompt string, first bool, verified *bool)
+	// PriceTable maps model names to prices for cost accounting in Usage.
+	// Optional: defaults to DefaultPriceTable.
+	PriceTable map[string]ModelPrice
+	// Budget, if set, makes queries fail with ErrBudgetExceeded once the
+	// instance has spent the given amount of money or tokens. A MaxCostUSD
+	// requires a price in PriceTable for Model and every fallback model.
+	Budget Budget
+	// Timeout limits each query, including fallback and verification requests.
+	// Optional: defaults to 30 seconds; negative means no limit. Override it
+	// per call with WithCallTimeout; an earlier context deadline also applies.
+	Timeout time.Duration
+	// EmbeddingModel is the model used by Embed.
+	// Optional: defaults to "text-embedding-004".
+	EmbeddingModel string
+	// PromptTemplates replaces DefaultGeminiPromptTemplates, e.g. with
+	// templates loaded with ReadPromptTemplateFile. Optional: used if IsEven is set.
+	PromptTemplates IsEvenAiCorePromptTemplates
+	// DeterministicMode makes the same question yield the same answer across
+	// runs: it enforces temperature 0 and greedy decoding (topK 1, a single
+	// candidate), and sends a fixed seed with batch jobs (the SDK used for
+	// other queries does not support seeds). Options whose answers depend on
+	// more than the question, i.e. a non-zero Temperature, SessionMode and
+	// FallbackModels, are rejected.
+	DeterministicMode bool
+}
+
+// deterministicSeed is the seed of batch requests in DeterministicMode.
+const deterministicSeed int32 = 0
+
+// checkDeterministic returns an error if config conflicts with its
+// DeterministicMode.
+func (config GeminiModelOptions) checkDeterministic() error {
+	switch {
+	case !config.DeterministicMode:
+		return nil
+	case config.Temperature != nil && *config.Temperature != 0:
+		return fmt.Errorf("gemini DeterministicMode requires temperature 0, got %g", *config.Temperature)
+	case config.SessionMode:
+		return errors.New("gemini DeterministicMode conflicts with SessionMode")
+	case len(config.FallbackModels) > 0:
+		return errors.New("gemini DeterministicMode conflicts with FallbackModels")
+	}
+	return nil
+}
+
+// checkPriced returns an error if config has a cost budget but no price for
+// one of its models, whose queries would then cost nothing and never use up
+// the budget.
+func (config GeminiModelOptions) checkPriced() error {
+	if config.Budget.MaxCostUSD <= 0 {
+		return nil
+	}
+	prices := config.PriceTable
+	if prices == nil {
+		prices = DefaultPriceTable
+	}
+	for _, model := range append([]string{config.Model}, config.FallbackModels...) {
+		if _, ok := prices[model]; !ok {
+			return fmt.Errorf("gemini Budget.MaxCostUSD requires a price for model %q in PriceTable", model)
+		}
+	}
+	return nil
 }
 
 // IsEvenAiGemini is an implementation of IsEvenAiCore using the Gemini API.
@@ -47,17 +233,92 @@ type IsEvenAiGemini struct {
 	genaiClient *genai.Client
 	apiKey      string
 	modelName   string
+	temperature float32
+	restClient  *http.Client // Authenticated client for requests not covered by the SDK
+	restBase    string       // Base URL of the API, without a trailing slash
+	embedModel  *genai.EmbeddingModel
+
+	fallbackModels     []*genai.GenerativeModel // Tried in order when the primary model is gone or overloaded
+	fallbackModelNames []string
+
+	usage    *usageTracker
+	logger   *slog.Logger
+	auditLog *AuditLog
+	timeout  time.Duration // Per query; non-positive for none
+
+	verifyAnswers      bool
+	doubleCheck        bool
+	onVerificationFlip func(prompt string, first bool, verified *bool)
+	deterministic      bool // GeminiModelOptions.DeterministicMode
+
+	chat            *genai.ChatSession // Non-nil in session mode
+	chatMu          sync.Mutex         // Serializes turns of chat
+	sessionMaxTurns int
 }
 
 // NewIsEvenAiGemini creates a new IsEvenAiGemini client.
 func NewIsEvenAiGemini(clientOpts GeminiClientOptions, modelConfigOpts ...GeminiModelOptions) (*IsEvenAiGemini, error) {
-	if clientOpts.APIKey == "" {
+	if clientOpts.APIKey == "" && clientOpts.TokenSource == nil {
 		return nil, errors.New("gemini API key is required")
 	}
 
+	logger := clientOpts.Logger
+	if clientOpts.Debug {
+		logger = newDebugLogger(logger)
+	}
+	if logger != nil {
+		logger = newRedactingLogger(logger, clientOpts.APIKey)
+	} else {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
 	opts := []option.ClientOption{option.WithAPIKey(clientOpts.APIKey)}
+	if clientOpts.TokenSource != nil {
+		opts = []option.ClientOption{option.WithTokenSource(clientOpts.TokenSource)}
+	}
+	restBase := defaultGeminiEndpoint
 	if clientOpts.BaseURL != "" {
-		opts = append(opts, option.WithEndpoint(clientOpts.BaseURL))
+		baseURL, err := parseEndpoint("BaseURL", clientOpts.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		// Without a trailing slash, which would double up with the API path.
+		baseURL.Path = strings.TrimRight(baseURL.Path, "/")
+		baseURL.RawPath = ""
+		restBase = baseURL.String()
+		opts = append(opts, option.WithEndpoint(restBase))
+	}
+	var fullEndpoint *url.URL
+	if clientOpts.FullEndpointURL != "" {
+		var err error
+		if fullEndpoint, err = parseEndpoint("FullEndpointURL", clientOpts.FullEndpointURL); err != nil {
+			return nil, err
+		}
+	}
+	transport := clientOpts.HTTPTransport
+	if transport == nil {
+		transport = defaultTransport()
+		if clientOpts.Transport != (HTTPTransportOptions{}) {
+			transport = tunedTransport(transport, clientOpts.Transport)
+		}
+	}
+	// Calls the SDK doesn't support, such as batch jobs, are made with restClient.
+	restClient := &http.Client{Transport: authTransport(http.DefaultTransport, clientOpts)}
+	if transport != nil || clientOpts.Debug || fullEndpoint != nil || clientOpts.TokenSource != nil {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		if clientOpts.Debug {
+			transport = &debugTransport{base: transport, logger: logger}
+		}
+		if fullEndpoint != nil {
+			// Outside the debug transport, so that it logs the rewritten URL.
+			transport = &endpointTransport{base: transport, endpoint: fullEndpoint}
+		}
+		// A custom HTTP client bypasses the SDK's authentication, so it is added by the transport.
+		transport = authTransport(transport, clientOpts)
+		restClient = &http.Client{Transport: transport}
+		opts = append(opts, option.WithHTTPClient(restClient))
 	}
 
 	// Use a context with timeout for client creation
@@ -69,28 +330,47 @@ func NewIsEvenAiGemini(clientOpts GeminiClientOptions, modelConfigOpts ...Gemini
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
 
-	config := GeminiModelOptions{
-		Model: "gemini-2.0-flash-lite", // Default model
-	}
-	var defaultTemp float32 = 0.0
-	config.Temperature = &defaultTemp
-
+	var config GeminiModelOptions
 	if len(modelConfigOpts) > 0 {
-		if modelConfigOpts[0].Model != "" {
-			config.Model = modelConfigOpts[0].Model
-		}
-		if modelConfigOpts[0].Temperature != nil {
-			config.Temperature = modelConfigOpts[0].Temperature
-		}
+		config = modelConfigOpts[0]
 	}
-
-	genaiModel := createdGenaiClient.GenerativeModel(config.Model)
-	genaiModel.SystemInstruction = &genai.Content{
-		Parts: []genai.Part{genai.Text(geminiSystemPrompt)},
+	if config.Model == "" {
+		config.Model = DefaultGeminiModel
+	}
+	config.Model = ResolveModel(config.Model)
+	config.FallbackModels = slices.Clone(config.FallbackModels)
+	for i, name := range config.FallbackModels {
+		config.FallbackModels[i] = ResolveModel(name)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultCallTimeout
+	}
+	if config.EmbeddingModel == "" {
+		config.EmbeddingModel = defaultEmbeddingModel
+	}
+	if config.Temperature == nil {
+		var defaultTemp float32 = 0.0
+		config.Temperature = &defaultTemp
+	}
+	if config.PromptTemplates.IsEven == nil {
+		config.PromptTemplates = DefaultGeminiPromptTemplates
+	} else if err := config.PromptTemplates.Validate(); err != nil {
+		_ = createdGenaiClient.Close()
+		return nil, err
+	}
+	if err := config.checkDeterministic(); err != nil {
+		_ = createdGenaiClient.Close()
+		return nil, err
+	}
+	if err := config.checkPriced(); err != nil {
+		_ = createdGenaiClient.Close()
+		return nil, err
 	}
 
-	if config.Temperature != nil {
-		genaiModel.SetTemperature(*config.Temperature)
+	genaiModel := newGenerativeModel(createdGenaiClient, config.Model, config)
+	var fallbackModels []*genai.GenerativeModel
+	for _, name := range config.FallbackModels {
+		fallbackModels = append(fallbackModels, newGenerativeModel(createdGenaiClient, name, config))
 	}
 
 	ai := &IsEvenAiGemini{
@@ -98,48 +378,479 @@ func NewIsEvenAiGemini(clientOpts GeminiClientOptions, modelConfigOpts ...Gemini
 		genaiModel:  genaiModel,
 		genaiClient: createdGenaiClient,
 		modelName:   config.Model,
+		temperature: *config.Temperature,
+		restClient:  restClient,
+		restBase:    restBase,
+		embedModel:  createdGenaiClient.EmbeddingModel(config.EmbeddingModel),
+
+		fallbackModels:     fallbackModels,
+		fallbackModelNames: config.FallbackModels,
+
+		usage:    newUsageTracker(config.PriceTable, config.Budget),
+		logger:   logger,
+		auditLog: clientOpts.AuditLog,
+		timeout:  config.Timeout,
+
+		verifyAnswers:      config.VerifyAnswers,
+		doubleCheck:        config.DoubleCheck,
+		onVerificationFlip: config.OnVerificationFlip,
+		deterministic:      config.DeterministicMode,
+	}
+
+	if clientOpts.ValidateModel {
+		if err := ai.Ping(ctx); err != nil {
+			_ = createdGenaiClient.Close()
+			return nil, err
+		}
+	}
+
+	if config.SessionMode {
+		ai.sessionMaxTurns = defaultSessionMaxTurns
+		if config.SessionMaxTurns > 0 {
+			ai.sessionMaxTurns = config.SessionMaxTurns
+		}
+		ai.chat = genaiModel.StartChat()
 	}
 
-	// Each API call gets its own context with a timeout. This makes the query robust
-	// against network issues for individual calls and independent of the client creation context.
-	queryFunc := func(prompt string) (*bool, error) {
-		apiCallCtx, apiCallCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer apiCallCancel()
+	ai.IsEvenAiCore = core.NewIsEvenAiCoreWithHandler(config.PromptTemplates, ai.query, AnswerSource{Provider: "gemini", Model: config.Model, SystemPromptHash: HashSystemPrompt(geminiSystemPrompt)})
+	return ai, nil
+}
+
+// geminiCall collects details about a single query beyond its answer.
+type geminiCall struct {
+	model     string // Model that answered, which may be a fallback model
+	rawAnswer string // Text of the first answer
+}
 
-		resp, err := ai.genaiModel.GenerateContent(apiCallCtx, genai.Text(prompt))
+// query answers prompt and appends the outcome to the audit log, if any.
+func (ai *IsEvenAiGemini) query(ctx context.Context, prompt string) (*bool, error) {
+	start := time.Now()
+	call := geminiCall{model: ai.modelName}
+	answer, err := ai.ask(ctx, prompt, &call)
+	info, _ := CallInfoFromContext(ctx)
+	ai.audit(start, info, LabelsFromContext(ctx), prompt, call, answer, err)
+	return answer, err
+}
+
+// audit appends the outcome of a query started at start to the audit log, if any.
+func (ai *IsEvenAiGemini) audit(start time.Time, info CallInfo, labels Labels, prompt string, call geminiCall, answer *bool, err error) {
+	if ai.auditLog == nil {
+		return
+	}
+	rec := AuditRecord{
+		Time:            start,
+		Predicate:       info.Predicate,
+		Args:            info.Args,
+		Prompt:          prompt,
+		Model:           call.model,
+		RawAnswer:       call.rawAnswer,
+		TemplateVersion: info.Source.TemplateVersion,
+		Result:          answer,
+		Labels:          labels,
+		LatencyMs:       time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if auditErr := ai.auditLog.Record(rec); auditErr != nil {
+		ai.logger.Error("gemini query not audited", "err", auditErr)
+	}
+}
+
+// ask sends prompt to Gemini and interprets the answer as true, false or undefined.
+// Each API call gets its own timeout (see GeminiModelOptions.Timeout). This makes
+// the query robust against network issues for individual calls and independent
+// of the client creation context.
+func (ai *IsEvenAiGemini) ask(ctx context.Context, prompt string, call *geminiCall) (*bool, error) {
+	if err := ai.usage.checkBudget(); err != nil {
+		ai.logger.Warn("gemini query rejected", "model", ai.modelName, "err", err)
+		return nil, err
+	}
+
+	apiCallCtx, apiCallCancel := withCallTimeout(ctx, ai.timeout)
+	defer apiCallCancel()
+
+	start := time.Now()
+	ai.logger.Debug("gemini request started", "model", call.model)
+	resp, err := ai.generate(apiCallCtx, prompt)
+	answeringModel := ai.genaiModel
+	for i := 0; err != nil && isGeminiFallbackError(err) && i < len(ai.fallbackModels); i++ {
+		ai.logger.Warn("gemini model unavailable, falling back", "model", call.model, "fallback", ai.fallbackModelNames[i], "err", err)
+		info, _ := CallInfoFromContext(ctx)
+		eventsFromContext(ctx).FallbackUsed(ctx, info, call.model, ai.fallbackModelNames[i], err)
+		call.model, answeringModel = ai.fallbackModelNames[i], ai.fallbackModels[i]
+		resp, err = generateContent(apiCallCtx, answeringModel, prompt)
+	}
+	model := call.model
+	if err != nil {
+		ai.logger.Debug("gemini request failed", "model", model, "latency", time.Since(start), "err", err)
+		return nil, fmt.Errorf("failed to generate content from Gemini API: %w", geminiError(model, err))
+	}
+	ai.recordUsage(ctx, model, resp)
+	call.rawAnswer = geminiResponseText(resp)
+	answer, err := parseGeminiResponse(resp)
+	switch {
+	case err != nil:
+		ai.logger.Warn("gemini response rejected", "model", model, "err", err)
+	case answer == nil:
+		ai.logger.Warn("gemini answer not understood", "model", model, "text", call.rawAnswer)
+	default:
+		ai.logger.Debug("gemini request finished", "model", model, "latency", time.Since(start), "answer", *answer)
+	}
+	if err != nil || answer == nil || !(ai.verifyAnswers || ai.doubleCheck) {
+		return answer, err
+	}
+	return ai.verify(apiCallCtx, answeringModel, model, prompt, resp.Candidates[0].Content, answer)
+}
+
+// verify asks model, named name, which gave answer, to double-check it in a
+// follow-up turn and returns the verified answer, reporting flips to
+// onVerificationFlip. With doubleCheck set, the answer is undefined unless both
+// turns agree.
+func (ai *IsEvenAiGemini) verify(ctx context.Context, model *genai.GenerativeModel, name, prompt string, answerContent *genai.Content, answer *bool) (*bool, error) {
+	cs := model.StartChat()
+	cs.History = []*genai.Content{
+		genai.NewUserContent(genai.Text(prompt)),
+		{Role: "model", Parts: answerContent.Parts},
+	}
+	followUp := geminiVerifyPrompt
+	if ai.doubleCheck {
+		followUp = geminiDoubleCheckPrompt
+	}
+	resp, err := cs.SendMessage(ctx, genai.Text(followUp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify answer with Gemini API: %w", geminiError(name, err))
+	}
+	ai.recordUsage(ctx, name, resp)
+	verified, err := parseGeminiResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if verified == nil || *verified != *answer {
+		ai.logger.Info("gemini answer changed on verification", "model", name, "first", *answer, "verified", verified)
+		if ai.onVerificationFlip != nil {
+			ai.onVerificationFlip(prompt, *answer, verified)
+		}
+		if ai.doubleCheck {
+			return nil, nil
+		}
+	}
+	return verified, nil
+}
+
+// generate sends prompt either as a standalone request or, in session mode,
+// as the next turn of the instance's chat. The answer is streamed to the
+// OnToken callback of ctx, if any.
+func (ai *IsEvenAiGemini) generate(ctx context.Context, prompt string) (*genai.GenerateContentResponse, error) {
+	if ai.chat == nil {
+		return generateContent(ctx, ai.genaiModel, prompt)
+	}
+
+	ai.chatMu.Lock()
+	defer ai.chatMu.Unlock()
+	before := len(ai.chat.History)
+	var resp *genai.GenerateContentResponse
+	var err error
+	if onToken := core.OnTokenFromContext(ctx); onToken != nil {
+		resp, err = streamGemini(ai.chat.SendMessageStream(ctx, genai.Text(prompt)), onToken)
+	} else {
+		resp, err = ai.chat.SendMessage(ctx, genai.Text(prompt))
+	}
+	if err != nil {
+		// Drop the unanswered question so the next turn doesn't follow a dangling user message.
+		ai.chat.History = ai.chat.History[:before]
+		return nil, err
+	}
+	// Keep only the most recent turns (one user and one model message each).
+	if keep := 2 * ai.sessionMaxTurns; len(ai.chat.History) > keep {
+		ai.chat.History = ai.chat.History[len(ai.chat.History)-keep:]
+	}
+	return resp, nil
+}
+
+// generateContent sends prompt to model, streaming the answer to the OnToken
+// callback of ctx, if any.
+func generateContent(ctx context.Context, model *genai.GenerativeModel, prompt string) (*genai.GenerateContentResponse, error) {
+	if onToken := core.OnTokenFromContext(ctx); onToken != nil {
+		return streamGemini(model.GenerateContentStream(ctx, genai.Text(prompt)), onToken)
+	}
+	return model.GenerateContent(ctx, genai.Text(prompt))
+}
+
+// streamGemini passes the text of each streamed response to onToken and
+// returns the merged response.
+func streamGemini(iter *genai.GenerateContentResponseIterator, onToken func(string)) (*genai.GenerateContentResponse, error) {
+	var usage *genai.UsageMetadata
+	for {
+		chunk, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate content from Gemini API: %w", err)
+			return nil, err
+		}
+		if text := geminiResponseText(chunk); text != "" {
+			onToken(text)
+		}
+		if chunk.UsageMetadata != nil {
+			usage = chunk.UsageMetadata // Reported for the whole answer so far
 		}
+	}
+	resp := iter.MergedResponse()
+	if resp == nil {
+		return nil, errors.New("empty response from model")
+	}
+	if resp.UsageMetadata == nil {
+		resp.UsageMetadata = usage // Not merged by the SDK
+	}
+	return resp, nil
+}
 
-		if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-			if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != genai.BlockReasonUnspecified {
-				return nil, fmt.Errorf("gemini API request blocked, reason: %s", resp.PromptFeedback.BlockReason.String())
-			}
-			return nil, nil // Undefined response
+// recordUsage adds the token counts reported in resp to the instance's usage,
+// under the predicate of the CallInfo in ctx, and to the CallMetadata in ctx,
+// if any.
+func (ai *IsEvenAiGemini) recordUsage(ctx context.Context, model string, resp *genai.GenerateContentResponse) {
+	var promptTokens, completionTokens int64
+	if resp.UsageMetadata != nil {
+		promptTokens = int64(resp.UsageMetadata.PromptTokenCount)
+		completionTokens = int64(resp.UsageMetadata.CandidatesTokenCount)
+	}
+	info, _ := CallInfoFromContext(ctx)
+	cost := ai.usage.record(model, info.Predicate, LabelsFromContext(ctx), promptTokens, completionTokens)
+	core.CallMetadataFromContext(ctx).Record(model, promptTokens, completionTokens, cost)
+}
+
+// Usage returns the cumulative token usage and estimated cost of all requests
+// made by this instance, including fallback and verification requests.
+func (ai *IsEvenAiGemini) Usage() Usage {
+	return ai.usage.snapshot()
+}
+
+// UsageByPredicate breaks Usage down by predicate, e.g. "isEven",
+// "areEqual", "isEvenFromImage" or "extractAndCheck", so that the cost of
+// each kind of question can be attributed. Requests whose context carries no
+// CallInfo, e.g. from a custom QueryHandler, are counted under "".
+func (ai *IsEvenAiGemini) UsageByPredicate() map[string]Usage {
+	return ai.usage.snapshotByPredicate()
+}
+
+// UsageByLabel breaks Usage down by the values of the label name (see
+// WithLabels), e.g. by team. Requests without the label are left out.
+func (ai *IsEvenAiGemini) UsageByLabel(name string) map[string]Usage {
+	return ai.usage.snapshotByLabel(name)
+}
+
+// Deterministic reports whether the instance runs in
+// GeminiModelOptions.DeterministicMode.
+func (ai *IsEvenAiGemini) Deterministic() bool {
+	return ai.deterministic
+}
+
+// BudgetStatus reports how much of GeminiModelOptions.Budget is left.
+func (ai *IsEvenAiGemini) BudgetStatus() BudgetStatus {
+	return ai.usage.budgetStatus()
+}
+
+// newGenerativeModel returns a handle for the named model configured with the
+// system prompt and generation settings from config.
+func newGenerativeModel(client *genai.Client, name string, config GeminiModelOptions) *genai.GenerativeModel {
+	model := client.GenerativeModel(name)
+	model.SystemInstruction = &genai.Content{
+		Parts: []genai.Part{genai.Text(geminiSystemPrompt)},
+	}
+	if config.Temperature != nil {
+		model.SetTemperature(*config.Temperature)
+	}
+	if config.DeterministicMode {
+		model.SetTopK(1)
+		model.SetCandidateCount(1)
+	}
+	return model
+}
+
+// freeformModel returns a handle for the primary model without the system
+// prompt, which only allows true or false, for questions with other answers.
+func (ai *IsEvenAiGemini) freeformModel() *genai.GenerativeModel {
+	model := ai.genaiClient.GenerativeModel(ai.modelName)
+	model.SetTemperature(ai.temperature)
+	if ai.deterministic {
+		model.SetTopK(1)
+		model.SetCandidateCount(1)
+	}
+	return model
+}
+
+// parseGeminiResponse extracts the true/false answer from a Gemini response
+// using ParseAnswer. A missing or unrecognized answer is reported as undefined (nil, nil).
+func parseGeminiResponse(resp *genai.GenerateContentResponse) (*bool, error) {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != genai.BlockReasonUnspecified {
+			return nil, fmt.Errorf("%w: gemini API request blocked, reason: %s", ErrContentFiltered, resp.PromptFeedback.BlockReason.String())
+		}
+		return nil, nil // Undefined response
+	}
+
+	part := resp.Candidates[0].Content.Parts[0]
+	textContent, ok := part.(genai.Text)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response part type: %T from Gemini API. Content: %+v", part, resp.Candidates[0].Content.Parts)
+	}
+
+	answer, err := ParseAnswer(string(textContent))
+	if err != nil {
+		return nil, nil // Unrecognized answers are undefined
+	}
+	return answer.Bool(), nil
+}
+
+// geminiResponseText returns the text of the first candidate in resp, for logging.
+func geminiResponseText(resp *genai.GenerateContentResponse) string {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if text, ok := part.(genai.Text); ok {
+			sb.WriteString(string(text))
 		}
+	}
+	return sb.String()
+}
+
+// ResetSession discards the conversation history kept in session mode.
+// It is a no-op when session mode is disabled.
+func (ai *IsEvenAiGemini) ResetSession() {
+	if ai.chat == nil {
+		return
+	}
+	ai.chatMu.Lock()
+	defer ai.chatMu.Unlock()
+	ai.chat.History = nil
+}
+
+// CountTokens reports how many input tokens the configured model would be billed
+// for prompt, including the system instruction that accompanies every query.
+func (ai *IsEvenAiGemini) CountTokens(ctx context.Context, prompt string) (int, error) {
+	resp, err := ai.genaiModel.CountTokens(ctx, genai.Text(prompt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens with Gemini API: %w", err)
+	}
+	return int(resp.TotalTokens), nil
+}
 
-		part := resp.Candidates[0].Content.Parts[0]
-		textContent, ok := part.(genai.Text)
+// EstimateCost predicts the usage and cost of asking IsEven for each of numbers
+// with the given model (the configured model if empty), so a batch can be
+// approved before it runs. Prompt tokens are counted with one CountTokens call,
+// which the API does not charge for, per prompt shape: numbers with the same
+// sign and count of digits are assumed to take as many tokens. Each answer is
+// assumed to take one completion token. Verification requests are not
+// included. A model without a price in the PriceTable cannot be estimated.
+func (ai *IsEvenAiGemini) EstimateCost(ctx context.Context, numbers []int, model string) (Usage, error) {
+	if model == "" {
+		model = ai.modelName
+	}
+	model = ResolveModel(model)
+	price, ok := ai.usage.prices[model]
+	if !ok {
+		return Usage{}, fmt.Errorf("no price for model %q", model)
+	}
+	genaiModel := newGenerativeModel(ai.genaiClient, model, GeminiModelOptions{})
+	type shape struct {
+		digits   int
+		negative bool
+	}
+	tokens := map[shape]int64{}
+	var estimate Usage
+	for _, n := range numbers {
+		digits := strconv.Itoa(n)
+		sh := shape{digits: len(strings.TrimPrefix(digits, "-")), negative: n < 0}
+		count, ok := tokens[sh]
 		if !ok {
-			return nil, fmt.Errorf("unexpected response part type: %T from Gemini API. Content: %+v", part, resp.Candidates[0].Content.Parts)
+			prompt, err := ai.Prompt("isEven", n)
+			if err != nil {
+				return Usage{}, err
+			}
+			resp, err := genaiModel.CountTokens(ctx, genai.Text(prompt))
+			if err != nil {
+				return Usage{}, fmt.Errorf("failed to count tokens with Gemini API: %w", err)
+			}
+			count = int64(resp.TotalTokens)
+			tokens[sh] = count
 		}
+		estimate.Requests++
+		estimate.PromptTokens += count
+		estimate.CompletionTokens++
+	}
+	estimate.CostUSD = price.Cost(estimate.PromptTokens, estimate.CompletionTokens)
+	return estimate, nil
+}
 
-		responseContent := strings.ToLower(strings.TrimSpace(string(textContent)))
+// Embed returns the embedding of text computed by the configured embedding
+// model, so that IsEvenAiGemini can serve as the Embedder of an EmbeddingCache.
+func (ai *IsEvenAiGemini) Embed(ctx context.Context, text string) ([]float32, error) {
+	resp, err := ai.embedModel.EmbedContent(ctx, genai.Text(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text with Gemini API: %w", err)
+	}
+	if resp.Embedding == nil {
+		return nil, errors.New("gemini API returned no embedding")
+	}
+	return resp.Embedding.Values, nil
+}
 
-		switch responseContent {
-		case "true":
-			b := true
-			return &b, nil
-		case "false":
-			b := false
-			return &b, nil
-		default:
-			return nil, nil
+// ListModels returns the names of the models available to the API key that
+// support content generation, without the "models/" prefix.
+func (ai *IsEvenAiGemini) ListModels(ctx context.Context) ([]string, error) {
+	var names []string
+	it := ai.genaiClient.ListModels(ctx)
+	for {
+		info, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Gemini models: %w", err)
+		}
+		if !slices.Contains(info.SupportedGenerationMethods, "generateContent") {
+			continue
 		}
+		names = append(names, strings.TrimPrefix(info.Name, "models/"))
 	}
+	return names, nil
+}
 
-	ai.IsEvenAiCore = NewIsEvenAiCore(DefaultGeminiPromptTemplates, queryFunc)
-	return ai, nil
+// Ping performs a minimal authenticated call (a lookup of the configured model)
+// so services can check connectivity and credentials, e.g. in readiness probes.
+// It returns ErrModelNotFound if the model does not exist.
+func (ai *IsEvenAiGemini) Ping(ctx context.Context) error {
+	if _, err := ai.genaiModel.Info(ctx); err != nil {
+		if isGeminiNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrModelNotFound, ai.modelName)
+		}
+		return fmt.Errorf("failed to reach Gemini API: %w", err)
+	}
+	return nil
+}
+
+// isGeminiNotFound reports whether err is a 404 response from the Gemini API.
+func isGeminiNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// isGeminiFallbackError reports whether err means the model is gone (404) or
+// out of capacity (429, 503), in which case another model may still be able to answer.
+func isGeminiFallbackError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusNotFound, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
 }
 
 // Close client connections if any were long-lived.
@@ -6,12 +6,64 @@
 package is_even_ai
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+	"github.com/philwo/is-even-ai/vcr"
 )
 
+// startFakeGeminiServer starts an httptest server that answers Gemini REST calls
+// with handler and returns its base URL. The server is closed when the test finishes.
+func startFakeGeminiServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// newFakeGemini returns an IsEvenAiGemini pointed at a fake server driven by handler.
+func newFakeGemini(t *testing.T, handler http.HandlerFunc, modelOpts ...GeminiModelOptions) *IsEvenAiGemini {
+	t.Helper()
+	baseURL := startFakeGeminiServer(t, handler)
+
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL}, modelOpts...)
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	t.Cleanup(func() { _ = ai.Close() })
+	return ai
+}
+
+// skipIfStreamingBroken skips tests that go through the SDK's REST stream reader
+// (chat sessions use it). That reader relies on encoding/json letting Token read
+// the closing ']' after a failed Decode, which GOEXPERIMENT=jsonv2 does not allow.
+func skipIfStreamingBroken(t *testing.T) {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(`[{}]`))
+	var raw json.RawMessage
+	_, _ = dec.Token()
+	_ = dec.Decode(&raw)
+	if err := dec.Decode(&raw); err != nil {
+		if tok, _ := dec.Token(); tok != json.Delim(']') {
+			t.Skip("Skipping: this toolchain's encoding/json breaks the Gemini SDK's stream reader")
+		}
+	}
+}
+
 // Helper function to check boolean pointer results for Gemini tests
 func checkGeminiResult(t *testing.T, val *bool, err error, expected bool, funcName string, inputs ...int) {
 	t.Helper()
@@ -28,13 +80,42 @@ func checkGeminiResult(t *testing.T, val *bool, err error, expected bool, funcNa
 	}
 }
 
-func TestIsEvenAiGemini_Integration(t *testing.T) {
+// integrationClientOptions returns client options for tests against the real
+// Gemini API. With GEMINI_API_KEY set, requests go to the API and, if
+// IS_EVEN_AI_RECORD is also set, are recorded to testdata/<cassette>.json.
+// Without a key, the recorded cassette is replayed, or the test is skipped if
+// there is none.
+func integrationClientOptions(t *testing.T, cassette string) GeminiClientOptions {
+	t.Helper()
+	path := filepath.Join("testdata", cassette+".json")
 	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		t.Skip("Skipping Gemini integration tests: GEMINI_API_KEY not set")
+	if apiKey != "" {
+		if os.Getenv("IS_EVEN_AI_RECORD") == "" {
+			return GeminiClientOptions{APIKey: apiKey}
+		}
+		rec, err := vcr.New(path, vcr.ModeRecord)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := rec.Save(); err != nil {
+				t.Error(err)
+			}
+		})
+		return GeminiClientOptions{APIKey: apiKey, HTTPTransport: rec}
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("Skipping Gemini integration tests: GEMINI_API_KEY not set and no cassette at %s", path)
+	}
+	rep, err := vcr.New(path, vcr.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
 	}
+	return GeminiClientOptions{APIKey: "replayed-api-key", HTTPTransport: rep}
+}
 
-	clientOpts := GeminiClientOptions{APIKey: apiKey}
+func TestIsEvenAiGemini_Integration(t *testing.T) {
+	clientOpts := integrationClientOptions(t, "integration")
 	ai, err := NewIsEvenAiGemini(clientOpts)
 	if err != nil {
 		t.Fatalf("Failed to create NewIsEvenAiGemini: %v", err)
@@ -144,6 +225,48 @@ func TestNewIsEvenAiGemini_Options(t *testing.T) {
 	})
 }
 
+func TestIsEvenAiGemini_DeterministicMode(t *testing.T) {
+	var generationConfig map[string]any
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			GenerationConfig map[string]any `json:"generationConfig"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		generationConfig = req.GenerationConfig
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{DeterministicMode: true})
+
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if !ai.Deterministic() {
+		t.Error("Deterministic() = false, want true")
+	}
+	if generationConfig["topK"] != 1.0 || generationConfig["candidateCount"] != 1.0 {
+		t.Errorf("Expected greedy decoding, got generation config %v", generationConfig)
+	}
+	if temp, ok := generationConfig["temperature"]; ok && temp != 0.0 {
+		t.Errorf("Expected temperature 0, got %v", temp)
+	}
+	batch, err := json.Marshal(ai.batchGenerationConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"temperature":0,"topK":1,"candidateCount":1,"seed":0}`; string(batch) != want {
+		t.Errorf("Batch generation config = %s, want %s", batch, want)
+	}
+
+	hot := float32(0.7)
+	for name, opts := range map[string]GeminiModelOptions{
+		"Temperature":    {DeterministicMode: true, Temperature: &hot},
+		"SessionMode":    {DeterministicMode: true, SessionMode: true},
+		"FallbackModels": {DeterministicMode: true, FallbackModels: []string{"other"}},
+	} {
+		if _, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: "http://localhost:1"}, opts); err == nil {
+			t.Errorf("NewIsEvenAiGemini() in DeterministicMode with %s succeeded, want an error", name)
+		}
+	}
+}
+
 func TestIsEvenAiGemini_APIFailure(t *testing.T) {
 	clientOpts := GeminiClientOptions{APIKey: "invalid-gemini-api-key-for-test"}
 	ai, err := NewIsEvenAiGemini(clientOpts)
@@ -164,3 +287,647 @@ func TestIsEvenAiGemini_APIFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestIsEvenAiGemini_CountTokens(t *testing.T) {
+	var gotPath, gotBody string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"totalTokens":27}`)
+	})
+
+	n, err := ai.CountTokens(context.Background(), "Is 2 an even number?")
+	if err != nil {
+		t.Fatalf("CountTokens returned error: %v", err)
+	}
+	if n != 27 {
+		t.Errorf("CountTokens = %d; want 27", n)
+	}
+	if !strings.HasSuffix(gotPath, "/models/gemini-2.0-flash-lite:countTokens") {
+		t.Errorf("CountTokens called wrong endpoint: %s", gotPath)
+	}
+	if !strings.Contains(gotBody, "Is 2 an even number?") || !strings.Contains(gotBody, geminiSystemPrompt) {
+		t.Errorf("CountTokens request should include prompt and system instruction, got: %s", gotBody)
+	}
+}
+
+func TestIsEvenAiGemini_Embed(t *testing.T) {
+	var gotPath string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"embedding":{"values":[0.5,-1,2]}}`)
+	}, GeminiModelOptions{EmbeddingModel: "my-embedder"})
+
+	v, err := ai.Embed(context.Background(), "Is 2 an even number?")
+	if err != nil {
+		t.Fatalf("Embed returned error: %v", err)
+	}
+	if !slices.Equal(v, []float32{0.5, -1, 2}) {
+		t.Errorf("Embed = %v; want [0.5 -1 2]", v)
+	}
+	if !strings.HasSuffix(gotPath, "/models/my-embedder:embedContent") {
+		t.Errorf("Embed called wrong endpoint: %s", gotPath)
+	}
+}
+
+func TestIsEvenAiGemini_ListModels(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models") {
+			t.Errorf("ListModels called wrong endpoint: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"models":[
+			{"name":"models/gemini-2.0-flash-lite","supportedGenerationMethods":["generateContent","countTokens"]},
+			{"name":"models/text-embedding-004","supportedGenerationMethods":["embedContent"]}]}`)
+	})
+
+	models, err := ai.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels returned error: %v", err)
+	}
+	if len(models) != 1 || models[0] != "gemini-2.0-flash-lite" {
+		t.Errorf("ListModels = %v; want [gemini-2.0-flash-lite]", models)
+	}
+}
+
+func TestNewIsEvenAiGemini_ValidateModel(t *testing.T) {
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/models/gemini-2.0-flash-lite") {
+			_, _ = io.WriteString(w, `{"name":"models/gemini-2.0-flash-lite"}`)
+			return
+		}
+		geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+	})
+	clientOpts := GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL, ValidateModel: true}
+
+	t.Run("KnownModel", func(t *testing.T) {
+		ai, err := NewIsEvenAiGemini(clientOpts)
+		if err != nil {
+			t.Fatalf("NewIsEvenAiGemini with known model failed: %v", err)
+		}
+		_ = ai.Close()
+	})
+
+	t.Run("UnknownModel", func(t *testing.T) {
+		_, err := NewIsEvenAiGemini(clientOpts, GeminiModelOptions{Model: "gemini-0.1-retired"})
+		if !errors.Is(err, ErrModelNotFound) {
+			t.Errorf("Expected ErrModelNotFound, got %v", err)
+		}
+	})
+}
+
+func TestIsEvenAiGemini_Ping(t *testing.T) {
+	t.Run("Healthy", func(t *testing.T) {
+		ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"name":"models/gemini-2.0-flash-lite"}`)
+		})
+		if err := ai.Ping(context.Background()); err != nil {
+			t.Errorf("Ping returned error: %v", err)
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+			geminitest.WriteError(w, http.StatusForbidden, "PERMISSION_DENIED", "API key not valid")
+		})
+		err := ai.Ping(context.Background())
+		if err == nil {
+			t.Fatal("Expected error from Ping with rejected API key, got nil")
+		}
+		if errors.Is(err, ErrModelNotFound) {
+			t.Errorf("Ping with rejected API key should not report ErrModelNotFound: %v", err)
+		}
+	})
+}
+
+func TestIsEvenAiGemini_SessionMode(t *testing.T) {
+	skipIfStreamingBroken(t)
+	var bodies []string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{SessionMode: true, SessionMaxTurns: 1})
+
+	for _, n := range []int{2, 4, 6} {
+		res, err := ai.IsEven(n)
+		checkGeminiResult(t, res, err, true, "IsEven", n)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[1], "Is 2 an even number?") {
+		t.Errorf("Second request should carry the first turn, got: %s", bodies[1])
+	}
+	if strings.Contains(bodies[2], "Is 2 an even number?") || !strings.Contains(bodies[2], "Is 4 an even number?") {
+		t.Errorf("Third request should only carry the most recent turn, got: %s", bodies[2])
+	}
+
+	ai.ResetSession()
+	_, _ = ai.IsEven(8)
+	if strings.Contains(bodies[3], "Is 6 an even number?") {
+		t.Errorf("Request after ResetSession should not carry old turns, got: %s", bodies[3])
+	}
+}
+
+func TestIsEvenAiGemini_OnToken(t *testing.T) {
+	skipIfStreamingBroken(t)
+	var paths []string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `[{"candidates":[{"content":{"role":"model","parts":[{"text":"Seven is odd, so: "}]}}]},`+
+			`{"candidates":[{"content":{"role":"model","parts":[{"text":"false"}]}}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":6}}]`)
+	})
+
+	var chunks []string
+	ctx, md := WithCallMetadata(WithOnToken(context.Background(), func(chunk string) { chunks = append(chunks, chunk) }))
+	res, err := ai.WithContext(ctx).IsEven(7)
+	if err != nil || res != nil {
+		// The merged text is not a bare answer, so it is undefined.
+		t.Fatalf("IsEven(7) = %v, %v; want undefined", res, err)
+	}
+	if !slices.Equal(chunks, []string{"Seven is odd, so: ", "false"}) {
+		t.Errorf("Streamed chunks = %q", chunks)
+	}
+	if len(paths) != 1 || !strings.HasSuffix(paths[0], ":streamGenerateContent") {
+		t.Errorf("Requests = %v, want one streamGenerateContent", paths)
+	}
+	if md.PromptTokens != 10 || md.CompletionTokens != 6 {
+		t.Errorf("Usage of streamed answer = %+v", md.Usage)
+	}
+}
+
+func TestIsEvenAiGemini_FallbackModels(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch {
+		case strings.Contains(r.URL.Path, "/models/retired-model:"):
+			geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+		case strings.Contains(r.URL.Path, "/models/busy-model:"):
+			geminitest.WriteError(w, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "Resource has been exhausted")
+		case strings.Contains(r.URL.Path, "/models/strict-model:"):
+			geminitest.WriteError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "bad request")
+		default:
+			geminitest.WriteAnswer(w, r, "true")
+		}
+	}
+
+	t.Run("FallsBackOnNotFoundAndOverloaded", func(t *testing.T) {
+		paths = nil
+		ai := newFakeGemini(t, handler, GeminiModelOptions{
+			Model:          "retired-model",
+			FallbackModels: []string{"busy-model", "healthy-model"},
+		})
+		ctx, md := WithCallMetadata(context.Background())
+		res, err := ai.WithContext(ctx).IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+		if len(paths) != 3 || !strings.Contains(paths[2], "/models/healthy-model:") {
+			t.Errorf("Expected primary, busy and healthy model to be tried in order, got %v", paths)
+		}
+		if md.Model != "healthy-model" || md.Requests != 1 {
+			t.Errorf("Expected metadata to report one request to the fallback model, got %+v", *md)
+		}
+	})
+
+	t.Run("OtherErrorsDoNotFallBack", func(t *testing.T) {
+		paths = nil
+		ai := newFakeGemini(t, handler, GeminiModelOptions{
+			Model:          "strict-model",
+			FallbackModels: []string{"healthy-model"},
+		})
+		if _, err := ai.IsEven(2); err == nil {
+			t.Error("Expected error from IsEven with a 400 response, got nil")
+		}
+		if len(paths) != 1 {
+			t.Errorf("Expected no fallback for a 400 response, got requests %v", paths)
+		}
+	})
+}
+
+func TestIsEvenAiGemini_VerifyAnswers(t *testing.T) {
+	skipIfStreamingBroken(t)
+
+	var flips []string
+	var verifyBody string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), geminiVerifyPrompt) {
+			geminitest.WriteAnswer(w, r, "false") // Careless first answer
+			return
+		}
+		verifyBody = string(body)
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{
+		VerifyAnswers: true,
+		OnVerificationFlip: func(prompt string, first bool, verified *bool) {
+			flips = append(flips, fmt.Sprintf("%s %t->%v", prompt, first, *verified))
+		},
+	})
+
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if !strings.Contains(verifyBody, "Is 2 an even number?") || !strings.Contains(verifyBody, `"false"`) {
+		t.Errorf("Verification request should include the question and first answer, got: %s", verifyBody)
+	}
+	if len(flips) != 1 || flips[0] != "Is 2 an even number? false->true" {
+		t.Errorf("Expected one reported flip, got %v", flips)
+	}
+}
+
+func TestIsEvenAiGemini_VerifyAnswersWithFallback(t *testing.T) {
+	skipIfStreamingBroken(t)
+
+	var verifyPath string
+	verifyStatus := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(r.URL.Path, "/models/retired-model:"):
+			geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+		case !strings.Contains(string(body), geminiVerifyPrompt):
+			geminitest.WriteAnswer(w, r, "true")
+		case verifyStatus != 0:
+			geminitest.WriteError(w, verifyStatus, "RESOURCE_EXHAUSTED", "Resource has been exhausted")
+		default:
+			verifyPath = r.URL.Path
+			geminitest.WriteAnswer(w, r, "true")
+		}
+	}, GeminiModelOptions{
+		Model:          "retired-model",
+		FallbackModels: []string{"healthy-model"},
+		VerifyAnswers:  true,
+		PriceTable:     map[string]ModelPrice{"healthy-model": {InputPerMillion: 1, OutputPerMillion: 10}},
+	})
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if !strings.Contains(verifyPath, "/models/healthy-model:") {
+		t.Errorf("Expected the fallback model that answered to verify, got request to %q", verifyPath)
+	}
+	if want := 2 * (20*1 + 1*10) / 1e6; md.Model != "healthy-model" || md.Requests != 2 || math.Abs(md.CostUSD-want) > 1e-12 {
+		t.Errorf("Expected both turns to be charged to the fallback model ($%g), got %+v", want, *md)
+	}
+
+	verifyStatus = http.StatusTooManyRequests
+	_, err = ai.IsEven(4)
+	var perr *ProviderError
+	if !errors.As(err, &perr) || perr.Model != "healthy-model" || !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected a rate limited ProviderError of the fallback model, got %v", err)
+	}
+}
+
+func TestIsEvenAiGemini_DoubleCheck(t *testing.T) {
+	skipIfStreamingBroken(t)
+
+	var flips int
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case !strings.Contains(string(body), geminiDoubleCheckPrompt):
+			geminitest.WriteAnswer(w, r, "true")
+		case strings.Contains(string(body), "Is 3 an even number?"):
+			geminitest.WriteAnswer(w, r, "false") // Second thoughts
+		default:
+			geminitest.WriteAnswer(w, r, "true")
+		}
+	}, GeminiModelOptions{
+		DoubleCheck:        true,
+		OnVerificationFlip: func(string, bool, *bool) { flips++ },
+	})
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if md.Requests != 2 {
+		t.Errorf("Expected the answer and the double check to be counted, got %d requests", md.Requests)
+	}
+	if res, err := ai.IsEven(3); err != nil || res != nil {
+		t.Errorf("IsEven(3) with disagreeing turns = %v, %v; want undefined", res, err)
+	}
+	if flips != 1 {
+		t.Errorf("Expected one reported flip, got %d", flips)
+	}
+}
+
+func TestIsEvenAiGemini_Usage(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{
+		Model:      "priced-model",
+		PriceTable: map[string]ModelPrice{"priced-model": {InputPerMillion: 1, OutputPerMillion: 10}},
+	})
+
+	if u := ai.Usage(); u != (Usage{}) {
+		t.Errorf("Expected zero usage before any request, got %+v", u)
+	}
+	for _, n := range []int{2, 4} {
+		res, err := ai.IsEven(n)
+		checkGeminiResult(t, res, err, true, "IsEven", n)
+	}
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(6)
+	checkGeminiResult(t, res, err, true, "IsEven", 6)
+	if want := (20*1 + 1*10) / 1e6; md.Model != "priced-model" || md.TotalTokens() != 21 || math.Abs(md.CostUSD-want) > 1e-12 {
+		t.Errorf("Unexpected call metadata: %+v", *md)
+	}
+
+	u := ai.Usage()
+	if u.Requests != 3 || u.PromptTokens != 60 || u.CompletionTokens != 3 || u.TotalTokens() != 63 {
+		t.Errorf("Unexpected token usage: %+v", u)
+	}
+	if want := (60*1 + 3*10) / 1e6; math.Abs(u.CostUSD-want) > 1e-12 {
+		t.Errorf("Expected cost %g, got %g", want, u.CostUSD)
+	}
+
+	res, err = ai.WithContext(WithLabels(context.Background(), Labels{"team": "a"})).AreEqual(1, 1)
+	checkGeminiResult(t, res, err, true, "AreEqual", 1, 1)
+	if byLabel := ai.UsageByLabel("team"); len(byLabel) != 1 || byLabel["a"].Requests != 1 {
+		t.Errorf("Unexpected usage by team: %+v", byLabel)
+	}
+	byPred := ai.UsageByPredicate()
+	if len(byPred) != 2 || byPred["isEven"] != u || byPred["areEqual"].Requests != 1 {
+		t.Errorf("Unexpected usage by predicate: %+v", byPred)
+	}
+}
+
+func TestIsEvenAiGemini_Budget(t *testing.T) {
+	requests := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{Budget: Budget{MaxTokens: 40}})
+
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if s := ai.BudgetStatus(); s.RemainingTokens == nil || *s.RemainingTokens != 19 {
+		t.Errorf("Expected 19 of 40 tokens to remain after one request, got %+v", s)
+	}
+	res, err = ai.IsEven(4)
+	checkGeminiResult(t, res, err, true, "IsEven", 4)
+	if _, err := ai.IsEven(6); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded once 42 of 40 tokens are used, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected no request after the budget was exceeded, got %d requests", requests)
+	}
+}
+
+func TestIsEvenAiGemini_BudgetRequiresPrices(t *testing.T) {
+	budget := Budget{MaxCostUSD: 1}
+	priced := map[string]ModelPrice{"priced-model": {InputPerMillion: 1, OutputPerMillion: 1}}
+	for name, tc := range map[string]struct {
+		opts    GeminiModelOptions
+		wantErr bool
+	}{
+		"DefaultModel":     {GeminiModelOptions{Budget: budget}, false},
+		"PricedModel":      {GeminiModelOptions{Model: "priced-model", PriceTable: priced, Budget: budget}, false},
+		"UnpricedModel":    {GeminiModelOptions{Model: "custom-model", Budget: budget}, true},
+		"UnpricedFallback": {GeminiModelOptions{Model: "priced-model", FallbackModels: []string{"custom-model"}, PriceTable: priced, Budget: budget}, true},
+		"TokenBudgetOnly":  {GeminiModelOptions{Model: "custom-model", Budget: Budget{MaxTokens: 100}}, false},
+	} {
+		ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: "http://localhost:1"}, tc.opts)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("NewIsEvenAiGemini() with %s = %v, want error: %t", name, err, tc.wantErr)
+		}
+		if err == nil {
+			_ = ai.Close()
+		}
+	}
+}
+
+func TestIsEvenAiGemini_EstimateCost(t *testing.T) {
+	var paths []string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"totalTokens":19}`)
+	}, GeminiModelOptions{
+		PriceTable: map[string]ModelPrice{"gemini-1.5-pro": {InputPerMillion: 1, OutputPerMillion: 10}},
+	})
+
+	est, err := ai.EstimateCost(context.Background(), []int{1, 2, 3, 10, 99, -4, -7}, "gemini-1.5-pro")
+	if err != nil {
+		t.Fatalf("EstimateCost returned error: %v", err)
+	}
+	want := Usage{Requests: 7, PromptTokens: 133, CompletionTokens: 7, CostUSD: (133*1 + 7*10) / 1e6}
+	if est.Requests != want.Requests || est.PromptTokens != want.PromptTokens || est.CompletionTokens != want.CompletionTokens || math.Abs(est.CostUSD-want.CostUSD) > 1e-12 {
+		t.Errorf("EstimateCost = %+v; want %+v", est, want)
+	}
+	if len(paths) != 3 || !strings.HasSuffix(paths[0], "/models/gemini-1.5-pro:countTokens") {
+		t.Errorf("Expected one countTokens call per prompt shape on the requested model, got %v", paths)
+	}
+	if u := ai.Usage(); u != (Usage{}) {
+		t.Errorf("Estimating should not count as usage, got %+v", u)
+	}
+	if est, err := ai.EstimateCost(context.Background(), []int{1}, "custom-model"); err == nil {
+		t.Errorf("EstimateCost for a model without a price = %+v, want an error", est)
+	}
+	if len(paths) != 3 {
+		t.Errorf("Expected no countTokens call for a model without a price, got %v", paths)
+	}
+}
+
+func TestIsEvenAiGemini_Logger(t *testing.T) {
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/models/busy-model:") {
+			geminitest.WriteError(w, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "Resource has been exhausted")
+			return
+		}
+		geminitest.WriteAnswer(w, r, "maybe")
+	})
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ai, err := NewIsEvenAiGemini(
+		GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL, Logger: logger},
+		GeminiModelOptions{Model: "busy-model", FallbackModels: []string{"healthy-model"}},
+	)
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	defer ai.Close()
+
+	if res, err := ai.IsEven(2); err != nil || res != nil {
+		t.Fatalf("Expected undefined answer, got %v, %v", res, err)
+	}
+	for _, want := range []string{
+		`msg="gemini request started" model=busy-model`,
+		`msg="gemini model unavailable, falling back" model=busy-model fallback=healthy-model`,
+		`msg="gemini answer not understood" model=healthy-model text=maybe`,
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("Expected log line containing %q, got:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestIsEvenAiGemini_Debug(t *testing.T) {
+	const apiKey = "secret-api-key"
+	var gotKey string
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-goog-api-key")
+		geminitest.WriteError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "API key "+apiKey+" is not valid")
+	})
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: apiKey, BaseURL: baseURL, Logger: logger, Debug: true})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	defer ai.Close()
+
+	if _, err := ai.IsEven(2); err == nil {
+		t.Fatal("Expected error from IsEven with a 400 response, got nil")
+	}
+	if gotKey != apiKey {
+		t.Errorf("Expected the API key to be sent in debug mode, got %q", gotKey)
+	}
+	out := logs.String()
+	if strings.Contains(out, apiKey) {
+		t.Errorf("Debug log leaks the API key:\n%s", out)
+	}
+	for _, want := range []string{"Is 2 an even number?", "status=400", "API key " + redacted + " is not valid"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected debug log to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestIsEvenAiGemini_AuditLog(t *testing.T) {
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "True\n")
+	})
+	var buf bytes.Buffer
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL, AuditLog: NewAuditLog(&buf)})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	defer ai.Close()
+
+	res, err := ai.WithContext(WithLabels(context.Background(), Labels{"team": "a"})).IsGreaterThan(8, 7)
+	checkGeminiResult(t, res, err, true, "IsGreaterThan", 8, 7)
+
+	var rec AuditRecord
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("Audit log is not a single JSON record: %v\n%s", err, buf.String())
+	}
+	if rec.Predicate != "isGreaterThan" || len(rec.Args) != 2 || rec.Args[0] != 8 || rec.Args[1] != 7 {
+		t.Errorf("Unexpected predicate or args in audit record: %+v", rec)
+	}
+	if rec.Prompt != "Is 8 greater than 7?" || rec.Model != "gemini-2.0-flash-lite" || rec.RawAnswer != "True\n" || rec.TemplateVersion != "1" {
+		t.Errorf("Unexpected prompt, model or raw answer in audit record: %+v", rec)
+	}
+	if rec.Result == nil || !*rec.Result || rec.Error != "" || rec.Time.IsZero() {
+		t.Errorf("Unexpected result in audit record: %+v", rec)
+	}
+	if rec.Labels["team"] != "a" {
+		t.Errorf("Unexpected labels in audit record: %+v", rec.Labels)
+	}
+}
+
+func TestIsEvenAiGemini_HTTPTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "true")
+	}))
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := vcr.New(cassette, vcr.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: srv.URL, HTTPTransport: rec})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	_ = ai.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	rep, err := vcr.New(cassette, vcr.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai, err = NewIsEvenAiGemini(GeminiClientOptions{APIKey: "other-api-key", BaseURL: srv.URL, HTTPTransport: rep})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini with replay transport failed: %v", err)
+	}
+	defer ai.Close()
+	res, err = ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven (replayed)", 2)
+}
+
+func TestNewIsEvenAiGemini_Endpoints(t *testing.T) {
+	var paths []string
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		geminitest.WriteAnswer(w, r, "true")
+	})
+
+	t.Run("BaseURLWithPrefix", func(t *testing.T) {
+		paths = nil
+		ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL + "/gateway/gemini/"})
+		if err != nil {
+			t.Fatalf("NewIsEvenAiGemini failed: %v", err)
+		}
+		defer ai.Close()
+		res, err := ai.IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+		if len(paths) != 1 || !strings.HasPrefix(paths[0], "/gateway/gemini/v1beta/models/gemini-2.0-flash-lite:generateContent") {
+			t.Errorf("Requests went to %q, want the API path under /gateway/gemini", paths)
+		}
+	})
+
+	t.Run("FullEndpointURL", func(t *testing.T) {
+		paths = nil
+		ai, err := NewIsEvenAiGemini(GeminiClientOptions{
+			APIKey:          "fake-api-key",
+			BaseURL:         baseURL,
+			FullEndpointURL: baseURL + "/custom/generate?deployment=parity",
+		})
+		if err != nil {
+			t.Fatalf("NewIsEvenAiGemini failed: %v", err)
+		}
+		defer ai.Close()
+		res, err := ai.IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+		if want := []string{"/custom/generate?deployment=parity"}; !slices.Equal(paths, want) {
+			t.Errorf("Requests went to %q, want %q", paths, want)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, opts := range []GeminiClientOptions{
+			{APIKey: "fake-api-key", BaseURL: "gateway/gemini"},
+			{APIKey: "fake-api-key", FullEndpointURL: "://bad"},
+		} {
+			if _, err := NewIsEvenAiGemini(opts); err == nil {
+				t.Errorf("NewIsEvenAiGemini(%+v) succeeded, want an error", opts)
+			}
+		}
+	})
+}
+
+func TestGeminiClientOptions_Log(t *testing.T) {
+	opts := GeminiClientOptions{APIKey: "secret-key", FullEndpointURL: "https://gateway.example.com/generate?key=secret-key"}
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("client", "opts", opts)
+	for _, s := range []string{fmt.Sprint(opts), fmt.Sprintf("%+v", opts), buf.String()} {
+		if strings.Contains(s, "secret-key") {
+			t.Errorf("Expected the API key to be redacted, got %s", s)
+		}
+		if !strings.Contains(s, "https://gateway.example.com/generate") {
+			t.Errorf("Expected the endpoint in %s", s)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package geminitest provides an in-process fake of the Gemini REST API, so
+// code using is_even_ai.IsEvenAiGemini can be tested end-to-end offline. Point
+// GeminiClientOptions.BaseURL at Server.URL.
+package geminitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Usage reported by WriteAnswer for every answer.
+const (
+	PromptTokens     = 20
+	CompletionTokens = 1
+)
+
+// Responder returns the text the fake model answers to prompt.
+type Responder func(prompt string) string
+
+// Always returns a Responder that always answers text.
+func Always(text string) Responder {
+	return func(string) string { return text }
+}
+
+// Request is a request received by a Server.
+type Request struct {
+	Model  string // Model name without the "models/" prefix
+	Method string // API method, e.g. "generateContent" or "streamGenerateContent"; "batches.get" for batch status
+	Prompt string // Text of the last message, if any
+	Body   string
+}
+
+// Server is a fake Gemini API serving generateContent, streamGenerateContent
+// (used by chat sessions), countTokens, model lookups and batch jobs. Answers
+// come from its Responder; batch jobs succeed as soon as they are submitted.
+// Server is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	respond  Responder
+	requests []Request
+	failures []failure
+	batches  map[string][]byte // Finished operations by batch name
+}
+
+type failure struct {
+	code           int
+	status, detail string
+}
+
+// NewServer starts a Server answering with respond. Call Close when done.
+func NewServer(respond Responder) *Server {
+	s := &Server{respond: respond}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// FailNext makes the next request fail with the given HTTP status code, e.g.
+// FailNext(http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "quota exceeded").
+// Failures queue up if FailNext is called repeatedly.
+func (s *Server) FailNext(code int, status, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{code, status, message})
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	_, path, _ := strings.Cut(r.URL.Path, "/models/")
+	model, method, _ := strings.Cut(path, ":")
+	req := Request{Model: model, Method: method, Prompt: lastPrompt(body), Body: string(body)}
+	_, batch, isBatch := strings.Cut(r.URL.Path, "/batches/")
+	if isBatch {
+		req.Method = "batches.get"
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var fail *failure
+	if len(s.failures) > 0 {
+		fail = &s.failures[0]
+		s.failures = s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	switch {
+	case fail != nil:
+		WriteError(w, fail.code, fail.status, fail.detail)
+	case method == "generateContent" || method == "streamGenerateContent":
+		WriteAnswer(w, r, s.respond(req.Prompt))
+	case method == "batchGenerateContent":
+		s.createBatch(w, model, body)
+	case isBatch && r.Method == http.MethodGet:
+		s.mu.Lock()
+		op, ok := s.batches["batches/"+batch]
+		s.mu.Unlock()
+		if !ok {
+			WriteError(w, http.StatusNotFound, "NOT_FOUND", "geminitest: no batch "+batch)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(op)
+	case method == "countTokens":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"totalTokens":%d}`, PromptTokens)
+	case method == "" && r.Method == http.MethodGet && model != "":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"name":"models/%s","supportedGenerationMethods":["generateContent","countTokens"]}`, model)
+	default:
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "geminitest: unsupported request "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// createBatch answers the requests of a batchGenerateContent call right away
+// and responds with the pending operation. Later lookups of the batch report
+// it as succeeded.
+func (s *Server) createBatch(w http.ResponseWriter, model string, body []byte) {
+	var in struct {
+		Batch struct {
+			InputConfig struct {
+				Requests struct {
+					Requests []struct {
+						Request  json.RawMessage `json:"request"`
+						Metadata json.RawMessage `json:"metadata"`
+					} `json:"requests"`
+				} `json:"requests"`
+			} `json:"inputConfig"`
+		} `json:"batch"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+	var responses []map[string]any
+	for _, r := range in.Batch.InputConfig.Requests.Requests {
+		text, _ := json.Marshal(s.respond(lastPrompt(r.Request)))
+		resp := fmt.Sprintf(`{"candidates":[{"content":{"role":"model","parts":[{"text":%s}]}}],"usageMetadata":{"promptTokenCount":%d,"candidatesTokenCount":%d}}`,
+			text, PromptTokens, CompletionTokens)
+		responses = append(responses, map[string]any{"response": json.RawMessage(resp), "metadata": r.Metadata})
+	}
+
+	s.mu.Lock()
+	if s.batches == nil {
+		s.batches = make(map[string][]byte)
+	}
+	name := fmt.Sprintf("batches/%d", len(s.batches)+1)
+	s.batches[name], _ = json.Marshal(map[string]any{
+		"name":     name,
+		"done":     true,
+		"metadata": map[string]any{"model": "models/" + model, "state": "BATCH_STATE_SUCCEEDED"},
+		"response": map[string]any{"inlinedResponses": map[string]any{"inlinedResponses": responses}},
+	})
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"name":%q,"metadata":{"model":"models/%s","state":"BATCH_STATE_PENDING"}}`, name, model)
+}
+
+// lastPrompt extracts the text of the last message in a generateContent or countTokens request.
+func lastPrompt(body []byte) string {
+	var req struct {
+		Contents []struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"contents"`
+	}
+	if json.Unmarshal(body, &req) != nil || len(req.Contents) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range req.Contents[len(req.Contents)-1].Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// WriteAnswer writes a generateContent response whose only part is text, for
+// use in custom handlers. Streaming requests get the same response as the only
+// element of a JSON array stream.
+func WriteAnswer(w http.ResponseWriter, r *http.Request, text string) {
+	candidate, _ := json.Marshal(text)
+	resp := fmt.Sprintf(`{"candidates":[{"content":{"role":"model","parts":[{"text":%s}]}}],"usageMetadata":{"promptTokenCount":%d,"candidatesTokenCount":%d,"totalTokenCount":%d}}`,
+		candidate, PromptTokens, CompletionTokens, PromptTokens+CompletionTokens)
+	if strings.HasSuffix(r.URL.Path, ":streamGenerateContent") {
+		resp = "[" + resp + "]"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, resp)
+}
+
+// WriteError writes an API error response, e.g.
+// WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found").
+func WriteError(w http.ResponseWriter, code int, status, message string) {
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]any{"code": code, "message": message, "status": status},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package geminitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func newClient(t *testing.T, srv *geminitest.Server, modelOpts ...is_even_ai.GeminiModelOptions) *is_even_ai.IsEvenAiGemini {
+	t.Helper()
+	ai, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{APIKey: "fake-api-key", BaseURL: srv.URL}, modelOpts...)
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	t.Cleanup(func() { _ = ai.Close() })
+	return ai
+}
+
+// parity answers the default IsEven prompts correctly.
+func parity(prompt string) string {
+	var n int
+	if _, err := fmt.Sscanf(prompt, "Is %d an even number?", &n); err != nil {
+		return "I don't know"
+	}
+	return fmt.Sprint(n%2 == 0)
+}
+
+func TestServer(t *testing.T) {
+	srv := geminitest.NewServer(parity)
+	defer srv.Close()
+	ai := newClient(t, srv)
+
+	for _, n := range []int{2, 3} {
+		res, err := ai.IsEven(n)
+		if err != nil || res == nil || *res != (n%2 == 0) {
+			t.Errorf("IsEven(%d) = %v, %v", n, res, err)
+		}
+	}
+	if res, err := ai.AreEqual(1, 1); err != nil || res != nil {
+		t.Errorf("AreEqual(1, 1) = %v, %v; want undefined", res, err)
+	}
+	if err := ai.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() = %v", err)
+	}
+	if _, err := ai.CountTokens(context.Background(), "Is 2 an even number?"); err != nil {
+		t.Errorf("CountTokens() = %v", err)
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 5 {
+		t.Fatalf("Expected 5 requests, got %+v", reqs)
+	}
+	if reqs[0].Model != "gemini-2.0-flash-lite" || reqs[0].Method != "generateContent" || reqs[0].Prompt != "Is 2 an even number?" {
+		t.Errorf("Unexpected first request: %+v", reqs[0])
+	}
+	if u := ai.Usage(); u.PromptTokens != 3*geminitest.PromptTokens || u.CompletionTokens != 3*geminitest.CompletionTokens {
+		t.Errorf("Unexpected usage %+v", u)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	srv := geminitest.NewServer(geminitest.Always("true"))
+	defer srv.Close()
+	ai := newClient(t, srv, is_even_ai.GeminiModelOptions{FallbackModels: []string{"backup-model"}})
+
+	srv.FailNext(http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "quota exceeded")
+	res, err := ai.IsEven(2)
+	if err != nil || res == nil || !*res {
+		t.Fatalf("IsEven(2) = %v, %v; want true from the fallback model", res, err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 2 || reqs[1].Model != "backup-model" {
+		t.Errorf("Expected a retry on the fallback model, got %+v", reqs)
+	}
+
+	srv.FailNext(http.StatusBadRequest, "INVALID_ARGUMENT", "bad request")
+	if _, err := ai.IsEven(2); err == nil || !strings.Contains(err.Error(), "bad request") {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+}
+
+func TestServer_Streaming(t *testing.T) {
+	skipIfStreamingBroken(t)
+	srv := geminitest.NewServer(parity)
+	defer srv.Close()
+	ai := newClient(t, srv, is_even_ai.GeminiModelOptions{SessionMode: true})
+
+	for _, n := range []int{4, 5} {
+		res, err := ai.IsEven(n)
+		if err != nil || res == nil || *res != (n%2 == 0) {
+			t.Errorf("IsEven(%d) = %v, %v", n, res, err)
+		}
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 2 || reqs[1].Method != "streamGenerateContent" || reqs[1].Prompt != "Is 5 an even number?" {
+		t.Errorf("Unexpected requests in session mode: %+v", reqs)
+	}
+}
+
+// skipIfStreamingBroken skips tests that go through the SDK's REST stream reader.
+// That reader relies on encoding/json letting Token read the closing ']' after
+// a failed Decode, which GOEXPERIMENT=jsonv2 does not allow.
+func skipIfStreamingBroken(t *testing.T) {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(`[{}]`))
+	var raw json.RawMessage
+	_, _ = dec.Token()
+	_ = dec.Decode(&raw)
+	if err := dec.Decode(&raw); err != nil {
+		if tok, _ := dec.Token(); tok != json.Delim(']') {
+			t.Skip("Skipping: this toolchain's encoding/json breaks the Gemini SDK's stream reader")
+		}
+	}
+}
+
+func TestServer_BatchJob(t *testing.T) {
+	srv := geminitest.NewServer(parity)
+	defer srv.Close()
+	ai := newClient(t, srv)
+
+	ctx := context.Background()
+	job, err := ai.SubmitBatchJob(ctx, []int{4, 7})
+	if err != nil {
+		t.Fatalf("SubmitBatchJob() = %v", err)
+	}
+	results, err := job.Wait(ctx, 0)
+	if err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+	for i, n := range []int{4, 7} {
+		if res := results[i]; res.N != n || res.Err != nil || res.Result == nil || *res.Result != (n%2 == 0) {
+			t.Errorf("results[%d] = %+v", i, res)
+		}
+	}
+	if reqs := srv.Requests(); len(reqs) != 2 || reqs[0].Method != "batchGenerateContent" || reqs[1].Method != "batches.get" {
+		t.Errorf("Unexpected requests %+v", reqs)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// defaultLocalRange is the range of IsEvenAiLocal's numbers if none is given.
+var defaultLocalRange = NumberRange{Min: -1000, Max: 1000}
+
+// NumberRange is an inclusive range of whole numbers.
+type NumberRange struct {
+	Min, Max int
+}
+
+// Contains reports whether n is within r.
+func (r NumberRange) Contains(n int) bool {
+	return r.Min <= n && n <= r.Max
+}
+
+// NumberGenerator produces numbers of a given parity, e.g. as fixtures for
+// property-based tests. Both methods take an optional range.
+type NumberGenerator interface {
+	GenerateEven(ctx context.Context, within ...NumberRange) (int, error)
+	GenerateOdd(ctx context.Context, within ...NumberRange) (int, error)
+}
+
+var (
+	_ NumberGenerator = (*IsEvenAiGemini)(nil)
+	_ NumberGenerator = (*IsEvenAiLocal)(nil)
+)
+
+// numberRange returns the optional range of a generator call, and whether
+// there is one.
+func numberRange(within []NumberRange) (NumberRange, bool, error) {
+	if len(within) == 0 {
+		return NumberRange{}, false, nil
+	}
+	r := within[0]
+	if r.Min > r.Max {
+		return NumberRange{}, false, fmt.Errorf("invalid range: %d > %d", r.Min, r.Max)
+	}
+	return r, true, nil
+}
+
+// GenerateEven asks the model for a random even number, within the given
+// range if any. The answer is parsed with ParseNumber and certified by asking
+// IsEven about it through the middleware; numbers that are unparsable, out
+// of range or not certified are asked for again, up to three times, before
+// failing with ErrNotGenerated. At the default temperature of 0 the model
+// tends to produce the same number every time.
+//
+// Like ExtractAndCheck, the request for the number bypasses middleware, but
+// counts towards Usage and the Budget and is recorded in the audit log.
+func (ai *IsEvenAiGemini) GenerateEven(ctx context.Context, within ...NumberRange) (int, error) {
+	return ai.generateParity(ctx, true, within)
+}
+
+// GenerateOdd is like GenerateEven for odd numbers, certified with IsOdd.
+func (ai *IsEvenAiGemini) GenerateOdd(ctx context.Context, within ...NumberRange) (int, error) {
+	return ai.generateParity(ctx, false, within)
+}
+
+// generateParity asks for a certified number of the given parity.
+func (ai *IsEvenAiGemini) generateParity(ctx context.Context, even bool, within []NumberRange) (int, error) {
+	r, bounded, err := numberRange(within)
+	if err != nil {
+		return 0, err
+	}
+	parity, predicate := "odd", "generateOdd"
+	if even {
+		parity, predicate = "even", "generateEven"
+	}
+	opts := AskIntOptions{Predicate: predicate, Validate: ai.certifier(ctx, even)}
+	prompt := "Give me a random " + parity + " number"
+	if bounded {
+		prompt += fmt.Sprintf(" between %d and %d (inclusive)", r.Min, r.Max)
+		opts.Args, opts.Range = []int{r.Min, r.Max}, &r
+	}
+	prompt += ". Answer with the number only, in digits."
+
+	n, err := ai.AskInt(ctx, prompt, opts)
+	if errors.Is(err, ErrAnswerRejected) {
+		return 0, fmt.Errorf("%w: %w", ErrNotGenerated, err)
+	}
+	return n, err
+}
+
+// certifier returns an AskIntOptions.Validate function that accepts numbers
+// the model says are even, or odd, through the middleware.
+func (ai *IsEvenAiGemini) certifier(ctx context.Context, even bool) func(int) (bool, error) {
+	certify := ai.WithContext(ctx).IsOdd
+	if even {
+		certify = ai.WithContext(ctx).IsEven
+	}
+	return func(n int) (bool, error) {
+		certified, err := certify(n)
+		return certified != nil && *certified, err
+	}
+}
+
+// GenerateEven returns a random even number within the given range, or
+// between -1000 and 1000. It fails with ErrNotGenerated if the range holds
+// no even number.
+func (l *IsEvenAiLocal) GenerateEven(ctx context.Context, within ...NumberRange) (int, error) {
+	return l.generateParity(ctx, 0, within)
+}
+
+// GenerateOdd is like GenerateEven for odd numbers.
+func (l *IsEvenAiLocal) GenerateOdd(ctx context.Context, within ...NumberRange) (int, error) {
+	return l.generateParity(ctx, 1, within)
+}
+
+// generateParity returns a random number within the range whose remainder modulo
+// 2 is rem.
+func (l *IsEvenAiLocal) generateParity(ctx context.Context, rem int, within []NumberRange) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r, bounded, err := numberRange(within)
+	if err != nil {
+		return 0, err
+	}
+	if !bounded {
+		r = defaultLocalRange
+	}
+	first := r.Min
+	if (first%2 != 0) != (rem != 0) {
+		if first == r.Max {
+			return 0, fmt.Errorf("%w: range %d to %d holds no such number", ErrNotGenerated, r.Min, r.Max)
+		}
+		first++
+	}
+	if first > r.Max {
+		return 0, fmt.Errorf("%w: range %d to %d holds no such number", ErrNotGenerated, r.Min, r.Max)
+	}
+	// In uint64, as the span of a range up to MinInt to MaxInt overflows int.
+	steps := (uint64(r.Max) - uint64(first)) / 2
+	return int(uint64(first) + 2*rand.Uint64N(steps+1)), nil
+}
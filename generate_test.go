@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_GenerateEven(t *testing.T) {
+	numbers := []string{"eleven", "12", "**4**"}
+	var generated, certified int
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "Give me a random even number between 0 and 10"):
+			geminitest.WriteAnswer(w, r, numbers[min(generated, len(numbers)-1)])
+			generated++
+		case strings.Contains(string(body), "Is 4 an even number?"):
+			certified++
+			geminitest.WriteAnswer(w, r, "true")
+		default:
+			t.Errorf("Unexpected request: %s", body)
+			geminitest.WriteAnswer(w, r, "false")
+		}
+	})
+
+	n, err := ai.GenerateEven(context.Background(), NumberRange{Min: 0, Max: 10})
+	if err != nil || n != 4 {
+		t.Fatalf("GenerateEven() = %d, %v; want 4", n, err)
+	}
+	if generated != 3 || certified != 1 {
+		t.Errorf("Expected 3 generation and 1 certification requests, got %d and %d", generated, certified)
+	}
+	if byPred := ai.UsageByPredicate(); byPred["generateEven"].Requests != 3 || byPred["isEven"].Requests != 1 {
+		t.Errorf("Unexpected usage by predicate: %+v", byPred)
+	}
+	if _, err := ai.GenerateEven(context.Background(), NumberRange{Min: 10, Max: 0}); err == nil {
+		t.Error("GenerateEven() with an empty range succeeded, want an error")
+	}
+}
+
+func TestIsEvenAiGemini_GenerateOdd_NotCertified(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "Give me a random odd number.") {
+			geminitest.WriteAnswer(w, r, "8")
+			return
+		}
+		geminitest.WriteAnswer(w, r, "false") // 8 is not odd
+	})
+
+	if n, err := ai.GenerateOdd(context.Background()); !errors.Is(err, ErrNotGenerated) {
+		t.Errorf("GenerateOdd() = %d, %v; want ErrNotGenerated", n, err)
+	}
+}
+
+func TestIsEvenAiLocal_Generate(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	ctx := context.Background()
+	for range 100 {
+		n, err := l.GenerateEven(ctx, NumberRange{Min: -5, Max: 5})
+		if err != nil || n%2 != 0 || n < -5 || n > 5 {
+			t.Fatalf("GenerateEven(-5, 5) = %d, %v", n, err)
+		}
+		n, err = l.GenerateOdd(ctx, NumberRange{Min: -4, Max: 4})
+		if err != nil || n%2 == 0 || n < -4 || n > 4 {
+			t.Fatalf("GenerateOdd(-4, 4) = %d, %v", n, err)
+		}
+		if n, err := l.GenerateEven(ctx); err != nil || !defaultLocalRange.Contains(n) || n%2 != 0 {
+			t.Fatalf("GenerateEven() = %d, %v", n, err)
+		}
+	}
+	if n, err := l.GenerateOdd(ctx, NumberRange{Min: 2, Max: 2}); !errors.Is(err, ErrNotGenerated) {
+		t.Errorf("GenerateOdd(2, 2) = %d, %v; want ErrNotGenerated", n, err)
+	}
+	if n, err := l.GenerateEven(ctx, NumberRange{Min: -3, Max: -3}); !errors.Is(err, ErrNotGenerated) {
+		t.Errorf("GenerateEven(-3, -3) = %d, %v; want ErrNotGenerated", n, err)
+	}
+}
+
+func TestIsEvenAiLocal_GenerateAtIntLimits(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	ctx := context.Background()
+	testCases := []struct {
+		name  string
+		r     NumberRange
+		odd   bool
+		valid bool
+	}{
+		{"EvenFromMinInt", NumberRange{Min: math.MinInt, Max: 0}, false, true},
+		{"OddFromMinInt", NumberRange{Min: math.MinInt, Max: 0}, true, true},
+		{"EvenToMaxInt", NumberRange{Min: -10, Max: math.MaxInt}, false, true},
+		{"OddToMaxInt", NumberRange{Min: -10, Max: math.MaxInt}, true, true},
+		{"EvenFullRange", NumberRange{Min: math.MinInt, Max: math.MaxInt}, false, true},
+		{"OddFullRange", NumberRange{Min: math.MinInt, Max: math.MaxInt}, true, true},
+		{"OddOnlyMinInt", NumberRange{Min: math.MinInt, Max: math.MinInt}, true, false},
+		{"EvenOnlyMaxInt", NumberRange{Min: math.MaxInt, Max: math.MaxInt}, false, false},
+		{"OddOnlyMaxInt", NumberRange{Min: math.MaxInt, Max: math.MaxInt}, true, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			generate := l.GenerateEven
+			if tc.odd {
+				generate = l.GenerateOdd
+			}
+			seen := map[int]bool{}
+			for range 20 {
+				n, err := generate(ctx, tc.r)
+				if !tc.valid {
+					if !errors.Is(err, ErrNotGenerated) {
+						t.Fatalf("Got %d, %v; want ErrNotGenerated", n, err)
+					}
+					return
+				}
+				if err != nil || (n%2 != 0) != tc.odd || !tc.r.Contains(n) {
+					t.Fatalf("Got %d, %v; want a number of the requested parity in %+v", n, err, tc.r)
+				}
+				seen[n] = true
+			}
+			if tc.r.Min != tc.r.Max && len(seen) == 1 {
+				t.Errorf("Expected random numbers, got only %v", seen)
+			}
+		})
+	}
+}
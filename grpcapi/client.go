@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+
+	"google.golang.org/grpc"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Client is a Provider that asks a remote IsEvenAI service.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  IsEvenAIClient
+}
+
+var _ is_even_ai.Provider = (*Client)(nil)
+
+// NewClient returns a Client using conn. Closing the Client closes conn.
+func NewClient(conn *grpc.ClientConn) *Client {
+	return &Client{conn: conn, rpc: NewIsEvenAIClient(conn)}
+}
+
+// IsEven implements Provider.
+func (c *Client) IsEven(n int) (*bool, error) {
+	return fromResponse(c.rpc.IsEven(context.Background(), &NumberRequest{N: int64(n)}))
+}
+
+// IsOdd implements Provider.
+func (c *Client) IsOdd(n int) (*bool, error) {
+	return fromResponse(c.rpc.IsOdd(context.Background(), &NumberRequest{N: int64(n)}))
+}
+
+// AreEqual implements Provider.
+func (c *Client) AreEqual(a, b int) (*bool, error) {
+	return c.compare(a, b, Comparison_COMPARISON_EQUAL)
+}
+
+// AreNotEqual implements Provider.
+func (c *Client) AreNotEqual(a, b int) (*bool, error) {
+	return c.compare(a, b, Comparison_COMPARISON_NOT_EQUAL)
+}
+
+// IsGreaterThan implements Provider.
+func (c *Client) IsGreaterThan(a, b int) (*bool, error) {
+	return c.compare(a, b, Comparison_COMPARISON_GREATER_THAN)
+}
+
+// IsLessThan implements Provider.
+func (c *Client) IsLessThan(a, b int) (*bool, error) {
+	return c.compare(a, b, Comparison_COMPARISON_LESS_THAN)
+}
+
+func (c *Client) compare(a, b int, op Comparison) (*bool, error) {
+	return fromResponse(c.rpc.Compare(context.Background(), &CompareRequest{A: int64(a), B: int64(b), Op: op}))
+}
+
+// Batch asks predicate for each of numbers and yields the answers as the
+// server streams them back, i.e. possibly out of order. If the stream fails,
+// Batch yields the error once and stops.
+func (c *Client) Batch(ctx context.Context, predicate Predicate, numbers []int) iter.Seq2[is_even_ai.BatchResult, error] {
+	return func(yield func(is_even_ai.BatchResult, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		req := &BatchRequest{Predicate: predicate, Numbers: make([]int64, len(numbers))}
+		for i, n := range numbers {
+			req.Numbers[i] = int64(n)
+		}
+		stream, err := c.rpc.Batch(ctx, req)
+		if err != nil {
+			yield(is_even_ai.BatchResult{}, err)
+			return
+		}
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(is_even_ai.BatchResult{}, err)
+				return
+			}
+			res := is_even_ai.BatchResult{Index: int(resp.Index), N: int(resp.N), Result: fromAnswer(resp.Answer)}
+			if resp.Error != "" {
+				res.Err = errors.New(resp.Error)
+			}
+			if !yield(res, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func fromResponse(resp *AnswerResponse, err error) (*bool, error) {
+	if err != nil {
+		return nil, err
+	}
+	return fromAnswer(resp.Answer), nil
+}
+
+func fromAnswer(answer Answer) *bool {
+	var b bool
+	switch answer {
+	case Answer_ANSWER_TRUE:
+		b = true
+	case Answer_ANSWER_FALSE:
+		b = false
+	default:
+		return nil
+	}
+	return &b
+}
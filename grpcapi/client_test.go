@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// newTestClient serves core over an in-memory connection and returns a client for it.
+func newTestClient(t *testing.T, core *is_even_ai.IsEvenAiCore) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterIsEvenAIServer(srv, NewServer(core, ServerOptions{Concurrency: 2}))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(conn)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestClient(t *testing.T) {
+	c := newTestClient(t, is_even_ai.NewIsEvenAiLocal().IsEvenAiCore)
+	testCases := []struct {
+		name string
+		call func() (*bool, error)
+		want bool
+	}{
+		{"IsEven(4)", func() (*bool, error) { return c.IsEven(4) }, true},
+		{"IsOdd(4)", func() (*bool, error) { return c.IsOdd(4) }, false},
+		{"AreEqual(3, 3)", func() (*bool, error) { return c.AreEqual(3, 3) }, true},
+		{"AreNotEqual(3, 3)", func() (*bool, error) { return c.AreNotEqual(3, 3) }, false},
+		{"IsGreaterThan(8, 7)", func() (*bool, error) { return c.IsGreaterThan(8, 7) }, true},
+		{"IsLessThan(8, 7)", func() (*bool, error) { return c.IsLessThan(8, 7) }, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.call()
+			if err != nil || got == nil || *got != tc.want {
+				t.Errorf("%s = %v, %v; want %v", tc.name, got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestClient_Undefined(t *testing.T) {
+	core := is_even_ai.NewIsEvenAiCore(is_even_ai.DefaultGeminiPromptTemplates, func(string) (*bool, error) {
+		return nil, nil
+	})
+	if got, err := newTestClient(t, core).IsEven(2); err != nil || got != nil {
+		t.Errorf("IsEven(2) = %v, %v; want undefined", got, err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	testCases := []struct {
+		err      error
+		wantCode codes.Code
+	}{
+		{is_even_ai.ErrBudgetExceeded, codes.ResourceExhausted},
+		{is_even_ai.ErrRateLimited, codes.Unavailable},
+		{errors.New("boom"), codes.Unknown},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			core := is_even_ai.NewIsEvenAiCore(is_even_ai.DefaultGeminiPromptTemplates, func(string) (*bool, error) {
+				return nil, tc.err
+			})
+			_, err := newTestClient(t, core).IsEven(2)
+			if status.Code(err) != tc.wantCode {
+				t.Errorf("Expected code %v, got %v", tc.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestClient_Batch(t *testing.T) {
+	c := newTestClient(t, is_even_ai.NewIsEvenAiLocal().IsEvenAiCore)
+	numbers := []int{5, 6, 7, 8}
+	got := make([]*bool, len(numbers))
+	for res, err := range c.Batch(context.Background(), Predicate_PREDICATE_IS_ODD, numbers) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Err != nil || res.N != numbers[res.Index] {
+			t.Fatalf("Unexpected result %+v", res)
+		}
+		got[res.Index] = res.Result
+	}
+	for i, n := range numbers {
+		if got[i] == nil || *got[i] != (n%2 != 0) {
+			t.Errorf("IsOdd(%d) = %v", n, got[i])
+		}
+	}
+}
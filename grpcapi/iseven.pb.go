@@ -0,0 +1,560 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: iseven.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Answer is the AI's verdict. Undefined means the AI gave no usable answer.
+type Answer int32
+
+const (
+	Answer_ANSWER_UNDEFINED Answer = 0
+	Answer_ANSWER_TRUE      Answer = 1
+	Answer_ANSWER_FALSE     Answer = 2
+)
+
+// Enum value maps for Answer.
+var (
+	Answer_name = map[int32]string{
+		0: "ANSWER_UNDEFINED",
+		1: "ANSWER_TRUE",
+		2: "ANSWER_FALSE",
+	}
+	Answer_value = map[string]int32{
+		"ANSWER_UNDEFINED": 0,
+		"ANSWER_TRUE":      1,
+		"ANSWER_FALSE":     2,
+	}
+)
+
+func (x Answer) Enum() *Answer {
+	p := new(Answer)
+	*p = x
+	return p
+}
+
+func (x Answer) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Answer) Descriptor() protoreflect.EnumDescriptor {
+	return file_iseven_proto_enumTypes[0].Descriptor()
+}
+
+func (Answer) Type() protoreflect.EnumType {
+	return &file_iseven_proto_enumTypes[0]
+}
+
+func (x Answer) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Answer.Descriptor instead.
+func (Answer) EnumDescriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{0}
+}
+
+type Comparison int32
+
+const (
+	Comparison_COMPARISON_UNSPECIFIED  Comparison = 0
+	Comparison_COMPARISON_EQUAL        Comparison = 1
+	Comparison_COMPARISON_NOT_EQUAL    Comparison = 2
+	Comparison_COMPARISON_GREATER_THAN Comparison = 3
+	Comparison_COMPARISON_LESS_THAN    Comparison = 4
+)
+
+// Enum value maps for Comparison.
+var (
+	Comparison_name = map[int32]string{
+		0: "COMPARISON_UNSPECIFIED",
+		1: "COMPARISON_EQUAL",
+		2: "COMPARISON_NOT_EQUAL",
+		3: "COMPARISON_GREATER_THAN",
+		4: "COMPARISON_LESS_THAN",
+	}
+	Comparison_value = map[string]int32{
+		"COMPARISON_UNSPECIFIED":  0,
+		"COMPARISON_EQUAL":        1,
+		"COMPARISON_NOT_EQUAL":    2,
+		"COMPARISON_GREATER_THAN": 3,
+		"COMPARISON_LESS_THAN":    4,
+	}
+)
+
+func (x Comparison) Enum() *Comparison {
+	p := new(Comparison)
+	*p = x
+	return p
+}
+
+func (x Comparison) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Comparison) Descriptor() protoreflect.EnumDescriptor {
+	return file_iseven_proto_enumTypes[1].Descriptor()
+}
+
+func (Comparison) Type() protoreflect.EnumType {
+	return &file_iseven_proto_enumTypes[1]
+}
+
+func (x Comparison) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Comparison.Descriptor instead.
+func (Comparison) EnumDescriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{1}
+}
+
+type Predicate int32
+
+const (
+	Predicate_PREDICATE_UNSPECIFIED Predicate = 0 // Same as PREDICATE_IS_EVEN
+	Predicate_PREDICATE_IS_EVEN     Predicate = 1
+	Predicate_PREDICATE_IS_ODD      Predicate = 2
+)
+
+// Enum value maps for Predicate.
+var (
+	Predicate_name = map[int32]string{
+		0: "PREDICATE_UNSPECIFIED",
+		1: "PREDICATE_IS_EVEN",
+		2: "PREDICATE_IS_ODD",
+	}
+	Predicate_value = map[string]int32{
+		"PREDICATE_UNSPECIFIED": 0,
+		"PREDICATE_IS_EVEN":     1,
+		"PREDICATE_IS_ODD":      2,
+	}
+)
+
+func (x Predicate) Enum() *Predicate {
+	p := new(Predicate)
+	*p = x
+	return p
+}
+
+func (x Predicate) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Predicate) Descriptor() protoreflect.EnumDescriptor {
+	return file_iseven_proto_enumTypes[2].Descriptor()
+}
+
+func (Predicate) Type() protoreflect.EnumType {
+	return &file_iseven_proto_enumTypes[2]
+}
+
+func (x Predicate) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Predicate.Descriptor instead.
+func (Predicate) EnumDescriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{2}
+}
+
+type NumberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int64                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NumberRequest) Reset() {
+	*x = NumberRequest{}
+	mi := &file_iseven_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NumberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NumberRequest) ProtoMessage() {}
+
+func (x *NumberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iseven_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NumberRequest.ProtoReflect.Descriptor instead.
+func (*NumberRequest) Descriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NumberRequest) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+type CompareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	A             int64                  `protobuf:"varint,1,opt,name=a,proto3" json:"a,omitempty"`
+	B             int64                  `protobuf:"varint,2,opt,name=b,proto3" json:"b,omitempty"`
+	Op            Comparison             `protobuf:"varint,3,opt,name=op,proto3,enum=isevenai.v1.Comparison" json:"op,omitempty"` // Asks whether "a op b"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareRequest) Reset() {
+	*x = CompareRequest{}
+	mi := &file_iseven_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareRequest) ProtoMessage() {}
+
+func (x *CompareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iseven_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareRequest.ProtoReflect.Descriptor instead.
+func (*CompareRequest) Descriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompareRequest) GetA() int64 {
+	if x != nil {
+		return x.A
+	}
+	return 0
+}
+
+func (x *CompareRequest) GetB() int64 {
+	if x != nil {
+		return x.B
+	}
+	return 0
+}
+
+func (x *CompareRequest) GetOp() Comparison {
+	if x != nil {
+		return x.Op
+	}
+	return Comparison_COMPARISON_UNSPECIFIED
+}
+
+type AnswerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Answer        Answer                 `protobuf:"varint,1,opt,name=answer,proto3,enum=isevenai.v1.Answer" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnswerResponse) Reset() {
+	*x = AnswerResponse{}
+	mi := &file_iseven_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnswerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnswerResponse) ProtoMessage() {}
+
+func (x *AnswerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iseven_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnswerResponse.ProtoReflect.Descriptor instead.
+func (*AnswerResponse) Descriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AnswerResponse) GetAnswer() Answer {
+	if x != nil {
+		return x.Answer
+	}
+	return Answer_ANSWER_UNDEFINED
+}
+
+type BatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Predicate     Predicate              `protobuf:"varint,1,opt,name=predicate,proto3,enum=isevenai.v1.Predicate" json:"predicate,omitempty"`
+	Numbers       []int64                `protobuf:"varint,2,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_iseven_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iseven_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchRequest) GetPredicate() Predicate {
+	if x != nil {
+		return x.Predicate
+	}
+	return Predicate_PREDICATE_UNSPECIFIED
+}
+
+func (x *BatchRequest) GetNumbers() []int64 {
+	if x != nil {
+		return x.Numbers
+	}
+	return nil
+}
+
+type BatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Position of n in the request
+	N             int64                  `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	Answer        Answer                 `protobuf:"varint,3,opt,name=answer,proto3,enum=isevenai.v1.Answer" json:"answer,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // Set if this number could not be answered
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_iseven_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iseven_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_iseven_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResponse) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *BatchResponse) GetAnswer() Answer {
+	if x != nil {
+		return x.Answer
+	}
+	return Answer_ANSWER_UNDEFINED
+}
+
+func (x *BatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_iseven_proto protoreflect.FileDescriptor
+
+const file_iseven_proto_rawDesc = "" +
+	"\n" +
+	"\fiseven.proto\x12\visevenai.v1\"\x1d\n" +
+	"\rNumberRequest\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x03R\x01n\"U\n" +
+	"\x0eCompareRequest\x12\f\n" +
+	"\x01a\x18\x01 \x01(\x03R\x01a\x12\f\n" +
+	"\x01b\x18\x02 \x01(\x03R\x01b\x12'\n" +
+	"\x02op\x18\x03 \x01(\x0e2\x17.isevenai.v1.ComparisonR\x02op\"=\n" +
+	"\x0eAnswerResponse\x12+\n" +
+	"\x06answer\x18\x01 \x01(\x0e2\x13.isevenai.v1.AnswerR\x06answer\"^\n" +
+	"\fBatchRequest\x124\n" +
+	"\tpredicate\x18\x01 \x01(\x0e2\x16.isevenai.v1.PredicateR\tpredicate\x12\x18\n" +
+	"\anumbers\x18\x02 \x03(\x03R\anumbers\"v\n" +
+	"\rBatchResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\f\n" +
+	"\x01n\x18\x02 \x01(\x03R\x01n\x12+\n" +
+	"\x06answer\x18\x03 \x01(\x0e2\x13.isevenai.v1.AnswerR\x06answer\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error*A\n" +
+	"\x06Answer\x12\x14\n" +
+	"\x10ANSWER_UNDEFINED\x10\x00\x12\x0f\n" +
+	"\vANSWER_TRUE\x10\x01\x12\x10\n" +
+	"\fANSWER_FALSE\x10\x02*\x8f\x01\n" +
+	"\n" +
+	"Comparison\x12\x1a\n" +
+	"\x16COMPARISON_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10COMPARISON_EQUAL\x10\x01\x12\x18\n" +
+	"\x14COMPARISON_NOT_EQUAL\x10\x02\x12\x1b\n" +
+	"\x17COMPARISON_GREATER_THAN\x10\x03\x12\x18\n" +
+	"\x14COMPARISON_LESS_THAN\x10\x04*S\n" +
+	"\tPredicate\x12\x19\n" +
+	"\x15PREDICATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11PREDICATE_IS_EVEN\x10\x01\x12\x14\n" +
+	"\x10PREDICATE_IS_ODD\x10\x022\x96\x02\n" +
+	"\bIsEvenAI\x12A\n" +
+	"\x06IsEven\x12\x1a.isevenai.v1.NumberRequest\x1a\x1b.isevenai.v1.AnswerResponse\x12@\n" +
+	"\x05IsOdd\x12\x1a.isevenai.v1.NumberRequest\x1a\x1b.isevenai.v1.AnswerResponse\x12C\n" +
+	"\aCompare\x12\x1b.isevenai.v1.CompareRequest\x1a\x1b.isevenai.v1.AnswerResponse\x12@\n" +
+	"\x05Batch\x12\x19.isevenai.v1.BatchRequest\x1a\x1a.isevenai.v1.BatchResponse0\x01B&Z$github.com/philwo/is-even-ai/grpcapib\x06proto3"
+
+var (
+	file_iseven_proto_rawDescOnce sync.Once
+	file_iseven_proto_rawDescData []byte
+)
+
+func file_iseven_proto_rawDescGZIP() []byte {
+	file_iseven_proto_rawDescOnce.Do(func() {
+		file_iseven_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_iseven_proto_rawDesc), len(file_iseven_proto_rawDesc)))
+	})
+	return file_iseven_proto_rawDescData
+}
+
+var file_iseven_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_iseven_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_iseven_proto_goTypes = []any{
+	(Answer)(0),            // 0: isevenai.v1.Answer
+	(Comparison)(0),        // 1: isevenai.v1.Comparison
+	(Predicate)(0),         // 2: isevenai.v1.Predicate
+	(*NumberRequest)(nil),  // 3: isevenai.v1.NumberRequest
+	(*CompareRequest)(nil), // 4: isevenai.v1.CompareRequest
+	(*AnswerResponse)(nil), // 5: isevenai.v1.AnswerResponse
+	(*BatchRequest)(nil),   // 6: isevenai.v1.BatchRequest
+	(*BatchResponse)(nil),  // 7: isevenai.v1.BatchResponse
+}
+var file_iseven_proto_depIdxs = []int32{
+	1, // 0: isevenai.v1.CompareRequest.op:type_name -> isevenai.v1.Comparison
+	0, // 1: isevenai.v1.AnswerResponse.answer:type_name -> isevenai.v1.Answer
+	2, // 2: isevenai.v1.BatchRequest.predicate:type_name -> isevenai.v1.Predicate
+	0, // 3: isevenai.v1.BatchResponse.answer:type_name -> isevenai.v1.Answer
+	3, // 4: isevenai.v1.IsEvenAI.IsEven:input_type -> isevenai.v1.NumberRequest
+	3, // 5: isevenai.v1.IsEvenAI.IsOdd:input_type -> isevenai.v1.NumberRequest
+	4, // 6: isevenai.v1.IsEvenAI.Compare:input_type -> isevenai.v1.CompareRequest
+	6, // 7: isevenai.v1.IsEvenAI.Batch:input_type -> isevenai.v1.BatchRequest
+	5, // 8: isevenai.v1.IsEvenAI.IsEven:output_type -> isevenai.v1.AnswerResponse
+	5, // 9: isevenai.v1.IsEvenAI.IsOdd:output_type -> isevenai.v1.AnswerResponse
+	5, // 10: isevenai.v1.IsEvenAI.Compare:output_type -> isevenai.v1.AnswerResponse
+	7, // 11: isevenai.v1.IsEvenAI.Batch:output_type -> isevenai.v1.BatchResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_iseven_proto_init() }
+func file_iseven_proto_init() {
+	if File_iseven_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iseven_proto_rawDesc), len(file_iseven_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_iseven_proto_goTypes,
+		DependencyIndexes: file_iseven_proto_depIdxs,
+		EnumInfos:         file_iseven_proto_enumTypes,
+		MessageInfos:      file_iseven_proto_msgTypes,
+	}.Build()
+	File_iseven_proto = out.File
+	file_iseven_proto_goTypes = nil
+	file_iseven_proto_depIdxs = nil
+}
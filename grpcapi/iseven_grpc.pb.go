@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: iseven.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IsEvenAI_IsEven_FullMethodName  = "/isevenai.v1.IsEvenAI/IsEven"
+	IsEvenAI_IsOdd_FullMethodName   = "/isevenai.v1.IsEvenAI/IsOdd"
+	IsEvenAI_Compare_FullMethodName = "/isevenai.v1.IsEvenAI/Compare"
+	IsEvenAI_Batch_FullMethodName   = "/isevenai.v1.IsEvenAI/Batch"
+)
+
+// IsEvenAIClient is the client API for IsEvenAI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IsEvenAI answers questions about numbers using the power of AI.
+type IsEvenAIClient interface {
+	IsEven(ctx context.Context, in *NumberRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	IsOdd(ctx context.Context, in *NumberRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	Compare(ctx context.Context, in *CompareRequest, opts ...grpc.CallOption) (*AnswerResponse, error)
+	// Batch answers a predicate for many numbers, streaming the answers back as
+	// they complete. Answers may arrive out of order; use index to restore it.
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResponse], error)
+}
+
+type isEvenAIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIsEvenAIClient(cc grpc.ClientConnInterface) IsEvenAIClient {
+	return &isEvenAIClient{cc}
+}
+
+func (c *isEvenAIClient) IsEven(ctx context.Context, in *NumberRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnswerResponse)
+	err := c.cc.Invoke(ctx, IsEvenAI_IsEven_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *isEvenAIClient) IsOdd(ctx context.Context, in *NumberRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnswerResponse)
+	err := c.cc.Invoke(ctx, IsEvenAI_IsOdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *isEvenAIClient) Compare(ctx context.Context, in *CompareRequest, opts ...grpc.CallOption) (*AnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnswerResponse)
+	err := c.cc.Invoke(ctx, IsEvenAI_Compare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *isEvenAIClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IsEvenAI_ServiceDesc.Streams[0], IsEvenAI_Batch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchRequest, BatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IsEvenAI_BatchClient = grpc.ServerStreamingClient[BatchResponse]
+
+// IsEvenAIServer is the server API for IsEvenAI service.
+// All implementations must embed UnimplementedIsEvenAIServer
+// for forward compatibility.
+//
+// IsEvenAI answers questions about numbers using the power of AI.
+type IsEvenAIServer interface {
+	IsEven(context.Context, *NumberRequest) (*AnswerResponse, error)
+	IsOdd(context.Context, *NumberRequest) (*AnswerResponse, error)
+	Compare(context.Context, *CompareRequest) (*AnswerResponse, error)
+	// Batch answers a predicate for many numbers, streaming the answers back as
+	// they complete. Answers may arrive out of order; use index to restore it.
+	Batch(*BatchRequest, grpc.ServerStreamingServer[BatchResponse]) error
+	mustEmbedUnimplementedIsEvenAIServer()
+}
+
+// UnimplementedIsEvenAIServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIsEvenAIServer struct{}
+
+func (UnimplementedIsEvenAIServer) IsEven(context.Context, *NumberRequest) (*AnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsEven not implemented")
+}
+func (UnimplementedIsEvenAIServer) IsOdd(context.Context, *NumberRequest) (*AnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsOdd not implemented")
+}
+func (UnimplementedIsEvenAIServer) Compare(context.Context, *CompareRequest) (*AnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compare not implemented")
+}
+func (UnimplementedIsEvenAIServer) Batch(*BatchRequest, grpc.ServerStreamingServer[BatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Batch not implemented")
+}
+func (UnimplementedIsEvenAIServer) mustEmbedUnimplementedIsEvenAIServer() {}
+func (UnimplementedIsEvenAIServer) testEmbeddedByValue()                  {}
+
+// UnsafeIsEvenAIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IsEvenAIServer will
+// result in compilation errors.
+type UnsafeIsEvenAIServer interface {
+	mustEmbedUnimplementedIsEvenAIServer()
+}
+
+func RegisterIsEvenAIServer(s grpc.ServiceRegistrar, srv IsEvenAIServer) {
+	// If the following call pancis, it indicates UnimplementedIsEvenAIServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IsEvenAI_ServiceDesc, srv)
+}
+
+func _IsEvenAI_IsEven_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IsEvenAIServer).IsEven(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IsEvenAI_IsEven_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IsEvenAIServer).IsEven(ctx, req.(*NumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IsEvenAI_IsOdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IsEvenAIServer).IsOdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IsEvenAI_IsOdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IsEvenAIServer).IsOdd(ctx, req.(*NumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IsEvenAI_Compare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IsEvenAIServer).Compare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IsEvenAI_Compare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IsEvenAIServer).Compare(ctx, req.(*CompareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IsEvenAI_Batch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IsEvenAIServer).Batch(m, &grpc.GenericServerStream[BatchRequest, BatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IsEvenAI_BatchServer = grpc.ServerStreamingServer[BatchResponse]
+
+// IsEvenAI_ServiceDesc is the grpc.ServiceDesc for IsEvenAI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IsEvenAI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "isevenai.v1.IsEvenAI",
+	HandlerType: (*IsEvenAIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IsEven",
+			Handler:    _IsEvenAI_IsEven_Handler,
+		},
+		{
+			MethodName: "IsOdd",
+			Handler:    _IsEvenAI_IsOdd_Handler,
+		},
+		{
+			MethodName: "Compare",
+			Handler:    _IsEvenAI_Compare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Batch",
+			Handler:       _IsEvenAI_Batch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "iseven.proto",
+}
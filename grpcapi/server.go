@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package grpcapi provides the IsEvenAI gRPC service defined in iseven.proto,
+// with a server backed by any provider and a client that is itself a
+// Provider.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative iseven.proto
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// ServerOptions configures a Server.
+type ServerOptions struct {
+	// Concurrency is the maximum number of parallel queries per Batch call.
+	// Defaults to 8.
+	Concurrency int
+}
+
+// Server implements IsEvenAIServer on top of a provider's core.
+type Server struct {
+	UnimplementedIsEvenAIServer
+	core        *is_even_ai.IsEvenAiCore
+	concurrency int
+}
+
+var _ IsEvenAIServer = (*Server)(nil)
+
+// NewServer returns a Server answering with core. Register it with
+// RegisterIsEvenAIServer.
+func NewServer(core *is_even_ai.IsEvenAiCore, opts ServerOptions) *Server {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	return &Server{core: core, concurrency: opts.Concurrency}
+}
+
+// IsEven implements IsEvenAIServer.
+func (s *Server) IsEven(ctx context.Context, req *NumberRequest) (*AnswerResponse, error) {
+	return answerResponse(s.core.WithContext(ctx).IsEven(int(req.N)))
+}
+
+// IsOdd implements IsEvenAIServer.
+func (s *Server) IsOdd(ctx context.Context, req *NumberRequest) (*AnswerResponse, error) {
+	return answerResponse(s.core.WithContext(ctx).IsOdd(int(req.N)))
+}
+
+// Compare implements IsEvenAIServer.
+func (s *Server) Compare(ctx context.Context, req *CompareRequest) (*AnswerResponse, error) {
+	core := s.core.WithContext(ctx)
+	a, b := int(req.A), int(req.B)
+	switch req.Op {
+	case Comparison_COMPARISON_EQUAL:
+		return answerResponse(core.AreEqual(a, b))
+	case Comparison_COMPARISON_NOT_EQUAL:
+		return answerResponse(core.AreNotEqual(a, b))
+	case Comparison_COMPARISON_GREATER_THAN:
+		return answerResponse(core.IsGreaterThan(a, b))
+	case Comparison_COMPARISON_LESS_THAN:
+		return answerResponse(core.IsLessThan(a, b))
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported comparison %v", req.Op)
+	}
+}
+
+// Batch implements IsEvenAIServer.
+func (s *Server) Batch(req *BatchRequest, stream IsEvenAI_BatchServer) error {
+	var predicate func(c *is_even_ai.IsEvenAiCore, n int) (*bool, error)
+	switch req.Predicate {
+	case Predicate_PREDICATE_UNSPECIFIED, Predicate_PREDICATE_IS_EVEN:
+		predicate = (*is_even_ai.IsEvenAiCore).IsEven
+	case Predicate_PREDICATE_IS_ODD:
+		predicate = (*is_even_ai.IsEvenAiCore).IsOdd
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported predicate %v", req.Predicate)
+	}
+
+	numbers := make([]int, len(req.Numbers))
+	for i, n := range req.Numbers {
+		numbers[i] = int(n)
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	// Batches yield to unary calls under admission control.
+	ctx = is_even_ai.WithPriority(ctx, is_even_ai.PriorityLow)
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		return predicate(s.core.WithContext(ctx), n)
+	}
+	results := is_even_ai.Batch(ctx, numbers, s.concurrency, ask)
+	for res := range results {
+		resp := &BatchResponse{Index: int32(res.Index), N: int64(res.N), Answer: toAnswer(res.Result)}
+		if res.Err != nil {
+			resp.Error = res.Err.Error()
+		}
+		if err := stream.Send(resp); err != nil {
+			cancel()
+			for range results { // Let the remaining workers finish
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func answerResponse(answer *bool, err error) (*AnswerResponse, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &AnswerResponse{Answer: toAnswer(answer)}, nil
+}
+
+func toAnswer(answer *bool) Answer {
+	switch {
+	case answer == nil:
+		return Answer_ANSWER_UNDEFINED
+	case *answer:
+		return Answer_ANSWER_TRUE
+	default:
+		return Answer_ANSWER_FALSE
+	}
+}
+
+// toStatus converts a query error to a gRPC status error.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, is_even_ai.ErrBudgetExceeded), errors.Is(err, is_even_ai.ErrTenantRateLimited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, is_even_ai.ErrRateLimited), errors.Is(err, is_even_ai.ErrQueueFull),
+		errors.Is(err, is_even_ai.ErrShutdown), errors.Is(err, is_even_ai.ErrUnavailable), errors.Is(err, is_even_ai.ErrDegraded):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, is_even_ai.ErrUnknownTenant):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func TestServer_InvalidArgument(t *testing.T) {
+	s := NewServer(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, ServerOptions{})
+	if _, err := s.Compare(context.Background(), &CompareRequest{A: 1, B: 2}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unspecified comparison, got %v", err)
+	}
+}
+
+func TestServer_UnsupportedPredicate(t *testing.T) {
+	s := NewServer(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, ServerOptions{})
+	if err := s.Batch(&BatchRequest{Predicate: 42}, nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unknown predicate, got %v", err)
+	}
+}
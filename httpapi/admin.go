@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// AdminOptions configures the handler returned by NewAdminHandler.
+type AdminOptions struct {
+	// APIKeys lists the keys accepted for admin requests, sent like those of
+	// the API. All admin requests are rejected if it is empty.
+	APIKeys []string
+	// Stats, if set, reports cache counters and spending.
+	Stats *is_even_ai.Stats
+	// CacheLen, if set, reports the number of cached answers.
+	CacheLen func() int
+	// Degraded, if set, is switched by /admin/degraded.
+	Degraded *is_even_ai.DegradedMode
+	Logger   *slog.Logger
+}
+
+// adminServer implements the admin API.
+type adminServer struct {
+	core *is_even_ai.IsEvenAiCore
+	opts AdminOptions
+}
+
+// NewAdminHandler returns the admin API of a service answering with core:
+//
+//	GET    /admin/cache                      {"entries":12,"hits":40,"misses":12}
+//	DELETE /admin/cache[?n=7|?predicate=isEven]
+//	GET    /admin/budget                     {"usage":{...},"usage_by_predicate":{...},"budget":{...}}
+//	GET    /admin/degraded                   {"degraded":false}
+//	PUT    /admin/degraded                   {"degraded":true}
+//	PUT    /admin/templates                  a PromptTemplateFile
+//
+// DELETE /admin/cache purges the caches registered with core (see
+// IsEvenAiCore.RegisterCache): all answers, those about one number, or those
+// about one predicate. PUT /admin/templates replaces core's prompt templates.
+// Mount it next to the API, e.g. with mux.Handle("/admin/", admin).
+func NewAdminHandler(core *is_even_ai.IsEvenAiCore, opts AdminOptions) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	s := &adminServer{core: core, opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/cache", s.handleCache)
+	mux.HandleFunc("DELETE /admin/cache", s.handleFlush)
+	mux.HandleFunc("GET /admin/budget", s.handleBudget)
+	mux.HandleFunc("GET /admin/degraded", s.handleDegraded)
+	mux.HandleFunc("PUT /admin/degraded", s.handleDegraded)
+	mux.HandleFunc("PUT /admin/templates", s.handleTemplates)
+	return s.guard(mux)
+}
+
+// guard rejects requests without a valid admin key.
+func (s *adminServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validKey(s.opts.APIKeys, requestAPIKey(r)) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid admin key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type cacheResponse struct {
+	Entries *int  `json:"entries,omitempty"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+func (s *adminServer) handleCache(w http.ResponseWriter, _ *http.Request) {
+	var resp cacheResponse
+	if s.opts.CacheLen != nil {
+		n := s.opts.CacheLen()
+		resp.Entries = &n
+	}
+	if s.opts.Stats != nil {
+		snap := s.opts.Stats.Snapshot()
+		resp.Hits, resp.Misses = snap.CacheHits, snap.CacheMisses
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *adminServer) handleFlush(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var err error
+	switch {
+	case q.Has("n"):
+		n, convErr := strconv.Atoi(q.Get("n"))
+		if convErr != nil {
+			writeError(w, http.StatusBadRequest, "n must be an integer")
+			return
+		}
+		err = s.core.WithContext(r.Context()).InvalidateNumber(n)
+	case q.Has("predicate"):
+		err = s.core.WithContext(r.Context()).InvalidatePredicate(q.Get("predicate"))
+	default:
+		err = s.core.WithContext(r.Context()).FlushCache()
+	}
+	if err != nil {
+		s.opts.Logger.Error("cache not purged", "query", r.URL.RawQuery, "err", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.opts.Logger.Info("cache purged", "query", r.URL.RawQuery)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type budgetResponse struct {
+	Usage            *is_even_ai.Usage           `json:"usage,omitempty"`
+	UsageByPredicate map[string]is_even_ai.Usage `json:"usage_by_predicate,omitempty"`
+	Budget           *is_even_ai.BudgetStatus    `json:"budget,omitempty"`
+}
+
+func (s *adminServer) handleBudget(w http.ResponseWriter, _ *http.Request) {
+	if s.opts.Stats == nil {
+		writeError(w, http.StatusNotFound, "spending is not tracked")
+		return
+	}
+	snap := s.opts.Stats.Snapshot()
+	writeJSON(w, http.StatusOK, budgetResponse{Usage: snap.Usage, UsageByPredicate: snap.UsageByPredicate, Budget: snap.Budget})
+}
+
+type degradedState struct {
+	Degraded *bool `json:"degraded"`
+}
+
+func (s *adminServer) handleDegraded(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Degraded == nil {
+		writeError(w, http.StatusNotFound, "degraded mode is not available")
+		return
+	}
+	if r.Method == http.MethodPut {
+		var req degradedState
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil || req.Degraded == nil {
+			writeError(w, http.StatusBadRequest, `body must be {"degraded":true} or {"degraded":false}`)
+			return
+		}
+		s.opts.Degraded.Set(*req.Degraded)
+		s.opts.Logger.Info("degraded mode switched", "degraded", *req.Degraded)
+	}
+	on := s.opts.Degraded.Enabled()
+	writeJSON(w, http.StatusOK, degradedState{Degraded: &on})
+}
+
+type templatesResponse struct {
+	Version string `json:"version"`
+}
+
+func (s *adminServer) handleTemplates(w http.ResponseWriter, r *http.Request) {
+	f, err := is_even_ai.ReadPromptTemplateFile(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	templates, err := f.Templates()
+	if err == nil {
+		err = s.core.SetPromptTemplates(templates)
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	s.opts.Logger.Info("prompt templates reloaded", "version", f.Version)
+	writeJSON(w, http.StatusOK, templatesResponse{Version: f.Version})
+}
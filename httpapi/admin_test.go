@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func TestAdminHandler(t *testing.T) {
+	ai := is_even_ai.NewIsEvenAiLocal()
+	stats := &is_even_ai.Stats{}
+	cache := is_even_ai.NewMemoryCache()
+	degraded := &is_even_ai.DegradedMode{}
+	ai.Use(is_even_ai.CacheMiddleware(stats.Cache(cache)), degraded.Middleware())
+	ai.RegisterCache(cache)
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", NewAdminHandler(ai.IsEvenAiCore, AdminOptions{
+		APIKeys:  []string{"admin"},
+		Stats:    stats,
+		CacheLen: cache.Len,
+		Degraded: degraded,
+	}))
+	mux.Handle("/", NewHandler(ai.IsEvenAiCore, Options{}))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	admin := http.Header{"Authorization": {"Bearer admin"}}
+
+	for _, path := range []string{"/v1/is-even/2", "/v1/is-even/2", "/v1/is-even/3"} {
+		if status, body := do(t, "GET", srv.URL+path, "", nil); status != http.StatusOK {
+			t.Fatalf("GET %s = %d %s", path, status, body)
+		}
+	}
+	testCases := []struct {
+		method, path, body string
+		header             http.Header
+		wantStatus         int
+		wantBody           string
+	}{
+		{"GET", "/admin/cache", "", nil, http.StatusUnauthorized, `{"error":"missing or invalid admin key"}`},
+		{"GET", "/admin/cache", "", http.Header{"X-Api-Key": {"user"}}, http.StatusUnauthorized, `{"error":"missing or invalid admin key"}`},
+		{"GET", "/admin/cache", "", admin, http.StatusOK, `{"entries":2,"hits":1,"misses":2}`},
+		{"DELETE", "/admin/cache?n=x", "", admin, http.StatusBadRequest, `{"error":"n must be an integer"}`},
+		{"DELETE", "/admin/cache?n=3", "", admin, http.StatusNoContent, ``},
+		{"GET", "/admin/cache", "", admin, http.StatusOK, `{"entries":1,"hits":1,"misses":2}`},
+		{"DELETE", "/admin/cache", "", admin, http.StatusNoContent, ``},
+		{"GET", "/admin/cache", "", admin, http.StatusOK, `{"entries":0,"hits":1,"misses":2}`},
+		{"GET", "/admin/budget", "", admin, http.StatusOK, `{}`},
+		{"GET", "/admin/degraded", "", admin, http.StatusOK, `{"degraded":false}`},
+		{"PUT", "/admin/degraded", `{}`, admin, http.StatusBadRequest, `{"error":"body must be {\"degraded\":true} or {\"degraded\":false}"}`},
+		{"PUT", "/admin/degraded", `{"degraded":true}`, admin, http.StatusOK, `{"degraded":true}`},
+		{"GET", "/v1/is-even/4", "", nil, http.StatusServiceUnavailable, `{"error":"degraded mode: only cached answers are served"}`},
+		{"PUT", "/admin/degraded", `{"degraded":false}`, admin, http.StatusOK, `{"degraded":false}`},
+		{"PUT", "/admin/templates", `{"isEven":"Is {n} even?"}`, admin, http.StatusUnprocessableEntity, ``},
+		{"PUT", "/admin/templates", `{"version":"2","isEven":"Is {n} even?","areEqual":"{a} = {b}?","isGreaterThan":"{a} > {b}?"}`, admin, http.StatusOK, `{"version":"2"}`},
+	}
+	for _, tc := range testCases {
+		status, body := do(t, tc.method, srv.URL+tc.path, tc.body, tc.header)
+		if status != tc.wantStatus || (tc.wantBody != "" || status == http.StatusNoContent) && body != tc.wantBody {
+			t.Errorf("%s %s = %d %s; want %d %s", tc.method, tc.path, status, body, tc.wantStatus, tc.wantBody)
+		}
+	}
+	if v := ai.PromptTemplates().Version; v != "2" {
+		t.Errorf("template version after reload = %q, want 2", v)
+	}
+}
+
+func TestAdminHandler_NoKeys(t *testing.T) {
+	srv := httptest.NewServer(NewAdminHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, AdminOptions{}))
+	t.Cleanup(srv.Close)
+	if status, _ := do(t, "DELETE", srv.URL+"/admin/cache", "", http.Header{"Authorization": {"Bearer "}}); status != http.StatusUnauthorized {
+		t.Errorf("DELETE /admin/cache without configured keys = %d, want 401", status)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package httpapi serves the is-even-ai predicates as a JSON REST API:
+//
+//	GET  /v1/is-even/{n}               {"n":42,"answer":true}
+//	GET  /v1/compare?a=8&b=7&op=gt     {"a":8,"b":7,"op":"gt","answer":true}
+//	POST /v1/batch                     {"predicate":"even","numbers":[1,2]}
+//
+// Undefined answers are reported as "answer": null. The handler can be
+// mounted in an existing service, e.g. under a prefix with http.StripPrefix:
+//
+//	mux.Handle("/parity/", http.StripPrefix("/parity", httpapi.NewHandler(ai.IsEvenAiCore, httpapi.Options{})))
+//
+// NewAdminHandler serves routes for operators to manage the cache, spending,
+// degraded mode and prompt templates of a running service.
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Options configures the handler returned by NewHandler. The zero value
+// serves an open API without rate limiting, for services that bring their
+// own authentication and limits.
+type Options struct {
+	// APIKeys lists the accepted API keys, sent as "Authorization: Bearer KEY"
+	// or "X-API-Key: KEY". The API is open if empty.
+	APIKeys []string
+	// RateLimit is the requests per second allowed per API key, with bursts
+	// of up to Burst requests. Without API keys, all clients share one limit.
+	// It does not apply with Tenants, whose own TenantConfig.RateLimit is
+	// enforced by Tenants.Middleware instead.
+	// Zero means unlimited. Burst defaults to 1.
+	RateLimit rate.Limit
+	Burst     int
+	// Tenants, if set, authenticates requests with the API keys of its
+	// tenants instead of APIKeys, and queries on behalf of the tenant of the
+	// key (see is_even_ai.WithTenant), labeled with "tenant" and its ID. Add
+	// Tenants.Middleware to core to apply the tenants' limits and caches.
+	Tenants *is_even_ai.Tenants
+	// MaxBatch is the maximum number of numbers per batch request. Defaults to 1000.
+	// Under a RateLimit, each number counts as one request, so batches are
+	// also limited to Burst numbers.
+	MaxBatch int
+	// Concurrency is the maximum number of parallel queries per batch request.
+	// Defaults to 8.
+	Concurrency int
+	Logger      *slog.Logger
+}
+
+// server implements the HTTP API on top of a provider's core.
+type server struct {
+	core *is_even_ai.IsEvenAiCore
+	opts Options
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter // By API key
+}
+
+// NewHandler returns the HTTP API answering with core.
+func NewHandler(core *is_even_ai.IsEvenAiCore, opts Options) http.Handler {
+	if opts.RateLimit == 0 {
+		opts.RateLimit = rate.Inf
+	}
+	opts.Burst = max(opts.Burst, 1)
+	if opts.MaxBatch == 0 {
+		opts.MaxBatch = 1000
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = 8
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	s := &server{core: core, opts: opts, limiters: map[string]*rate.Limiter{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/is-even/{n}", s.handleIsEven)
+	mux.HandleFunc("GET /v1/compare", s.handleCompare)
+	mux.HandleFunc("POST /v1/batch", s.handleBatch)
+	return s.guard(mux)
+}
+
+// guard authenticates and rate limits requests before passing them to next.
+func (s *server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The limiter is only keyed by authenticated clients, so that
+		// made-up keys can neither evade the limit nor grow s.limiters.
+		// Tenants are limited by their own configuration.
+		var limiterKey string
+		limited := true
+		switch key := requestAPIKey(r); {
+		case s.opts.Tenants != nil:
+			id, ok := s.opts.Tenants.Authenticate(key)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+				return
+			}
+			ctx := is_even_ai.WithTenant(r.Context(), id)
+			r = r.WithContext(is_even_ai.WithLabels(ctx, is_even_ai.Labels{"tenant": id}))
+			limited = false
+		case len(s.opts.APIKeys) > 0:
+			if !validKey(s.opts.APIKeys, key) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+				return
+			}
+			limiterKey = key
+		}
+		if limited {
+			l := s.limiter(limiterKey)
+			if !l.Allow() {
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), limiterContextKey{}, l))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limiterContextKey is the context key of the rate limiter that guard
+// charged for a request.
+type limiterContextKey struct{}
+
+// requestAPIKey returns the API key sent with r, if any.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// validKey reports whether key is one of keys, in constant time.
+func validKey(keys []string, key string) bool {
+	valid := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// limiter returns the rate limiter for key, an authenticated API key.
+// Without authentication all requests share the limiter of the empty key.
+func (s *server) limiter(key string) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.limiters[key]
+	if !ok {
+		l = rate.NewLimiter(s.opts.RateLimit, s.opts.Burst)
+		s.limiters[key] = l
+	}
+	return l
+}
+
+type isEvenResponse struct {
+	N      int   `json:"n"`
+	Answer *bool `json:"answer"`
+}
+
+func (s *server) handleIsEven(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid number %q", r.PathValue("n")))
+		return
+	}
+	answer, err := s.core.WithContext(r.Context()).IsEven(n)
+	if err != nil {
+		s.writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, isEvenResponse{N: n, Answer: answer})
+}
+
+type compareResponse struct {
+	A      int    `json:"a"`
+	B      int    `json:"b"`
+	Op     string `json:"op"`
+	Answer *bool  `json:"answer"`
+}
+
+// comparisons maps the op parameter of /v1/compare to the predicate it asks.
+var comparisons = map[string]func(c *is_even_ai.IsEvenAiCore, a, b int) (*bool, error){
+	"eq": (*is_even_ai.IsEvenAiCore).AreEqual,
+	"ne": (*is_even_ai.IsEvenAiCore).AreNotEqual,
+	"gt": (*is_even_ai.IsEvenAiCore).IsGreaterThan,
+	"lt": (*is_even_ai.IsEvenAiCore).IsLessThan,
+}
+
+func (s *server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	a, errA := strconv.Atoi(q.Get("a"))
+	b, errB := strconv.Atoi(q.Get("b"))
+	if errA != nil || errB != nil {
+		writeError(w, http.StatusBadRequest, "a and b must be integers")
+		return
+	}
+	op := q.Get("op")
+	compare, ok := comparisons[op]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "op must be one of eq, ne, gt or lt")
+		return
+	}
+	answer, err := compare(s.core.WithContext(r.Context()), a, b)
+	if err != nil {
+		s.writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, compareResponse{A: a, B: b, Op: op, Answer: answer})
+}
+
+type batchRequest struct {
+	Predicate string `json:"predicate"` // "even" (default) or "odd"
+	Numbers   []int  `json:"numbers"`
+}
+
+type batchItem struct {
+	N      int    `json:"n"`
+	Answer *bool  `json:"answer"`
+	Error  string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchItem `json:"results"` // In the order of the request
+}
+
+// batchPredicates maps the predicate of a batch request to the method it asks.
+var batchPredicates = map[string]func(c *is_even_ai.IsEvenAiCore, n int) (*bool, error){
+	"":     (*is_even_ai.IsEvenAiCore).IsEven,
+	"even": (*is_even_ai.IsEvenAiCore).IsEven,
+	"odd":  (*is_even_ai.IsEvenAiCore).IsOdd,
+}
+
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	predicate, ok := batchPredicates[req.Predicate]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "predicate must be even or odd")
+		return
+	}
+	if len(req.Numbers) > s.opts.MaxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d numbers per batch", s.opts.MaxBatch))
+		return
+	}
+	// Each number counts as a request against the rate limit, of which guard
+	// already charged one.
+	if l, ok := r.Context().Value(limiterContextKey{}).(*rate.Limiter); ok && l.Limit() != rate.Inf && len(req.Numbers) > 1 {
+		if len(req.Numbers) > l.Burst() {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d numbers per batch under the rate limit", l.Burst()))
+			return
+		}
+		if !l.AllowN(time.Now(), len(req.Numbers)-1) {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+	}
+
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		return predicate(s.core.WithContext(ctx), n)
+	}
+	resp := batchResponse{Results: make([]batchItem, len(req.Numbers))}
+	// Batches yield to single queries under admission control.
+	ctx := is_even_ai.WithPriority(r.Context(), is_even_ai.PriorityLow)
+	for res := range is_even_ai.Batch(ctx, req.Numbers, s.opts.Concurrency, ask) {
+		item := batchItem{N: res.N, Answer: res.Result}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		}
+		resp.Results[res.Index] = item
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeQueryError reports a failed query. A tenant over its rate limit is
+// asked to slow down. Budget and other rate limit errors mean the service is
+// temporarily unable to answer; anything else is blamed on the upstream
+// provider.
+func (s *server) writeQueryError(w http.ResponseWriter, err error) {
+	s.opts.Logger.Warn("query failed", "err", err)
+	switch {
+	case errors.Is(err, is_even_ai.ErrTenantRateLimited):
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, is_even_ai.ErrBudgetExceeded), errors.Is(err, is_even_ai.ErrRateLimited), errors.Is(err, is_even_ai.ErrQueueFull),
+		errors.Is(err, is_even_ai.ErrShutdown), errors.Is(err, is_even_ai.ErrUnavailable), errors.Is(err, is_even_ai.ErrDegraded):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/time/rate"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func newTestServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	if opts.MaxBatch == 0 {
+		opts.MaxBatch = 10
+	}
+	srv := httptest.NewServer(NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, opts))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, url, body string, header http.Header) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(respBody))
+}
+
+func TestHandler(t *testing.T) {
+	srv := newTestServer(t, Options{})
+	testCases := []struct {
+		method, path, body string
+		wantStatus         int
+		wantBody           string
+	}{
+		{"GET", "/v1/is-even/42", "", http.StatusOK, `{"n":42,"answer":true}`},
+		{"GET", "/v1/is-even/-7", "", http.StatusOK, `{"n":-7,"answer":false}`},
+		{"GET", "/v1/is-even/forty-two", "", http.StatusBadRequest, `{"error":"invalid number \"forty-two\""}`},
+		{"GET", "/v1/compare?a=8&b=7&op=gt", "", http.StatusOK, `{"a":8,"b":7,"op":"gt","answer":true}`},
+		{"GET", "/v1/compare?a=8&b=7&op=eq", "", http.StatusOK, `{"a":8,"b":7,"op":"eq","answer":false}`},
+		{"GET", "/v1/compare?a=8&b=7&op=xor", "", http.StatusBadRequest, `{"error":"op must be one of eq, ne, gt or lt"}`},
+		{"GET", "/v1/compare?a=8&op=gt", "", http.StatusBadRequest, `{"error":"a and b must be integers"}`},
+		{"POST", "/v1/batch", `{"numbers":[1,2,3]}`, http.StatusOK, `{"results":[{"n":1,"answer":false},{"n":2,"answer":true},{"n":3,"answer":false}]}`},
+		{"POST", "/v1/batch", `{"predicate":"odd","numbers":[1]}`, http.StatusOK, `{"results":[{"n":1,"answer":true}]}`},
+		{"POST", "/v1/batch", `{"predicate":"prime","numbers":[1]}`, http.StatusBadRequest, `{"error":"predicate must be even or odd"}`},
+		{"POST", "/v1/batch", `{"numbers":[1,2,3,4,5,6,7,8,9,10,11]}`, http.StatusRequestEntityTooLarge, `{"error":"at most 10 numbers per batch"}`},
+		{"GET", "/v1/batch", "", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			status, body := do(t, tc.method, srv.URL+tc.path, tc.body, nil)
+			if status != tc.wantStatus || (tc.wantBody != "" && body != tc.wantBody) {
+				t.Errorf("Got %d %s; want %d %s", status, body, tc.wantStatus, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandler_Auth(t *testing.T) {
+	srv := newTestServer(t, Options{APIKeys: []string{"key-1", "key-2"}})
+	testCases := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+	}{
+		{"NoKey", nil, http.StatusUnauthorized},
+		{"WrongKey", http.Header{"X-Api-Key": {"key-3"}}, http.StatusUnauthorized},
+		{"APIKeyHeader", http.Header{"X-Api-Key": {"key-1"}}, http.StatusOK},
+		{"BearerToken", http.Header{"Authorization": {"Bearer key-2"}}, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, body := do(t, "GET", srv.URL+"/v1/is-even/2", "", tc.header); status != tc.wantStatus {
+				t.Errorf("Got %d %s; want %d", status, body, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_RateLimit(t *testing.T) {
+	srv := newTestServer(t, Options{APIKeys: []string{"a", "b"}, RateLimit: rate.Every(1e12), Burst: 2})
+	keyA := http.Header{"X-Api-Key": {"a"}}
+	for i := range 2 {
+		if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", keyA); status != http.StatusOK {
+			t.Fatalf("Request %d within burst failed with %d", i, status)
+		}
+	}
+	if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", keyA); status != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 after the burst, got %d", status)
+	}
+	if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", http.Header{"X-Api-Key": {"b"}}); status != http.StatusOK {
+		t.Errorf("Expected other keys to be unaffected, got %d", status)
+	}
+}
+
+func TestHandler_RateLimitBatch(t *testing.T) {
+	srv := newTestServer(t, Options{RateLimit: rate.Every(1e12), Burst: 5})
+	testCases := []struct {
+		name, body string
+		wantStatus int
+	}{
+		{"OverBurst", `{"numbers":[1,2,3,4,5,6]}`, http.StatusRequestEntityTooLarge},
+		{"WithinBurst", `{"numbers":[1,2,3]}`, http.StatusOK},
+		{"OverRemainingTokens", `{"numbers":[1,2]}`, http.StatusTooManyRequests},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, body := do(t, "POST", srv.URL+"/v1/batch", tc.body, nil); status != tc.wantStatus {
+				t.Errorf("Got %d %s; want %d", status, body, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_RateLimitDefaultBurst(t *testing.T) {
+	srv := newTestServer(t, Options{RateLimit: rate.Every(1e12)})
+	if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", nil); status != http.StatusOK {
+		t.Fatalf("Expected the first request to pass with the default burst, got %d", status)
+	}
+	if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", nil); status != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 after the default burst of 1, got %d", status)
+	}
+}
+
+func TestHandler_RateLimitWithoutAuth(t *testing.T) {
+	srv := newTestServer(t, Options{RateLimit: rate.Every(1e12), Burst: 2})
+	madeUpKey := func(i int) http.Header { return http.Header{"X-Api-Key": {fmt.Sprint("made-up-", i)}} }
+	for i := range 2 {
+		if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", madeUpKey(i)); status != http.StatusOK {
+			t.Fatalf("Request %d within burst failed with %d", i, status)
+		}
+	}
+	for i := 2; i < 4; i++ {
+		if status, _ := do(t, "GET", srv.URL+"/v1/is-even/2", "", madeUpKey(i)); status != http.StatusTooManyRequests {
+			t.Errorf("Expected 429 after the burst despite rotating keys, got %d", status)
+		}
+	}
+}
+
+func TestHandler_Tenants(t *testing.T) {
+	tenants := is_even_ai.NewTenants(map[string]is_even_ai.TenantConfig{
+		"a": {APIKeys: []string{"key-a"}, RateLimit: rate.Every(1e12), Burst: 1},
+		"b": {APIKeys: []string{"key-b"}},
+	})
+	stats := &is_even_ai.Stats{LabelNames: []string{"tenant"}}
+	ai := is_even_ai.NewIsEvenAiLocal()
+	ai.Use(stats.Middleware(), tenants.Middleware())
+	// The handler's own limit would reject a second request of tenant b.
+	srv := httptest.NewServer(NewHandler(ai.IsEvenAiCore, Options{APIKeys: []string{"other"}, Tenants: tenants, RateLimit: rate.Every(1e12), Burst: 1}))
+	defer srv.Close()
+
+	keyA := http.Header{"X-Api-Key": {"key-a"}}
+	testCases := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+	}{
+		{"NoKey", nil, http.StatusUnauthorized},
+		{"APIKeysIgnored", http.Header{"X-Api-Key": {"other"}}, http.StatusUnauthorized},
+		{"TenantA", keyA, http.StatusOK},
+		{"TenantARateLimited", keyA, http.StatusTooManyRequests},
+		{"TenantB", http.Header{"Authorization": {"Bearer key-b"}}, http.StatusOK},
+		{"TenantBUnlimited", http.Header{"Authorization": {"Bearer key-b"}}, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, body := do(t, "GET", srv.URL+"/v1/is-even/2", "", tc.header); status != tc.wantStatus {
+				t.Errorf("Got %d %s; want %d", status, body, tc.wantStatus)
+			}
+		})
+	}
+	if status, _ := tenants.Status("b"); status.Usage.Requests != 2 {
+		t.Errorf("Expected tenant b to be charged for 2 requests, got %d", status.Usage.Requests)
+	}
+	if byTenant := stats.Snapshot().ByLabel["tenant"]; byTenant["a"].Requests != 2 || byTenant["a"].Errors != 1 || byTenant["b"].Requests != 2 {
+		t.Errorf("Unexpected stats by tenant label: %+v", byTenant)
+	}
+}
+
+func TestHandler_QueryErrors(t *testing.T) {
+	testCases := []struct {
+		err        error
+		wantStatus int
+	}{
+		{is_even_ai.ErrBudgetExceeded, http.StatusServiceUnavailable},
+		{is_even_ai.ErrTenantRateLimited, http.StatusTooManyRequests},
+		{errors.New("upstream failure"), http.StatusBadGateway},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			core := is_even_ai.NewIsEvenAiCore(is_even_ai.DefaultGeminiPromptTemplates, func(string) (*bool, error) {
+				return nil, tc.err
+			})
+			srv := httptest.NewServer(NewHandler(core, Options{}))
+			defer srv.Close()
+			if status, body := do(t, "GET", srv.URL+"/v1/is-even/2", "", nil); status != tc.wantStatus {
+				t.Errorf("Got %d %s; want %d", status, body, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_Mounted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/parity/", http.StripPrefix("/parity", NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, Options{})))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	if status, body := do(t, "GET", srv.URL+"/parity/v1/is-even/4", "", nil); status != http.StatusOK || body != `{"n":4,"answer":true}` {
+		t.Errorf("Got %d %s from mounted handler", status, body)
+	}
+}
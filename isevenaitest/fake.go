@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package isevenaitest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// ErrScriptExhausted is returned by a FakeProvider once all steps of its script have been used.
+var ErrScriptExhausted = errors.New("isevenaitest: script exhausted")
+
+// Step is one scripted response of a FakeProvider.
+type Step struct {
+	Result *bool // nil for an undefined answer
+	Err    error
+	Delay  time.Duration // Time to wait before answering
+}
+
+// Answer returns a step that answers v.
+func Answer(v bool) Step {
+	return Step{Result: &v}
+}
+
+// Undefined returns a step that gives an undefined answer.
+func Undefined() Step {
+	return Step{}
+}
+
+// Fail returns a step that fails with err.
+func Fail(err error) Step {
+	return Step{Err: err}
+}
+
+// After returns a copy of s that waits d before answering.
+func (s Step) After(d time.Duration) Step {
+	s.Delay = d
+	return s
+}
+
+// ParseScript parses a compact transcript into steps, for table-driven tests.
+// Steps are separated by commas; each is "true", "false", "undefined" (or
+// "nil"), or "error" optionally followed by ":message". A step may be prefixed
+// by a delay and "+", e.g. "true, 50ms+false, error:quota exceeded, nil".
+func ParseScript(script string) ([]Step, error) {
+	var steps []Step
+	for _, field := range strings.Split(script, ",") {
+		field = strings.TrimSpace(field)
+		var delay time.Duration
+		if d, rest, ok := strings.Cut(field, "+"); ok {
+			var err error
+			if delay, err = time.ParseDuration(strings.TrimSpace(d)); err != nil {
+				return nil, fmt.Errorf("invalid delay in step %q: %w", field, err)
+			}
+			field = strings.TrimSpace(rest)
+		}
+		var step Step
+		switch kind, msg, _ := strings.Cut(field, ":"); kind {
+		case "true":
+			step = Answer(true)
+		case "false":
+			step = Answer(false)
+		case "undefined", "nil":
+			step = Undefined()
+		case "error":
+			if msg == "" {
+				msg = "scripted error"
+			}
+			step = Fail(errors.New(strings.TrimSpace(msg)))
+		default:
+			return nil, fmt.Errorf("invalid step %q", field)
+		}
+		steps = append(steps, step.After(delay))
+	}
+	return steps, nil
+}
+
+// FakeProvider is an is_even_ai.Provider that replays a script: each call,
+// whatever the predicate, consumes the next step. It is useful for exercising
+// retry, fallback and consensus logic. FakeProvider is safe for concurrent use.
+type FakeProvider struct {
+	mu     sync.Mutex
+	script []Step
+	next   int
+	calls  []Call
+	closed bool
+}
+
+var _ is_even_ai.Provider = (*FakeProvider)(nil)
+
+// NewFakeProvider returns a FakeProvider that follows script.
+func NewFakeProvider(script ...Step) *FakeProvider {
+	return &FakeProvider{script: script}
+}
+
+// MustParseScript is like ParseScript followed by NewFakeProvider, but panics
+// if script is invalid.
+func MustParseScript(script string) *FakeProvider {
+	steps, err := ParseScript(script)
+	if err != nil {
+		panic(err)
+	}
+	return NewFakeProvider(steps...)
+}
+
+func (f *FakeProvider) ask(predicate string, args ...int) (*bool, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Predicate: predicate, Args: args})
+	if f.next >= len(f.script) {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("%w: no step for call %s", ErrScriptExhausted, Call{Predicate: predicate, Args: args})
+	}
+	step := f.script[f.next]
+	f.next++
+	f.mu.Unlock()
+
+	time.Sleep(step.Delay)
+	if step.Result == nil {
+		return nil, step.Err
+	}
+	v := *step.Result
+	return &v, nil
+}
+
+// The predicate methods record the call and answer with the next step of the script.
+
+func (f *FakeProvider) IsEven(n int) (*bool, error)             { return f.ask(IsEven, n) }
+func (f *FakeProvider) IsOdd(n int) (*bool, error)              { return f.ask(IsOdd, n) }
+func (f *FakeProvider) AreEqual(a, b int) (*bool, error)        { return f.ask(AreEqual, a, b) }
+func (f *FakeProvider) AreNotEqual(a, b int) (*bool, error)     { return f.ask(AreNotEqual, a, b) }
+func (f *FakeProvider) IsGreaterThan(a, b int) (*bool, error)   { return f.ask(IsGreaterThan, a, b) }
+func (f *FakeProvider) IsLessThan(a, b int) (*bool, error)      { return f.ask(IsLessThan, a, b) }
+func (f *FakeProvider) IsLeapYear(year int) (*bool, error)      { return f.ask(IsLeapYear, year) }
+func (f *FakeProvider) IsPerfectNumber(n int) (*bool, error)    { return f.ask(IsPerfectNumber, n) }
+func (f *FakeProvider) IsHappyNumber(n int) (*bool, error)      { return f.ask(IsHappyNumber, n) }
+func (f *FakeProvider) IsTriangularNumber(n int) (*bool, error) { return f.ask(IsTriangularNumber, n) }
+func (f *FakeProvider) IsFactorOf(a, b int) (*bool, error)      { return f.ask(IsFactorOf, a, b) }
+func (f *FakeProvider) SumIsEven(a, b int) (*bool, error)       { return f.ask(SumIsEven, a, b) }
+func (f *FakeProvider) ProductIsEven(a, b int) (*bool, error)   { return f.ask(ProductIsEven, a, b) }
+
+// Close marks the provider as closed; see Closed.
+func (f *FakeProvider) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// Closed reports whether Close has been called.
+func (f *FakeProvider) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// Calls returns the calls received so far, in order.
+func (f *FakeProvider) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Remaining returns the number of steps not used yet.
+func (f *FakeProvider) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.script) - f.next
+}
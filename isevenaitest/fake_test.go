@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package isevenaitest
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseScript(t *testing.T) {
+	steps, err := ParseScript("true, 5ms+false, error:quota exceeded, nil, error")
+	if err != nil {
+		t.Fatalf("ParseScript failed: %v", err)
+	}
+	if len(steps) != 5 {
+		t.Fatalf("Expected 5 steps, got %d", len(steps))
+	}
+	if r := steps[0].Result; r == nil || !*r {
+		t.Errorf("Step 0 = %+v; want true", steps[0])
+	}
+	if r := steps[1].Result; r == nil || *r || steps[1].Delay != 5*time.Millisecond {
+		t.Errorf("Step 1 = %+v; want false after 5ms", steps[1])
+	}
+	if steps[2].Err == nil || steps[2].Err.Error() != "quota exceeded" {
+		t.Errorf("Step 2 = %+v; want error \"quota exceeded\"", steps[2])
+	}
+	if steps[3].Result != nil || steps[3].Err != nil {
+		t.Errorf("Step 3 = %+v; want undefined", steps[3])
+	}
+	if steps[4].Err == nil {
+		t.Errorf("Step 4 = %+v; want an error", steps[4])
+	}
+
+	for _, bad := range []string{"maybe", "soon+true", "true,,false"} {
+		if _, err := ParseScript(bad); err == nil {
+			t.Errorf("ParseScript(%q) succeeded; want error", bad)
+		}
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	errQuota := errors.New("quota")
+	f := NewFakeProvider(Fail(errQuota), Undefined(), Answer(true).After(10*time.Millisecond))
+
+	if _, err := f.IsEven(2); !errors.Is(err, errQuota) {
+		t.Errorf("Call 1 error = %v; want %v", err, errQuota)
+	}
+	if res, err := f.AreEqual(1, 1); res != nil || err != nil {
+		t.Errorf("Call 2 = %v, %v; want undefined", res, err)
+	}
+	start := time.Now()
+	if res, err := f.IsGreaterThan(3, 2); err != nil || res == nil || !*res {
+		t.Errorf("Call 3 = %v, %v; want true", res, err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("Call 3 did not honor the scripted delay")
+	}
+	if _, err := f.IsEven(2); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("Call 4 error = %v; want ErrScriptExhausted", err)
+	}
+
+	if f.Remaining() != 0 {
+		t.Errorf("Remaining() = %d; want 0", f.Remaining())
+	}
+	calls := f.Calls()
+	if len(calls) != 4 || calls[2].String() != "isGreaterThan[3 2]" {
+		t.Errorf("Unexpected calls: %v", calls)
+	}
+}
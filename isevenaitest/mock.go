@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package isevenaitest provides test doubles for code that depends on
+// is_even_ai.Provider, so it can be unit-tested without network access.
+package isevenaitest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Predicate names used to program and inspect a Mock. They match the prompt
+// names used by is_even_ai.IsEvenAiCore.
+const (
+	IsEven             = "isEven"
+	IsOdd              = "isOdd"
+	AreEqual           = "areEqual"
+	AreNotEqual        = "areNotEqual"
+	IsGreaterThan      = "isGreaterThan"
+	IsLessThan         = "isLessThan"
+	IsLeapYear         = "isLeapYear"
+	IsPerfectNumber    = "isPerfectNumber"
+	IsHappyNumber      = "isHappyNumber"
+	IsTriangularNumber = "isTriangularNumber"
+	IsFactorOf         = "isFactorOf"
+	SumIsEven          = "sumIsEven"
+	ProductIsEven      = "productIsEven"
+)
+
+// Call is a predicate call received by a Mock.
+type Call struct {
+	Predicate string
+	Args      []int
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s%v", c.Predicate, c.Args)
+}
+
+// Expectation is a programmed answer of a Mock, created by Mock.On.
+type Expectation struct {
+	predicate string
+	args      []int // nil matches any arguments
+	result    *bool
+	err       error
+}
+
+// Return makes matching calls answer v.
+func (e *Expectation) Return(v bool) {
+	e.result, e.err = &v, nil
+}
+
+// ReturnUndefined makes matching calls return an undefined (nil) answer.
+func (e *Expectation) ReturnUndefined() {
+	e.result, e.err = nil, nil
+}
+
+// ReturnError makes matching calls fail with err.
+func (e *Expectation) ReturnError(err error) {
+	e.result, e.err = nil, err
+}
+
+// Mock is a programmable is_even_ai.Provider. Program answers with On; calls
+// without a matching expectation fail with an error naming the call. Mock is
+// safe for concurrent use.
+type Mock struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	closed       bool
+}
+
+var _ is_even_ai.Provider = (*Mock)(nil)
+
+// NewMock returns a Mock without expectations.
+func NewMock() *Mock {
+	return &Mock{}
+}
+
+// On programs the answer for predicate called with args, e.g.
+// m.On(isevenaitest.IsEven, 4).Return(true). Without args, the expectation
+// matches any arguments. Later expectations take precedence over earlier ones.
+// The expectation answers undefined until one of its Return methods is called.
+func (m *Mock) On(predicate string, args ...int) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &Expectation{predicate: predicate}
+	if len(args) > 0 {
+		e.args = args
+	}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+func (m *Mock) ask(predicate string, args ...int) (*bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Predicate: predicate, Args: args})
+	for _, e := range slices.Backward(m.expectations) {
+		if e.predicate == predicate && (e.args == nil || slices.Equal(e.args, args)) {
+			if e.result == nil {
+				return nil, e.err
+			}
+			v := *e.result
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("isevenaitest: unexpected call %s", Call{Predicate: predicate, Args: args})
+}
+
+// The predicate methods record the call and return the programmed answer.
+
+func (m *Mock) IsEven(n int) (*bool, error)             { return m.ask(IsEven, n) }
+func (m *Mock) IsOdd(n int) (*bool, error)              { return m.ask(IsOdd, n) }
+func (m *Mock) AreEqual(a, b int) (*bool, error)        { return m.ask(AreEqual, a, b) }
+func (m *Mock) AreNotEqual(a, b int) (*bool, error)     { return m.ask(AreNotEqual, a, b) }
+func (m *Mock) IsGreaterThan(a, b int) (*bool, error)   { return m.ask(IsGreaterThan, a, b) }
+func (m *Mock) IsLessThan(a, b int) (*bool, error)      { return m.ask(IsLessThan, a, b) }
+func (m *Mock) IsLeapYear(year int) (*bool, error)      { return m.ask(IsLeapYear, year) }
+func (m *Mock) IsPerfectNumber(n int) (*bool, error)    { return m.ask(IsPerfectNumber, n) }
+func (m *Mock) IsHappyNumber(n int) (*bool, error)      { return m.ask(IsHappyNumber, n) }
+func (m *Mock) IsTriangularNumber(n int) (*bool, error) { return m.ask(IsTriangularNumber, n) }
+func (m *Mock) IsFactorOf(a, b int) (*bool, error)      { return m.ask(IsFactorOf, a, b) }
+func (m *Mock) SumIsEven(a, b int) (*bool, error)       { return m.ask(SumIsEven, a, b) }
+func (m *Mock) ProductIsEven(a, b int) (*bool, error)   { return m.ask(ProductIsEven, a, b) }
+
+// Close marks the mock as closed; see Closed.
+func (m *Mock) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+// Closed reports whether Close has been called.
+func (m *Mock) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// Calls returns the calls received so far, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.calls)
+}
+
+// CallCount returns how often predicate was called with args (with any
+// arguments if args is empty).
+func (m *Mock) CallCount(predicate string, args ...int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, c := range m.calls {
+		if c.Predicate == predicate && (len(args) == 0 || slices.Equal(c.Args, args)) {
+			count++
+		}
+	}
+	return count
+}
+
+// AssertCalled fails the test if predicate was never called with args (with
+// any arguments if args is empty).
+func (m *Mock) AssertCalled(t testing.TB, predicate string, args ...int) {
+	t.Helper()
+	if m.CallCount(predicate, args...) == 0 {
+		t.Errorf("isevenaitest: expected call %s, got calls %v", Call{Predicate: predicate, Args: args}, m.Calls())
+	}
+}
+
+// AssertNotCalled fails the test if predicate was called with args (with any
+// arguments if args is empty).
+func (m *Mock) AssertNotCalled(t testing.TB, predicate string, args ...int) {
+	t.Helper()
+	if n := m.CallCount(predicate, args...); n > 0 {
+		t.Errorf("isevenaitest: unexpected call %s (%d times)", Call{Predicate: predicate, Args: args}, n)
+	}
+}
+
+// AssertNumberOfCalls fails the test unless the mock received exactly n calls in total.
+func (m *Mock) AssertNumberOfCalls(t testing.TB, n int) {
+	t.Helper()
+	if calls := m.Calls(); len(calls) != n {
+		t.Errorf("isevenaitest: expected %d calls, got %d: %v", n, len(calls), calls)
+	}
+}
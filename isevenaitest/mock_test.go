@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package isevenaitest
+
+import (
+	"errors"
+	"testing"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func TestMock(t *testing.T) {
+	errBoom := errors.New("boom")
+	m := NewMock()
+	m.On(IsEven).Return(false)
+	m.On(IsEven, 4).Return(true)
+	m.On(IsEven, 7).ReturnUndefined()
+	m.On(AreEqual, 1, 2).ReturnError(errBoom)
+
+	if res, err := m.IsEven(4); err != nil || res == nil || !*res {
+		t.Errorf("IsEven(4) = %v, %v; want true", res, err)
+	}
+	if res, err := m.IsEven(3); err != nil || res == nil || *res {
+		t.Errorf("IsEven(3) = %v, %v; want false from the catch-all expectation", res, err)
+	}
+	if res, err := m.IsEven(7); err != nil || res != nil {
+		t.Errorf("IsEven(7) = %v, %v; want undefined", res, err)
+	}
+	if _, err := m.AreEqual(1, 2); !errors.Is(err, errBoom) {
+		t.Errorf("AreEqual(1, 2) error = %v; want %v", err, errBoom)
+	}
+	if _, err := m.IsOdd(1); err == nil {
+		t.Error("Expected error for a call without expectation")
+	}
+
+	m.AssertCalled(t, IsEven, 4)
+	m.AssertCalled(t, IsOdd)
+	m.AssertNotCalled(t, IsLessThan)
+	m.AssertNumberOfCalls(t, 5)
+	if n := m.CallCount(IsEven); n != 3 {
+		t.Errorf("CallCount(IsEven) = %d; want 3", n)
+	}
+
+	var p is_even_ai.Provider = m
+	_ = p.Close()
+	if !m.Closed() {
+		t.Error("Expected Closed() to report true after Close")
+	}
+}
+
+func TestMock_Assertions(t *testing.T) {
+	m := NewMock()
+	m.On(IsEven).Return(true)
+	_, _ = m.IsEven(2)
+
+	for name, assert := range map[string]func(t testing.TB){
+		"AssertCalled":        func(t testing.TB) { m.AssertCalled(t, IsEven, 3) },
+		"AssertNotCalled":     func(t testing.TB) { m.AssertNotCalled(t, IsEven) },
+		"AssertNumberOfCalls": func(t testing.TB) { m.AssertNumberOfCalls(t, 2) },
+	} {
+		rec := &recordingT{TB: t}
+		assert(rec)
+		if !rec.failed {
+			t.Errorf("%s did not fail the test", name)
+		}
+	}
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingT) Helper()                           {}
+func (r *recordingT) Errorf(format string, args ...any) { r.failed = true }
@@ -0,0 +1,298 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// defaultJobPollInterval is the default of JobQueue.PollInterval.
+const defaultJobPollInterval = 10 * time.Second
+
+// JobStatus is the state of a job in a JobQueue.
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+)
+
+// jobPredicates are the predicates that jobs may ask.
+var jobPredicates = []string{"isEven", "isOdd", "isPerfectNumber"}
+
+// Job is a batch of numbers to ask a one-number predicate about, with the
+// answers given so far.
+type Job struct {
+	ID        string
+	Predicate string // "isEven", "isOdd" or "isPerfectNumber"
+	Numbers   []int
+	Status    JobStatus
+	Results   []JobResult // In the order they were answered
+	Created   time.Time
+	Updated   time.Time // Last change of Status or Results
+}
+
+// JobResult is the answer to one number of a Job.
+type JobResult struct {
+	Index  int // Position of the number in Job.Numbers
+	Answer Answer
+	Err    string // Why the question failed, if it did
+}
+
+// Progress returns how many numbers of the job are answered, of how many.
+func (j Job) Progress() (done, total int) {
+	return len(j.Results), len(j.Numbers)
+}
+
+// JobStore persists the jobs of a JobQueue. The sqlitestore package provides
+// one backed by SQLite, which survives restarts; NewMemoryJobStore does not.
+// Implementations must be safe for concurrent use.
+type JobStore interface {
+	// AddJob stores a new job.
+	AddJob(ctx context.Context, job Job) error
+	// Job returns the job with the given ID and its results, or an error
+	// wrapping ErrJobNotFound.
+	Job(ctx context.Context, id string) (Job, error)
+	// AddJobResult stores the answer to one number of a job.
+	AddJobResult(ctx context.Context, id string, res JobResult) error
+	// SetJobStatus updates the status of a job.
+	SetJobStatus(ctx context.Context, id string, status JobStatus) error
+	// UnfinishedJobs returns the IDs of the jobs that are queued or running,
+	// oldest first.
+	UnfinishedJobs(ctx context.Context) ([]string, error)
+}
+
+// JobQueue works through batches of parity questions durably: jobs are
+// persisted when submitted, every answer is stored as it arrives, and jobs
+// left unfinished by a crash or restart are resumed where they stopped.
+// Submit jobs from anywhere and run the queue in the background:
+//
+//	store, err := sqlitestore.Open("jobs.db")
+//	...
+//	queue := is_even_ai.NewJobQueue(store, ai)
+//	go queue.Run(ctx)
+//	id, err := queue.Submit(ctx, "isEven", numbers)
+//	...
+//	job, err := queue.Job(ctx, id)
+//	done, total := job.Progress()
+//
+// Failed questions are stored with their error and not asked again.
+type JobQueue struct {
+	// Concurrency is the number of questions asked at once. Optional:
+	// defaults to 1.
+	Concurrency int
+	// PollInterval is how often Run checks the store for jobs submitted by
+	// other processes. Optional: defaults to 10 seconds.
+	PollInterval time.Duration
+
+	store    JobStore
+	provider Provider
+	wake     chan struct{} // Signals Run that a job was submitted
+	now      func() time.Time
+}
+
+// NewJobQueue creates a JobQueue keeping its jobs in store and asking
+// provider. Providers built on IsEvenAiCore are asked with the context of Run.
+func NewJobQueue(store JobStore, provider Provider) *JobQueue {
+	return &JobQueue{store: store, provider: provider, wake: make(chan struct{}, 1), now: time.Now}
+}
+
+// Submit persists a job asking predicate, "isEven", "isOdd" or
+// "isPerfectNumber", about each of numbers and returns its ID. Run answers it;
+// isPerfectNumber jobs need a provider with an IsPerfectNumber method, such as
+// IsEvenAiGemini.
+func (q *JobQueue) Submit(ctx context.Context, predicate string, numbers []int) (string, error) {
+	if !slices.Contains(jobPredicates, predicate) {
+		return "", fmt.Errorf("job predicate must be one of %v, got %q", jobPredicates, predicate)
+	}
+	if len(numbers) == 0 {
+		return "", fmt.Errorf("job needs at least one number")
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	now := q.now()
+	job := Job{ID: hex.EncodeToString(id), Predicate: predicate, Numbers: slices.Clone(numbers), Status: JobQueued, Created: now, Updated: now}
+	if err := q.store.AddJob(ctx, job); err != nil {
+		return "", fmt.Errorf("failed to submit job: %w", err)
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job.ID, nil
+}
+
+// Job returns the job with the given ID and its answers so far, or an error
+// wrapping ErrJobNotFound.
+func (q *JobQueue) Job(ctx context.Context, id string) (Job, error) {
+	return q.store.Job(ctx, id)
+}
+
+// Run answers the unfinished jobs in the store, oldest first, including those
+// interrupted earlier, and then waits for new ones until ctx is done. It
+// returns ctx.Err(), or the first error of the store.
+func (q *JobQueue) Run(ctx context.Context) error {
+	interval := q.PollInterval
+	if interval <= 0 {
+		interval = defaultJobPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ids, err := q.store.UnfinishedJobs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list unfinished jobs: %w", err)
+		}
+		for _, id := range ids {
+			if err := q.runJob(ctx, id); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob asks the questions of a job that are not answered yet.
+func (q *JobQueue) runJob(ctx context.Context, id string) error {
+	job, err := q.store.Job(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.store.SetJobStatus(ctx, id, JobRunning); err != nil {
+		return err
+	}
+	answered := make([]bool, len(job.Numbers))
+	for _, res := range job.Results {
+		answered[res.Index] = true
+	}
+	var indexes, numbers []int
+	for i, n := range job.Numbers {
+		if !answered[i] {
+			indexes = append(indexes, i)
+			numbers = append(numbers, n)
+		}
+	}
+
+	ask := func(ctx context.Context, n int) (*bool, error) {
+		var p predicateAsker = q.provider
+		if cp, ok := p.(contextProvider); ok {
+			p = cp.WithContext(ctx)
+		}
+		return askProvider(p, job.Predicate, []int{n})
+	}
+	var storeErr error
+	for res := range Batch(ctx, numbers, max(q.Concurrency, 1), ask) {
+		if res.Err != nil && ctx.Err() != nil {
+			continue // Canceled; asked again by the next Run
+		}
+		jr := JobResult{Index: indexes[res.Index], Answer: core.AnswerOf(res.Result)}
+		if res.Err != nil {
+			jr.Err = res.Err.Error()
+		}
+		// Keep answers that arrive while shutting down.
+		if err := q.store.AddJobResult(context.WithoutCancel(ctx), id, jr); err != nil && storeErr == nil {
+			storeErr = err
+		}
+	}
+	if storeErr != nil {
+		return fmt.Errorf("failed to store job result: %w", storeErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return q.store.SetJobStatus(ctx, id, JobDone)
+}
+
+// memoryJobStore is a JobStore in memory.
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	ids  []string // In order of submission
+	now  func() time.Time
+}
+
+// NewMemoryJobStore returns a JobStore that keeps jobs in memory, e.g. for
+// tests. Unlike the SQLite store of the sqlitestore package, it loses
+// unfinished jobs on restart.
+func NewMemoryJobStore() JobStore {
+	return &memoryJobStore{jobs: map[string]*Job{}, now: time.Now}
+}
+
+func (s *memoryJobStore) AddJob(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; ok {
+		return fmt.Errorf("job %s exists", job.ID)
+	}
+	job.Numbers = slices.Clone(job.Numbers)
+	job.Results = slices.Clone(job.Results)
+	s.jobs[job.ID] = &job
+	s.ids = append(s.ids, job.ID)
+	return nil
+}
+
+func (s *memoryJobStore) Job(_ context.Context, id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	j := *job
+	j.Numbers = slices.Clone(job.Numbers)
+	j.Results = slices.Clone(job.Results)
+	return j, nil
+}
+
+func (s *memoryJobStore) AddJobResult(_ context.Context, id string, res JobResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	job.Results = append(job.Results, res)
+	job.Updated = s.now()
+	return nil
+}
+
+func (s *memoryJobStore) SetJobStatus(_ context.Context, id string, status JobStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	job.Status = status
+	job.Updated = s.now()
+	return nil
+}
+
+func (s *memoryJobStore) UnfinishedJobs(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, id := range s.ids {
+		if s.jobs[id].Status != JobDone {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
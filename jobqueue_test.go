@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// waitJobDone polls queue until the job is done.
+func waitJobDone(t *testing.T, queue *JobQueue, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := queue.Job(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Job(%s) failed: %v", id, err)
+		}
+		if job.Status == JobDone {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still %s after 5s", id, job.Status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	queue := NewJobQueue(NewMemoryJobStore(), NewIsEvenAiLocal())
+	queue.Concurrency = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- queue.Run(ctx) }()
+
+	id, err := queue.Submit(ctx, "isOdd", []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Submit() failed: %v", err)
+	}
+	job := waitJobDone(t, queue, id)
+	if done, total := job.Progress(); done != 3 || total != 3 {
+		t.Errorf("Progress() = %d, %d; want 3, 3", done, total)
+	}
+	answers := make([]Answer, len(job.Numbers))
+	for _, res := range job.Results {
+		answers[res.Index] = res.Answer
+	}
+	if want := []Answer{AnswerTrue, AnswerFalse, AnswerTrue}; !slices.Equal(answers, want) {
+		t.Errorf("answers = %v, want %v", answers, want)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+func TestJobQueue_PerfectNumbers(t *testing.T) {
+	queue := NewJobQueue(NewMemoryJobStore(), NewIsEvenAiLocal())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+
+	id, err := queue.Submit(ctx, "isPerfectNumber", []int{6, 8, 28})
+	if err != nil {
+		t.Fatalf("Submit() failed: %v", err)
+	}
+	job := waitJobDone(t, queue, id)
+	answers := make([]Answer, len(job.Numbers))
+	for _, res := range job.Results {
+		answers[res.Index] = res.Answer
+	}
+	if want := []Answer{AnswerTrue, AnswerFalse, AnswerTrue}; !slices.Equal(answers, want) {
+		t.Errorf("answers = %v, want %v", answers, want)
+	}
+}
+
+func TestJobQueue_Resume(t *testing.T) {
+	// A job interrupted after answering its second number.
+	store := NewMemoryJobStore()
+	ctx := context.Background()
+	job := Job{ID: "interrupted", Predicate: "isEven", Numbers: []int{1, 2, 3}, Status: JobRunning, Results: []JobResult{{Index: 1, Answer: AnswerTrue}}}
+	if err := store.AddJob(ctx, job); err != nil {
+		t.Fatalf("AddJob() failed: %v", err)
+	}
+	stub := &stubProvider{answer: answerAlways(false)}
+	queue := NewJobQueue(store, stub)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+
+	job = waitJobDone(t, queue, "interrupted")
+	if len(job.Results) != 3 {
+		t.Errorf("Results = %+v, want 3", job.Results)
+	}
+	calls := stub.callLog()
+	slices.Sort(calls)
+	if want := []string{"isEven[1]", "isEven[3]"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestJobQueue_Errors(t *testing.T) {
+	stub := &stubProvider{answer: func(string, ...int) (*bool, error) { return nil, errors.New("quota exceeded") }}
+	queue := NewJobQueue(NewMemoryJobStore(), stub)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+
+	if _, err := queue.Submit(ctx, "isPrime", []int{1}); err == nil {
+		t.Error("Submit(isPrime) succeeded")
+	}
+	if _, err := queue.Submit(ctx, "isEven", nil); err == nil {
+		t.Error("Submit() without numbers succeeded")
+	}
+	if _, err := queue.Job(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Job(missing) = %v, want ErrJobNotFound", err)
+	}
+
+	id, err := queue.Submit(ctx, "isEven", []int{4})
+	if err != nil {
+		t.Fatalf("Submit() failed: %v", err)
+	}
+	job := waitJobDone(t, queue, id)
+	if len(job.Results) != 1 || job.Results[0].Err != "quota exceeded" || job.Results[0].Answer != AnswerUndefined {
+		t.Errorf("Results = %+v, want the error", job.Results)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"maps"
+)
+
+// Labels are arbitrary key-value pairs describing the caller of a query, e.g.
+// the team, feature or request ID, for attributing spending and errors.
+type Labels map[string]string
+
+type labelsKey struct{}
+
+// WithLabels returns a context whose queries carry labels, in addition to the
+// labels already in ctx. Labels set later replace earlier ones of the same
+// name:
+//
+//	ctx = is_even_ai.WithLabels(ctx, is_even_ai.Labels{"team": "payments", "feature": "checkout"})
+//	even, err := ai.WithContext(ctx).IsEven(n)
+//
+// The labels are recorded in AuditRecord.Labels, counted by Stats for
+// Stats.LabelNames, and added up by IsEvenAiGemini.UsageByLabel.
+func WithLabels(ctx context.Context, labels Labels) context.Context {
+	merged := maps.Clone(LabelsFromContext(ctx))
+	if merged == nil {
+		merged = Labels{}
+	}
+	maps.Copy(merged, labels)
+	return context.WithValue(ctx, labelsKey{}, merged)
+}
+
+// LabelsFromContext returns the labels set with WithLabels, or nil. The
+// result must not be modified.
+func LabelsFromContext(ctx context.Context) Labels {
+	labels, _ := ctx.Value(labelsKey{}).(Labels)
+	return labels
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestWithLabels(t *testing.T) {
+	if labels := LabelsFromContext(context.Background()); labels != nil {
+		t.Errorf("LabelsFromContext() without labels = %v, want nil", labels)
+	}
+	outer := WithLabels(context.Background(), Labels{"team": "a", "feature": "checkout"})
+	inner := WithLabels(outer, Labels{"team": "b", "request_id": "42"})
+	if got, want := LabelsFromContext(inner), (Labels{"team": "b", "feature": "checkout", "request_id": "42"}); !maps.Equal(got, want) {
+		t.Errorf("LabelsFromContext(inner) = %v, want %v", got, want)
+	}
+	if got, want := LabelsFromContext(outer), (Labels{"team": "a", "feature": "checkout"}); !maps.Equal(got, want) {
+		t.Errorf("LabelsFromContext(outer) = %v after adding labels, want %v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package lambda serves the is-even-ai predicates from AWS Lambda behind an
+// API Gateway proxy integration. A complete function is:
+//
+//	package main
+//
+//	import "github.com/philwo/is-even-ai/lambda"
+//
+//	func main() {
+//		lambda.Start(lambda.ConfigFromEnv())
+//	}
+//
+// Requests pass the number as path parameter or query parameter n, e.g.
+// GET /is-even/{n} or GET /?n=42, and optionally predicate=odd. The response
+// body is {"n":42,"answer":true}, with "answer": null for undefined answers.
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	awslambda "github.com/aws/aws-lambda-go/lambda"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// Config selects the provider a Handler uses.
+type Config struct {
+	Provider string // "gemini" (default) or "local"
+	Model    string // Provider's default if empty
+	APIKey   string // Gemini API key
+}
+
+// ConfigFromEnv reads a Config from the environment variables
+// IS_EVEN_AI_PROVIDER, IS_EVEN_AI_MODEL and GEMINI_API_KEY.
+func ConfigFromEnv() Config {
+	return Config{
+		Provider: os.Getenv("IS_EVEN_AI_PROVIDER"),
+		Model:    os.Getenv("IS_EVEN_AI_MODEL"),
+		APIKey:   os.Getenv("GEMINI_API_KEY"),
+	}
+}
+
+// Handler answers API Gateway proxy requests. The provider client is created
+// by the first invocation and reused by all later ones in the same execution
+// environment, so only cold starts pay for its setup.
+type Handler struct {
+	config Config
+
+	mu     sync.Mutex
+	core   *is_even_ai.IsEvenAiCore
+	closer io.Closer
+}
+
+// NewHandler returns a Handler for config.
+func NewHandler(config Config) *Handler {
+	return &Handler{config: config}
+}
+
+// Start runs a Handler for config as the Lambda function. It does not return.
+func Start(config Config) {
+	awslambda.Start(NewHandler(config).Handle)
+}
+
+// client returns the shared provider core, creating it if needed. A failed
+// creation is retried by the next invocation.
+func (h *Handler) client() (*is_even_ai.IsEvenAiCore, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.core != nil {
+		return h.core, nil
+	}
+	switch h.config.Provider {
+	case "", "gemini":
+		if h.config.APIKey == "" {
+			return nil, errors.New("GEMINI_API_KEY not set")
+		}
+		ai, err := is_even_ai.NewIsEvenAiGemini(
+			is_even_ai.GeminiClientOptions{APIKey: h.config.APIKey},
+			is_even_ai.GeminiModelOptions{Model: h.config.Model},
+		)
+		if err != nil {
+			return nil, err
+		}
+		h.core, h.closer = ai.IsEvenAiCore, ai
+	case "local":
+		local := is_even_ai.NewIsEvenAiLocal()
+		h.core, h.closer = local.IsEvenAiCore, local
+	default:
+		return nil, fmt.Errorf("unknown provider %q", h.config.Provider)
+	}
+	return h.core, nil
+}
+
+// Close releases the provider client, if one was created.
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closer == nil {
+		return nil
+	}
+	err := h.closer.Close()
+	h.core, h.closer = nil, nil
+	return err
+}
+
+type answerResponse struct {
+	N      int   `json:"n"`
+	Answer *bool `json:"answer"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handle answers one API Gateway proxy request. Failures are reported as
+// HTTP error responses rather than Lambda errors, so API Gateway passes them
+// on to the caller.
+func (h *Handler) Handle(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	s, ok := req.PathParameters["n"]
+	if !ok {
+		s = req.QueryStringParameters["n"]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return jsonResponse(http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid number %q", s)})
+	}
+	var ask func(c *is_even_ai.IsEvenAiCore, n int) (*bool, error)
+	switch req.QueryStringParameters["predicate"] {
+	case "", "even":
+		ask = (*is_even_ai.IsEvenAiCore).IsEven
+	case "odd":
+		ask = (*is_even_ai.IsEvenAiCore).IsOdd
+	default:
+		return jsonResponse(http.StatusBadRequest, errorResponse{"predicate must be even or odd"})
+	}
+
+	core, err := h.client()
+	if err != nil {
+		return jsonResponse(http.StatusInternalServerError, errorResponse{"failed to create client: " + err.Error()})
+	}
+	answer, err := ask(core.WithContext(ctx), n)
+	if err != nil {
+		return jsonResponse(http.StatusBadGateway, errorResponse{err.Error()})
+	}
+	return jsonResponse(http.StatusOK, answerResponse{N: n, Answer: answer})
+}
+
+func jsonResponse(status int, v any) (events.APIGatewayProxyResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package lambda
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(Config{Provider: "local"})
+	defer h.Close()
+	testCases := []struct {
+		name       string
+		req        events.APIGatewayProxyRequest
+		wantStatus int
+		wantBody   string
+	}{
+		{"PathParameter", events.APIGatewayProxyRequest{PathParameters: map[string]string{"n": "42"}}, http.StatusOK, `{"n":42,"answer":true}`},
+		{"QueryParameter", events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"n": "7"}}, http.StatusOK, `{"n":7,"answer":false}`},
+		{"Odd", events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"n": "7", "predicate": "odd"}}, http.StatusOK, `{"n":7,"answer":true}`},
+		{"Missing", events.APIGatewayProxyRequest{}, http.StatusBadRequest, `{"error":"invalid number \"\""}`},
+		{"UnknownPredicate", events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"n": "7", "predicate": "prime"}}, http.StatusBadRequest, `{"error":"predicate must be even or odd"}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := h.Handle(context.Background(), tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.wantStatus || resp.Body != tc.wantBody {
+				t.Errorf("Got %d %s; want %d %s", resp.StatusCode, resp.Body, tc.wantStatus, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandler_ReusesClient(t *testing.T) {
+	h := NewHandler(Config{Provider: "local"})
+	defer h.Close()
+	first, err := h.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := h.client(); second != first {
+		t.Error("Expected the client to be reused across invocations")
+	}
+}
+
+func TestHandler_ClientError(t *testing.T) {
+	h := NewHandler(Config{Provider: "gemini"}) // No API key
+	resp, err := h.Handle(context.Background(), events.APIGatewayProxyRequest{PathParameters: map[string]string{"n": "2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected 500 without an API key, got %d %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("IS_EVEN_AI_PROVIDER", "local")
+	t.Setenv("IS_EVEN_AI_MODEL", "some-model")
+	t.Setenv("GEMINI_API_KEY", "key")
+	if got, want := ConfigFromEnv(), (Config{Provider: "local", Model: "some-model", APIKey: "key"}); got != want {
+		t.Errorf("ConfigFromEnv() = %+v; want %+v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultLatencyWindow is the default of LatencyMonitor.Window.
+const defaultLatencyWindow = 100
+
+// LatencyMonitor tracks the rolling p50, p95 and p99 latency of queries per
+// provider and predicate, and alerts when one of them breaches an SLO, so a
+// provider slowing down is noticed before customers do. Enable it with
+// Middleware:
+//
+//	monitor := &is_even_ai.LatencyMonitor{
+//		SLOs: []is_even_ai.LatencySLO{{Percentile: 0.99, Max: 2 * time.Second}},
+//		OnBreach: func(b is_even_ai.SLOBreach) { page("%s", b) },
+//	}
+//	ai.Use(is_even_ai.CacheMiddleware(cache), monitor.Middleware())
+//
+// Put it after caches, whose hits would otherwise hide slow answers. Failed
+// queries count as well, as timeouts are the slowest of all. Prompts without
+// CallInfo are not tracked. The zero value tracks latency but never alerts.
+// LatencyMonitor is safe for concurrent use.
+type LatencyMonitor struct {
+	// Window is the number of recent queries per provider and predicate the
+	// percentiles are computed over. Defaults to 100.
+	Window int
+	// SLOs are the objectives checked after every query.
+	SLOs []LatencySLO
+	// OnBreach is called when a full window of queries breaches an SLO. It is
+	// not called again for the same provider and predicate until the SLO is
+	// met again. It runs synchronously in the query that breached the SLO, so
+	// it should not block for long.
+	OnBreach func(SLOBreach)
+
+	mu     sync.Mutex
+	series map[latencyKey]*latencySeries
+}
+
+// LatencySLO is a latency objective, e.g. a p99 of at most two seconds.
+type LatencySLO struct {
+	// Provider and Predicate restrict the SLO to queries of one provider,
+	// e.g. "gemini", or about one predicate, e.g. "isEven". Empty matches all.
+	Provider  string
+	Predicate string
+	// Percentile is the percentile the objective is about, between 0 and 1,
+	// e.g. 0.99.
+	Percentile float64
+	// Max is the highest latency allowed at Percentile.
+	Max time.Duration
+}
+
+// SLOBreach describes the queries that triggered LatencyMonitor.OnBreach.
+type SLOBreach struct {
+	SLO       LatencySLO
+	Provider  string
+	Predicate string
+	Latency   time.Duration // Latency at SLO.Percentile over the window
+	Window    int
+	Last      CallInfo // The query that breached the SLO
+}
+
+func (b SLOBreach) String() string {
+	return fmt.Sprintf("p%g latency of %s %s is %v, above %v", 100*b.SLO.Percentile, b.Provider, b.Predicate, b.Latency, b.SLO.Max)
+}
+
+// LatencyStats are the percentiles of a provider and predicate tracked by a
+// LatencyMonitor.
+type LatencyStats struct {
+	Provider  string        `json:"provider"`
+	Predicate string        `json:"predicate"`
+	Count     int64         `json:"count"` // Queries overall
+	P50       time.Duration `json:"p50"`   // Over the current window
+	P95       time.Duration `json:"p95"`
+	P99       time.Duration `json:"p99"`
+}
+
+type latencyKey struct {
+	provider, predicate string
+}
+
+// latencySeries holds the recent latencies of one provider and predicate.
+type latencySeries struct {
+	recent   []time.Duration // Ring buffer of the last Window latencies
+	next     int             // Position of the next latency in recent
+	count    int64
+	breached map[int]bool // Indexes of the SLOs currently breached
+}
+
+// percentile returns the latency at p, between 0 and 1, of the window using
+// the nearest-rank method.
+func (s *latencySeries) percentile(p float64) time.Duration {
+	sorted := slices.Sorted(slices.Values(s.recent))
+	i := int(p*float64(len(sorted))+0.999999) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// Middleware times every query passing through it.
+func (m *LatencyMonitor) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			start := time.Now()
+			res, err := next(ctx, prompt)
+			if info, ok := CallInfoFromContext(ctx); ok {
+				m.observe(info, time.Since(start))
+			}
+			return res, err
+		}
+	}
+}
+
+// observe adds the latency of one query and calls OnBreach for the SLOs it
+// breached.
+func (m *LatencyMonitor) observe(info CallInfo, latency time.Duration) {
+	key := latencyKey{provider: info.Source.Provider, predicate: info.Predicate}
+	m.mu.Lock()
+	if m.series == nil {
+		m.series = map[latencyKey]*latencySeries{}
+	}
+	s, ok := m.series[key]
+	if !ok {
+		window := m.Window
+		if window <= 0 {
+			window = defaultLatencyWindow
+		}
+		s = &latencySeries{recent: make([]time.Duration, 0, window), breached: map[int]bool{}}
+		m.series[key] = s
+	}
+	if len(s.recent) < cap(s.recent) {
+		s.recent = append(s.recent, latency)
+	} else {
+		s.recent[s.next] = latency
+	}
+	s.next = (s.next + 1) % cap(s.recent)
+	s.count++
+
+	var breaches []SLOBreach
+	for i, slo := range m.SLOs {
+		if (slo.Provider != "" && slo.Provider != key.provider) || (slo.Predicate != "" && slo.Predicate != key.predicate) {
+			continue
+		}
+		p := s.percentile(slo.Percentile)
+		switch {
+		case p <= slo.Max:
+			s.breached[i] = false
+		case !s.breached[i] && len(s.recent) == cap(s.recent):
+			s.breached[i] = true
+			breaches = append(breaches, SLOBreach{SLO: slo, Provider: key.provider, Predicate: key.predicate, Latency: p, Window: len(s.recent), Last: info})
+		}
+	}
+	m.mu.Unlock()
+
+	if m.OnBreach != nil {
+		for _, b := range breaches {
+			m.OnBreach(b)
+		}
+	}
+}
+
+// Stats returns the latency percentiles of every provider and predicate seen,
+// sorted by provider and predicate.
+func (m *LatencyMonitor) Stats() []LatencyStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make([]LatencyStats, 0, len(m.series))
+	for key, s := range m.series {
+		stats = append(stats, LatencyStats{
+			Provider:  key.provider,
+			Predicate: key.predicate,
+			Count:     s.count,
+			P50:       s.percentile(0.5),
+			P95:       s.percentile(0.95),
+			P99:       s.percentile(0.99),
+		})
+	}
+	slices.SortFunc(stats, func(a, b LatencyStats) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Predicate, b.Predicate))
+	})
+	return stats
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatencyMonitor(t *testing.T) {
+	// The model is slow to answer about odd numbers.
+	core := newIsEvenAiCore(DefaultGeminiPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		if info, _ := CallInfoFromContext(ctx); info.Args[0]%2 != 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		yes := true
+		return &yes, nil
+	})
+	var breaches []SLOBreach
+	monitor := &LatencyMonitor{
+		Window: 4,
+		SLOs: []LatencySLO{
+			{Predicate: "isEven", Percentile: 0.75, Max: 10 * time.Millisecond},
+			{Predicate: "isOdd", Percentile: 0.5, Max: 10 * time.Millisecond},
+		},
+		OnBreach: func(b SLOBreach) { breaches = append(breaches, b) },
+	}
+	core.Use(monitor.Middleware())
+
+	for _, n := range []int{2, 4, 1, 6} { // p75 fast
+		_, _ = core.IsEven(n)
+	}
+	if len(breaches) != 0 {
+		t.Fatalf("Expected no breach within the SLO, got %v", breaches)
+	}
+	_, _ = core.IsEven(3) // 2 of the last 4 slow
+	if len(breaches) != 1 || breaches[0].Predicate != "isEven" || breaches[0].Latency < 20*time.Millisecond || breaches[0].Last.Args[0] != 3 {
+		t.Fatalf("Expected one breach at 2 of 4 slow, got %+v", breaches)
+	}
+	_, _ = core.IsEven(5) // Still breached
+	if len(breaches) != 1 {
+		t.Errorf("Expected no repeated breach, got %d", len(breaches))
+	}
+	for _, n := range []int{8, 10, 12} { // Recovers
+		_, _ = core.IsEven(n)
+	}
+	_, _ = core.IsEven(7)
+	_, _ = core.IsEven(9)
+	if len(breaches) != 2 {
+		t.Errorf("Expected a new breach after recovering, got %d", len(breaches))
+	}
+
+	_, _ = core.IsOdd(1) // Tracked separately; the window is not full yet
+	stats := monitor.Stats()
+	if len(stats) != 2 || stats[0].Predicate != "isEven" || stats[0].Count != 11 || stats[1].Predicate != "isOdd" || stats[1].Count != 1 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+	if s := stats[0]; s.P50 >= 10*time.Millisecond || s.P99 < 20*time.Millisecond {
+		t.Errorf("Unexpected percentiles %+v", s)
+	}
+	if len(breaches) != 2 {
+		t.Errorf("Expected no breach before the window is full, got %d", len(breaches))
+	}
+}
+
+func TestLatencySeriesPercentile(t *testing.T) {
+	s := &latencySeries{recent: []time.Duration{5, 1, 4, 2, 3}}
+	for p, want := range map[float64]time.Duration{0: 1, 0.5: 3, 0.95: 5, 1: 5} {
+		if got := s.percentile(p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// localModel is the model name IsEvenAiLocal reports in CallMetadata.
+const localModel = "local"
+
+// LocalOptions configures IsEvenAiLocal.
+type LocalOptions struct {
+	// Latency, if set, delays every answer to mimic a remote model.
+	Latency time.Duration
+}
+
+// IsEvenAiLocal is an implementation of IsEvenAiCore that computes answers
+// arithmetically without any network I/O. It is intended for CI and local
+// development where no AI is available. Prompts are built exactly as for
+// Gemini, so prompt construction is still exercised.
+type IsEvenAiLocal struct {
+	*IsEvenAiCore
+	latency time.Duration
+}
+
+var _ Provider = (*IsEvenAiLocal)(nil)
+
+// NewIsEvenAiLocal creates a new IsEvenAiLocal.
+func NewIsEvenAiLocal(opts ...LocalOptions) *IsEvenAiLocal {
+	l := &IsEvenAiLocal{}
+	if len(opts) > 0 {
+		l.latency = opts[0].Latency
+	}
+	l.IsEvenAiCore = core.NewIsEvenAiCoreWithHandler(DefaultGeminiPromptTemplates, l.query, AnswerSource{Provider: "local", Model: localModel})
+	return l
+}
+
+// query answers the question described by the call info in ctx; the prompt itself is ignored.
+func (l *IsEvenAiLocal) query(ctx context.Context, prompt string) (*bool, error) {
+	if l.latency > 0 {
+		timer := time.NewTimer(l.latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	info, ok := CallInfoFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("local provider cannot answer free-form prompt %q", prompt)
+	}
+	answer, err := core.GroundTruth(info.Predicate, info.Args)
+	if err != nil {
+		return nil, fmt.Errorf("local provider: %w", err)
+	}
+	core.CallMetadataFromContext(ctx).Record(localModel, 0, 0, 0)
+	if onToken := core.OnTokenFromContext(ctx); onToken != nil {
+		onToken(strconv.FormatBool(answer))
+	}
+	return &answer, nil
+}
+
+// Close is a no-op; IsEvenAiLocal holds no resources.
+func (l *IsEvenAiLocal) Close() error {
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIsEvenAiLocal(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	defer l.Close()
+
+	for _, n := range []int{-3, -2, 0, 1, 42} {
+		res, err := l.IsEven(n)
+		checkGeminiResult(t, res, err, n%2 == 0, "IsEven", n)
+		res, err = l.IsOdd(n)
+		checkGeminiResult(t, res, err, n%2 != 0, "IsOdd", n)
+	}
+	for _, pair := range [][2]int{{1, 2}, {2, 1}, {3, 3}} {
+		a, b := pair[0], pair[1]
+		res, err := l.AreEqual(a, b)
+		checkGeminiResult(t, res, err, a == b, "AreEqual", a, b)
+		res, err = l.AreNotEqual(a, b)
+		checkGeminiResult(t, res, err, a != b, "AreNotEqual", a, b)
+		res, err = l.IsGreaterThan(a, b)
+		checkGeminiResult(t, res, err, a > b, "IsGreaterThan", a, b)
+		res, err = l.IsLessThan(a, b)
+		checkGeminiResult(t, res, err, a < b, "IsLessThan", a, b)
+	}
+	for year, want := range map[int]bool{1900: false, 2000: true, 2023: false, 2024: true} {
+		res, err := l.IsLeapYear(year)
+		checkGeminiResult(t, res, err, want, "IsLeapYear", year)
+	}
+	for n, want := range map[int]bool{-6: false, 1: false, 6: true, 12: false, 28: true, 496: true, 8128: true} {
+		res, err := l.IsPerfectNumber(n)
+		checkGeminiResult(t, res, err, want, "IsPerfectNumber", n)
+	}
+	for n, want := range map[int]bool{-7: false, 0: false, 1: true, 2: false, 7: true, 19: true, 20: false, 100: true} {
+		res, err := l.IsHappyNumber(n)
+		checkGeminiResult(t, res, err, want, "IsHappyNumber", n)
+	}
+	for n, want := range map[int]bool{-1: false, 0: true, 1: true, 2: false, 3: true, 9: false, 10: true, 5050: true, 5051: false} {
+		res, err := l.IsTriangularNumber(n)
+		checkGeminiResult(t, res, err, want, "IsTriangularNumber", n)
+	}
+	for _, tc := range []struct {
+		a, b int
+		want bool
+	}{{3, 12, true}, {12, 3, false}, {-4, 12, true}, {5, 0, true}, {0, 5, false}, {0, 0, true}} {
+		res, err := l.IsFactorOf(tc.a, tc.b)
+		checkGeminiResult(t, res, err, tc.want, "IsFactorOf", tc.a, tc.b)
+	}
+	for _, pair := range [][2]int{{1, 3}, {1, 2}, {2, 4}, {-3, 4}} {
+		a, b := pair[0], pair[1]
+		res, err := l.SumIsEven(a, b)
+		checkGeminiResult(t, res, err, (a+b)%2 == 0, "SumIsEven", a, b)
+		res, err = l.ProductIsEven(a, b)
+		checkGeminiResult(t, res, err, (a*b)%2 == 0, "ProductIsEven", a, b)
+	}
+}
+
+func TestIsEvenAiLocal_Latency(t *testing.T) {
+	l := NewIsEvenAiLocal(LocalOptions{Latency: 20 * time.Millisecond})
+	start := time.Now()
+	res, err := l.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected at least 20ms of artificial latency, got %v", elapsed)
+	}
+}
+
+func TestIsEvenAiLocal_OnToken(t *testing.T) {
+	var chunks []string
+	ctx := WithOnToken(context.Background(), func(chunk string) { chunks = append(chunks, chunk) })
+	res, err := NewIsEvenAiLocal().WithContext(ctx).IsOdd(7)
+	checkGeminiResult(t, res, err, true, "IsOdd", 7)
+	if len(chunks) != 1 || chunks[0] != "true" {
+		t.Errorf("Streamed chunks = %q, want the answer", chunks)
+	}
+}
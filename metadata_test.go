@@ -0,0 +1,21 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCallMetadata_Local(t *testing.T) {
+	ctx, md := WithCallMetadata(context.Background())
+	if _, err := NewIsEvenAiLocal().WithContext(ctx).IsOdd(3); err != nil {
+		t.Fatal(err)
+	}
+	if md.Model != localModel || md.Requests != 1 {
+		t.Errorf("Unexpected metadata %+v", *md)
+	}
+}
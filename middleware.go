@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// RetryPolicy decides whether a query is asked again. Implement it for
+// policies RetryMiddleware does not cover, e.g. to never retry invalid
+// requests, or to add jitter to the backoff.
+type RetryPolicy interface {
+	// ShouldRetry is called after the attempt'th attempt (starting at 1) failed
+	// with err or, if err is nil, answered undefined (res is nil). It returns
+	// how long to wait before the next attempt, and whether to make one.
+	ShouldRetry(attempt int, err error, res *bool) (time.Duration, bool)
+}
+
+// ExponentialBackoff is the RetryPolicy of RetryMiddleware: it retries failed
+// queries up to Attempts times in total, waiting Backoff before the first retry
+// and doubling the wait after each one, or the delay suggested by the provider
+// if that is longer. Undefined answers are not retried, and neither are errors
+// another attempt cannot fix, such as ErrBudgetExceeded (see IsRetryable).
+type ExponentialBackoff struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+// ShouldRetry implements RetryPolicy.
+func (b ExponentialBackoff) ShouldRetry(attempt int, err error, _ *bool) (time.Duration, bool) {
+	if !IsRetryable(err) || attempt >= b.Attempts {
+		return 0, false
+	}
+	wait := b.Backoff << (attempt - 1)
+	var perr *ProviderError
+	if errors.As(err, &perr) {
+		wait = max(wait, perr.RetryAfter)
+	}
+	return wait, true
+}
+
+// RetryMiddleware retries failed queries up to attempts times in total, waiting
+// backoff before the first retry and doubling the wait after each one; see
+// ExponentialBackoff and RetryMiddlewareWithPolicy.
+func RetryMiddleware(attempts int, backoff time.Duration) Middleware {
+	return RetryMiddlewareWithPolicy(ExponentialBackoff{Attempts: attempts, Backoff: backoff})
+}
+
+// RetryMiddlewareWithPolicy asks failed or undefined queries again as long as
+// policy says so. Queries are never retried once their context is done.
+//
+// If the query's context has a deadline, a retry is only made if the wait plus
+// the duration of the slowest attempt so far fits before it. Once a retry was
+// made or skipped for lack of time, the last error is returned annotated with
+// the number of attempts made.
+func RetryMiddlewareWithPolicy(policy RetryPolicy) Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			var slowest time.Duration
+			for attempt := 1; ; attempt++ {
+				start := time.Now()
+				res, err := next(ctx, prompt)
+				slowest = max(slowest, time.Since(start))
+				if (err == nil && res != nil) || ctx.Err() != nil {
+					return res, err
+				}
+				wait, retry := policy.ShouldRetry(attempt, err, res)
+				if !retry {
+					if attempt > 1 && err != nil {
+						err = retriesFailed(err, attempt)
+					}
+					return res, err
+				}
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait+slowest {
+					if err != nil {
+						err = retriesFailed(err, attempt)
+					}
+					return res, err
+				}
+				info, _ := CallInfoFromContext(ctx)
+				eventsFromContext(ctx).RetryScheduled(ctx, info, attempt, wait, err)
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					if err != nil {
+						err = retriesFailed(err, attempt)
+					}
+					return nil, errors.Join(err, ctx.Err())
+				}
+			}
+		}
+	}
+}
+
+// retriesFailed annotates the last error of a retried query with the number
+// of attempts made.
+func retriesFailed(err error, attempts int) error {
+	if attempts == 1 {
+		return fmt.Errorf("gave up after 1 attempt: %w", err)
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", attempts, err)
+}
+
+// LoggingMiddleware logs every query with its predicate, arguments, answer,
+// error and latency at debug level, or at warn level if it failed.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			start := time.Now()
+			res, err := next(ctx, prompt)
+			info, _ := CallInfoFromContext(ctx)
+			attrs := []any{"predicate", info.Predicate, "args", info.Args, "latency", time.Since(start)}
+			if err != nil {
+				logger.Warn("query failed", append(attrs, "err", err)...)
+			} else {
+				logger.Debug("query answered", append(attrs, "answer", core.AnswerOf(res))...)
+			}
+			return res, err
+		}
+	}
+}
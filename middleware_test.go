@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingHandler returns a QueryHandler that answers from results in turn
+// (repeating the last one) and counts its calls.
+func countingHandler(calls *int, results ...func() (*bool, error)) QueryHandler {
+	return func(context.Context, string) (*bool, error) {
+		i := min(*calls, len(results)-1)
+		*calls++
+		return results[i]()
+	}
+}
+
+func answered(v bool) func() (*bool, error)  { return func() (*bool, error) { return &v, nil } }
+func failed(err error) func() (*bool, error) { return func() (*bool, error) { return nil, err } }
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next QueryHandler) QueryHandler {
+			return func(ctx context.Context, prompt string) (*bool, error) {
+				order = append(order, name)
+				return next(ctx, prompt)
+			}
+		}
+	}
+	core := newIsEvenAiCore(testPromptTemplates, func(context.Context, string) (*bool, error) {
+		order = append(order, "handler")
+		return nil, nil
+	})
+	core.Use(tag("a"), tag("b"))
+	core.Use(tag("c"))
+
+	_, _ = core.IsEven(2)
+	if got := strings.Join(order, ","); got != "a,b,c,handler" {
+		t.Errorf("Middleware ran in order %s; want a,b,c,handler", got)
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	errTransient := errors.New("transient")
+
+	t.Run("RetriesUntilSuccess", func(t *testing.T) {
+		calls := 0
+		h := RetryMiddleware(3, time.Millisecond)(countingHandler(&calls, failed(errTransient), failed(errTransient), answered(true)))
+		res, err := h(context.Background(), "p")
+		checkGeminiResult(t, res, err, true, "retried query")
+		if calls != 3 {
+			t.Errorf("Expected 3 attempts, got %d", calls)
+		}
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		calls := 0
+		h := RetryMiddleware(2, time.Millisecond)(countingHandler(&calls, failed(errTransient)))
+		if _, err := h(context.Background(), "p"); !errors.Is(err, errTransient) {
+			t.Errorf("Expected last error after giving up, got %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls)
+		}
+	})
+
+	t.Run("DoesNotRetryBudgetOrUndefined", func(t *testing.T) {
+		for _, result := range []func() (*bool, error){failed(ErrBudgetExceeded), func() (*bool, error) { return nil, nil }} {
+			calls := 0
+			h := RetryMiddleware(3, time.Millisecond)(countingHandler(&calls, result))
+			_, _ = h(context.Background(), "p")
+			if calls != 1 {
+				t.Errorf("Expected a single attempt, got %d", calls)
+			}
+		}
+	})
+
+	t.Run("StopsOnCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		h := RetryMiddleware(3, time.Hour)(countingHandler(&calls, failed(errTransient)))
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := h(ctx, "p"); !errors.Is(err, context.Canceled) || !errors.Is(err, errTransient) {
+			t.Errorf("Expected cancellation and the last error, got %v", err)
+		}
+	})
+
+	t.Run("SkipsRetriesPastDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		calls := 0
+		h := RetryMiddleware(3, time.Minute)(countingHandler(&calls, failed(errTransient)))
+		start := time.Now()
+		_, err := h(ctx, "p")
+		if !errors.Is(err, errTransient) || !strings.Contains(err.Error(), "gave up after 1 attempt") {
+			t.Errorf("Expected the last error with the attempts made, got %v", err)
+		}
+		if calls != 1 || time.Since(start) > 500*time.Millisecond {
+			t.Errorf("Expected a single attempt without waiting, got %d attempts in %v", calls, time.Since(start))
+		}
+	})
+
+	t.Run("RetriesWithinDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		calls := 0
+		h := RetryMiddleware(3, time.Millisecond)(countingHandler(&calls, failed(errTransient)))
+		if _, err := h(ctx, "p"); err == nil || !strings.Contains(err.Error(), "gave up after 3 attempts") || calls != 3 {
+			t.Errorf("Expected 3 attempts, got %d and %v", calls, err)
+		}
+	})
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	core := NewIsEvenAiLocal()
+	core.Use(LoggingMiddleware(logger))
+
+	_, _ = core.IsGreaterThan(3, 2)
+	if out := buf.String(); !strings.Contains(out, `msg="query answered" predicate=isGreaterThan args="[3 2]"`) || !strings.Contains(out, "answer=true") {
+		t.Errorf("Unexpected log output: %s", out)
+	}
+}
+
+// retryUndefined is a RetryPolicy that retries undefined answers but no errors.
+type retryUndefined struct{ attempts []int }
+
+func (p *retryUndefined) ShouldRetry(attempt int, err error, res *bool) (time.Duration, bool) {
+	p.attempts = append(p.attempts, attempt)
+	return time.Millisecond, err == nil && attempt < 3
+}
+
+func TestRetryMiddlewareWithPolicy(t *testing.T) {
+	undefined := func() (*bool, error) { return nil, nil }
+
+	calls := 0
+	policy := &retryUndefined{}
+	h := RetryMiddlewareWithPolicy(policy)(countingHandler(&calls, undefined, undefined, answered(false)))
+	res, err := h(context.Background(), "p")
+	checkGeminiResult(t, res, err, false, "query retried while undefined")
+	if calls != 3 || !slices.Equal(policy.attempts, []int{1, 2}) {
+		t.Errorf("Expected 3 attempts and 2 policy decisions, got %d and %v", calls, policy.attempts)
+	}
+
+	calls = 0
+	policy = &retryUndefined{}
+	h = RetryMiddlewareWithPolicy(policy)(countingHandler(&calls, failed(errors.New("bad request"))))
+	if _, err := h(context.Background(), "p"); err == nil || err.Error() != "bad request" || calls != 1 {
+		t.Errorf("Expected the unannotated error of a single attempt, got %v after %d attempts", err, calls)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{Attempts: 4, Backoff: time.Second}
+	errTransient := errors.New("transient")
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		if wait, ok := b.ShouldRetry(attempt, errTransient, nil); !ok || wait != want {
+			t.Errorf("ShouldRetry(%d) = %v, %v; want %v, true", attempt, wait, ok, want)
+		}
+	}
+	if _, ok := b.ShouldRetry(4, errTransient, nil); ok {
+		t.Error("ShouldRetry() retries beyond Attempts")
+	}
+	if _, ok := b.ShouldRetry(1, fmt.Errorf("wrapped: %w", ErrBudgetExceeded), nil); ok {
+		t.Error("ShouldRetry() retries ErrBudgetExceeded")
+	}
+	if _, ok := b.ShouldRetry(1, nil, nil); ok {
+		t.Error("ShouldRetry() retries undefined answers")
+	}
+	if _, ok := b.ShouldRetry(1, &ProviderError{Kind: ErrInvalidRequest, Err: errTransient}, nil); ok {
+		t.Error("ShouldRetry() retries non-retryable provider errors")
+	}
+	if wait, ok := b.ShouldRetry(1, &ProviderError{Kind: ErrRateLimited, Retryable: true, RetryAfter: time.Minute, Err: errTransient}, nil); !ok || wait != time.Minute {
+		t.Errorf("ShouldRetry() with RetryAfter = %v, %v; want 1m, true", wait, ok)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"maps"
+	"sync"
+)
+
+// Gemini models known to this package, e.g. in DefaultPriceTable.
+const (
+	ModelGeminiFlashLite    = "gemini-2.0-flash-lite"
+	ModelGeminiFlash        = "gemini-2.0-flash"
+	ModelGemini15Flash      = "gemini-1.5-flash"
+	ModelGemini15Flash8B    = "gemini-1.5-flash-8b"
+	ModelGemini15Pro        = "gemini-1.5-pro"
+	ModelGeminiEmbedding004 = "text-embedding-004"
+
+	// DefaultGeminiModel is used if GeminiModelOptions.Model is empty.
+	DefaultGeminiModel = ModelGeminiFlashLite
+)
+
+var (
+	modelAliasesMu sync.RWMutex
+	modelAliases   = map[string]string{
+		"cheap":    ModelGeminiFlashLite,
+		"fast":     ModelGeminiFlashLite,
+		"accurate": ModelGemini15Pro,
+	}
+)
+
+// SetModelAlias makes the constructors, e.g. NewIsEvenAiGemini, use model
+// when asked for alias, so configuration can name a model by its purpose,
+// such as "cheap" or "accurate", and model churn is handled in one place. An
+// empty model removes the alias. It affects clients created afterwards.
+func SetModelAlias(alias, model string) {
+	modelAliasesMu.Lock()
+	defer modelAliasesMu.Unlock()
+	if model == "" {
+		delete(modelAliases, alias)
+	} else {
+		modelAliases[alias] = model
+	}
+}
+
+// ModelAliases returns a copy of the current aliases. By default, "cheap" and
+// "fast" map to ModelGeminiFlashLite, and "accurate" to ModelGemini15Pro.
+func ModelAliases() map[string]string {
+	modelAliasesMu.RLock()
+	defer modelAliasesMu.RUnlock()
+	return maps.Clone(modelAliases)
+}
+
+// ResolveModel returns the model an alias stands for, or name itself if it is
+// not an alias.
+func ResolveModel(name string) string {
+	modelAliasesMu.RLock()
+	defer modelAliasesMu.RUnlock()
+	if model, ok := modelAliases[name]; ok {
+		return model
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestModelAliases(t *testing.T) {
+	t.Cleanup(func() { SetModelAlias("cheap", ModelGeminiFlashLite); SetModelAlias("legacy", "") })
+	if got := ResolveModel("accurate"); got != ModelGemini15Pro {
+		t.Errorf("ResolveModel(accurate) = %q, want %q", got, ModelGemini15Pro)
+	}
+	if got := ResolveModel("some-model"); got != "some-model" {
+		t.Errorf("ResolveModel(some-model) = %q, want it unchanged", got)
+	}
+
+	SetModelAlias("cheap", ModelGemini15Flash8B)
+	SetModelAlias("legacy", ModelGemini15Flash)
+	aliases := ModelAliases()
+	if aliases["cheap"] != ModelGemini15Flash8B || aliases["legacy"] != ModelGemini15Flash {
+		t.Errorf("ModelAliases() = %v", aliases)
+	}
+	aliases["cheap"] = "changed"
+	if got := ResolveModel("cheap"); got != ModelGemini15Flash8B {
+		t.Errorf("Changing the copy affected the aliases: ResolveModel(cheap) = %q", got)
+	}
+	SetModelAlias("legacy", "")
+	if _, ok := ModelAliases()["legacy"]; ok {
+		t.Error("Expected the empty model to remove the alias")
+	}
+}
+
+func TestNewIsEvenAiGemini_ModelAlias(t *testing.T) {
+	var paths []string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.Contains(r.URL.Path, "/models/"+ModelGeminiFlashLite+":") {
+			geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+			return
+		}
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{Model: "cheap", FallbackModels: []string{"accurate"}})
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+	if len(paths) != 2 || !strings.Contains(paths[1], "/models/"+ModelGemini15Pro+":") || md.Model != ModelGemini15Pro {
+		t.Errorf("Expected the aliases to be resolved, got requests to %v answered by %q", paths, md.Model)
+	}
+}
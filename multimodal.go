@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/philwo/is-even-ai/core"
+)
+
+const geminiImagePrompt = "Is the number shown in this image an even number? If it shows no number, or more than one, answer undefined."
+
+const geminiAudioPrompt = "Is the number spoken in this recording an even number? If it contains no number, or more than one, answer undefined."
+
+// IsEvenFromImage asks whether the number shown in img, e.g. a photographed
+// meter reading, is even. mime is the image's MIME type, such as "image/jpeg";
+// if empty, it is detected from the data. The configured model must accept
+// images, which all current Gemini models do.
+//
+// The question bypasses middleware, which only sees text prompts, but counts
+// towards Usage and the Budget and is recorded in the audit log. The answer is
+// undefined if the image shows no number or several.
+func (ai *IsEvenAiGemini) IsEvenFromImage(ctx context.Context, img []byte, mime string) (*bool, error) {
+	if mime == "" {
+		mime = http.DetectContentType(img)
+	}
+	if !strings.HasPrefix(mime, "image/") {
+		return nil, fmt.Errorf("unsupported image type %q", mime)
+	}
+	return ai.askMedia(ctx, "isEvenFromImage", geminiImagePrompt, genai.Blob{MIMEType: mime, Data: img})
+}
+
+// IsEvenFromAudio asks whether the number spoken in audio, e.g. a recording
+// from an IVR system, is even. The format is detected from the data; WAV,
+// MP3, AIFF, Ogg and FLAC are supported. Like IsEvenFromImage, the question
+// bypasses middleware, and the answer is undefined if the recording contains
+// no number or several.
+func (ai *IsEvenAiGemini) IsEvenFromAudio(ctx context.Context, audio []byte) (*bool, error) {
+	mime, err := audioType(audio)
+	if err != nil {
+		return nil, err
+	}
+	return ai.askMedia(ctx, "isEvenFromAudio", geminiAudioPrompt, genai.Blob{MIMEType: mime, Data: audio})
+}
+
+// audioType returns the MIME type Gemini expects for audio.
+func audioType(audio []byte) (string, error) {
+	if bytes.HasPrefix(audio, []byte("fLaC")) {
+		return "audio/flac", nil
+	}
+	switch mime := http.DetectContentType(audio); mime {
+	case "audio/wave":
+		return "audio/wav", nil
+	case "application/ogg":
+		return "audio/ogg", nil
+	case "audio/mpeg", "audio/aiff":
+		return mime, nil
+	default:
+		return "", fmt.Errorf("unsupported audio type %q", mime)
+	}
+}
+
+// askMedia asks the primary model prompt about media, with the bookkeeping
+// of regular queries. predicate names the question in the audit log and
+// UsageByPredicate.
+func (ai *IsEvenAiGemini) askMedia(ctx context.Context, predicate, prompt string, media genai.Blob) (*bool, error) {
+	if err := ai.Drainer().Enter(); err != nil {
+		return nil, err
+	}
+	defer ai.Drainer().Leave()
+	if err := ai.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+
+	info := CallInfo{Predicate: predicate}
+	callCtx, cancel := withCallTimeout(core.ContextWithCallInfo(ctx, info), ai.timeout)
+	defer cancel()
+	start := time.Now()
+	call := geminiCall{model: ai.modelName}
+	ai.logger.Debug("gemini request started", "model", call.model, "predicate", predicate, "mime", media.MIMEType)
+	answer, err := ai.generateMedia(callCtx, prompt, media, &call)
+	if err != nil {
+		ai.logger.Debug("gemini request failed", "model", call.model, "latency", time.Since(start), "err", err)
+	}
+	ai.audit(start, info, LabelsFromContext(ctx), prompt, call, answer, err)
+	return answer, err
+}
+
+// generateMedia sends media followed by prompt to the primary model and
+// interprets the answer.
+func (ai *IsEvenAiGemini) generateMedia(ctx context.Context, prompt string, media genai.Blob, call *geminiCall) (*bool, error) {
+	resp, err := ai.genaiModel.GenerateContent(ctx, media, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content from Gemini API: %w", geminiError(call.model, err))
+	}
+	ai.recordUsage(ctx, call.model, resp)
+	call.rawAnswer = geminiResponseText(resp)
+	answer, err := parseGeminiResponse(resp)
+	if err == nil && answer == nil {
+		ai.logger.Warn("gemini answer not understood", "model", call.model, "text", call.rawAnswer)
+	}
+	return answer, err
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_IsEvenFromImage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n fake image")
+	var gotBody string
+	var audit bytes.Buffer
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		geminitest.WriteAnswer(w, r, "true")
+	})
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key", BaseURL: baseURL, AuditLog: NewAuditLog(&audit)})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini against fake server failed: %v", err)
+	}
+	defer ai.Close()
+
+	res, err := ai.IsEvenFromImage(context.Background(), png, "")
+	checkGeminiResult(t, res, err, true, "IsEvenFromImage")
+	if !strings.Contains(gotBody, `"mimeType":"image/png"`) || !strings.Contains(gotBody, base64.StdEncoding.EncodeToString(png)) ||
+		!strings.Contains(gotBody, geminiImagePrompt) {
+		t.Errorf("Request should contain the image and the question, got: %s", gotBody)
+	}
+	if u := ai.Usage(); u.Requests != 1 {
+		t.Errorf("Expected the query to count towards usage, got %+v", u)
+	}
+	var rec AuditRecord
+	if err := json.Unmarshal(audit.Bytes(), &rec); err != nil || rec.Predicate != "isEvenFromImage" || rec.RawAnswer != "true" {
+		t.Errorf("Unexpected audit record %s (%v)", audit.String(), err)
+	}
+
+	if _, err := ai.IsEvenFromImage(context.Background(), []byte("plain text"), ""); err == nil {
+		t.Error("IsEvenFromImage with text data succeeded, want an error")
+	}
+}
+
+func TestIsEvenAiGemini_IsEvenFromAudio(t *testing.T) {
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt fake audio")
+	var gotBody string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		geminitest.WriteAnswer(w, r, "false")
+	})
+
+	res, err := ai.IsEvenFromAudio(context.Background(), wav)
+	checkGeminiResult(t, res, err, false, "IsEvenFromAudio")
+	if !strings.Contains(gotBody, `"mimeType":"audio/wav"`) || !strings.Contains(gotBody, geminiAudioPrompt) {
+		t.Errorf("Request should contain the recording and the question, got: %s", gotBody)
+	}
+	if _, err := ai.IsEvenFromAudio(context.Background(), []byte("plain text")); err == nil {
+		t.Error("IsEvenFromAudio with text data succeeded, want an error")
+	}
+}
+
+func TestAudioType(t *testing.T) {
+	for data, want := range map[string]string{
+		"RIFF\x24\x00\x00\x00WAVEfmt ": "audio/wav",
+		"ID3\x03\x00\x00\x00":          "audio/mpeg",
+		"OggS\x00\x02":                 "audio/ogg",
+		"fLaC\x00\x00\x00\x22":         "audio/flac",
+	} {
+		if got, err := audioType([]byte(data)); err != nil || got != want {
+			t.Errorf("audioType(%q) = %q, %v; want %q", data, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// IsEvenAiPool spreads queries over several pre-initialized providers, for
+// services whose throughput is limited by a single client or connection.
+// Each query goes to the provider with the fewest queries in flight, taking
+// turns among equally busy ones. IsEvenAiPool is safe for concurrent use.
+type IsEvenAiPool struct {
+	providers []Provider
+	drainer   core.Drainer
+
+	mu       sync.Mutex
+	inFlight []int
+	next     int // Provider to prefer among equally busy ones
+}
+
+var _ Provider = (*IsEvenAiPool)(nil)
+
+// NewIsEvenAiPool creates size providers with newProvider up front, so no
+// query waits for a client to be set up. If any of them fails, the ones
+// created so far are closed and the error is returned.
+func NewIsEvenAiPool(size int, newProvider func() (Provider, error)) (*IsEvenAiPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("pool size must be positive, got %d", size)
+	}
+	p := &IsEvenAiPool{inFlight: make([]int, size)}
+	for range size {
+		provider, err := newProvider()
+		if err != nil {
+			err = fmt.Errorf("failed to create pooled provider: %w", err)
+			return nil, errors.Join(err, p.Close())
+		}
+		p.providers = append(p.providers, provider)
+	}
+	return p, nil
+}
+
+// Size returns the number of providers in the pool.
+func (p *IsEvenAiPool) Size() int {
+	return len(p.providers)
+}
+
+// acquire returns the index of the least busy provider and marks a query in
+// flight on it. Call release with the index when the query is done.
+func (p *IsEvenAiPool) acquire() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	best := p.next
+	for i := range p.providers {
+		j := (p.next + i) % len(p.providers)
+		if p.inFlight[j] < p.inFlight[best] {
+			best = j
+		}
+	}
+	p.inFlight[best]++
+	p.next = (best + 1) % len(p.providers)
+	return best
+}
+
+func (p *IsEvenAiPool) release(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[i]--
+}
+
+// ask sends one query to the least busy provider.
+func (p *IsEvenAiPool) ask(ask func(Provider) (*bool, error)) (*bool, error) {
+	if err := p.drainer.Enter(); err != nil {
+		return nil, err
+	}
+	defer p.drainer.Leave()
+	i := p.acquire()
+	defer p.release(i)
+	return ask(p.providers[i])
+}
+
+// IsEven checks if n is even.
+func (p *IsEvenAiPool) IsEven(n int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.IsEven(n) })
+}
+
+// IsOdd checks if n is odd.
+func (p *IsEvenAiPool) IsOdd(n int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.IsOdd(n) })
+}
+
+// AreEqual checks if a and b are equal.
+func (p *IsEvenAiPool) AreEqual(a, b int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.AreEqual(a, b) })
+}
+
+// AreNotEqual checks if a and b are not equal.
+func (p *IsEvenAiPool) AreNotEqual(a, b int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.AreNotEqual(a, b) })
+}
+
+// IsGreaterThan checks if a is greater than b.
+func (p *IsEvenAiPool) IsGreaterThan(a, b int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.IsGreaterThan(a, b) })
+}
+
+// IsLessThan checks if a is less than b.
+func (p *IsEvenAiPool) IsLessThan(a, b int) (*bool, error) {
+	return p.ask(func(p Provider) (*bool, error) { return p.IsLessThan(a, b) })
+}
+
+// Close closes all providers in the pool.
+func (p *IsEvenAiPool) Close() error {
+	var errs []error
+	for _, provider := range p.providers {
+		errs = append(errs, provider.Close())
+	}
+	return errors.Join(errs...)
+}
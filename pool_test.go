@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestPool(t *testing.T, stubs ...*stubProvider) *IsEvenAiPool {
+	t.Helper()
+	i := 0
+	pool, err := NewIsEvenAiPool(len(stubs), func() (Provider, error) {
+		i++
+		return stubs[i-1], nil
+	})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiPool() failed: %v", err)
+	}
+	return pool
+}
+
+func TestIsEvenAiPool_RoundRobin(t *testing.T) {
+	stubs := []*stubProvider{{answer: answerAlways(true)}, {answer: answerAlways(true)}, {answer: answerAlways(true)}}
+	pool := newTestPool(t, stubs...)
+	for n := range 6 {
+		res, err := pool.IsEven(n)
+		if err != nil || res == nil || !*res {
+			t.Fatalf("IsEven(%d) = %v, %v; want true", n, res, err)
+		}
+	}
+	for i, s := range stubs {
+		if got := len(s.callLog()); got != 2 {
+			t.Errorf("Provider %d got %d calls; want 2", i, got)
+		}
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	for i, s := range stubs {
+		if !s.closed {
+			t.Errorf("Provider %d was not closed", i)
+		}
+	}
+}
+
+func TestIsEvenAiPool_LeastBusy(t *testing.T) {
+	started, unblock := make(chan struct{}), make(chan struct{})
+	slow := &stubProvider{answer: func(string, ...int) (*bool, error) {
+		close(started)
+		<-unblock
+		return nil, nil
+	}}
+	fast := &stubProvider{answer: answerAlways(true)}
+	pool := newTestPool(t, slow, fast)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = pool.IsEven(1)
+		close(done)
+	}()
+	<-started
+	for n := range 3 {
+		_, _ = pool.AreEqual(n, n)
+	}
+	close(unblock)
+	<-done
+	if got := len(fast.callLog()); got != 3 {
+		t.Errorf("Idle provider got %d calls while the other was busy; want 3", got)
+	}
+}
+
+func TestNewIsEvenAiPool_Error(t *testing.T) {
+	created := &stubProvider{}
+	calls := 0
+	_, err := NewIsEvenAiPool(3, func() (Provider, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("no quota")
+		}
+		return created, nil
+	})
+	if err == nil {
+		t.Fatal("NewIsEvenAiPool() succeeded despite a failing provider")
+	}
+	if !created.closed {
+		t.Error("Providers created before the failure were not closed")
+	}
+	if _, err := NewIsEvenAiPool(0, nil); err == nil {
+		t.Error("NewIsEvenAiPool(0) succeeded")
+	}
+}
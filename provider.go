@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"fmt"
+	"io"
+)
+
+// Provider is the surface shared by every AI backend. Code that only needs the
+// predicates (and to release the backend when done) should depend on Provider
+// rather than on a concrete client type.
+type Provider interface {
+	IsEven(n int) (*bool, error)
+	IsOdd(n int) (*bool, error)
+	AreEqual(a, b int) (*bool, error)
+	AreNotEqual(a, b int) (*bool, error)
+	IsGreaterThan(a, b int) (*bool, error)
+	IsLessThan(a, b int) (*bool, error)
+	io.Closer
+}
+
+var _ Provider = (*IsEvenAiGemini)(nil)
+
+// predicateAsker is a Provider, or an IsEvenAiCore, which has no Close method.
+type predicateAsker interface {
+	IsEven(n int) (*bool, error)
+	IsOdd(n int) (*bool, error)
+	AreEqual(a, b int) (*bool, error)
+	AreNotEqual(a, b int) (*bool, error)
+	IsGreaterThan(a, b int) (*bool, error)
+	IsLessThan(a, b int) (*bool, error)
+}
+
+// The following interfaces are implemented by providers that answer the
+// predicates beyond Provider, such as IsEvenAiCore.
+type leapYearAsker interface {
+	IsLeapYear(year int) (*bool, error)
+}
+
+type perfectNumberAsker interface {
+	IsPerfectNumber(n int) (*bool, error)
+}
+
+type happyNumberAsker interface {
+	IsHappyNumber(n int) (*bool, error)
+}
+
+type triangularNumberAsker interface {
+	IsTriangularNumber(n int) (*bool, error)
+}
+
+type factorAsker interface {
+	IsFactorOf(a, b int) (*bool, error)
+}
+
+type parityOfSumAsker interface {
+	SumIsEven(a, b int) (*bool, error)
+	ProductIsEven(a, b int) (*bool, error)
+}
+
+// askProvider asks p about predicate applied to args.
+func askProvider(p predicateAsker, predicate string, args []int) (*bool, error) {
+	switch {
+	case predicate == "isEven" && len(args) == 1:
+		return p.IsEven(args[0])
+	case predicate == "isOdd" && len(args) == 1:
+		return p.IsOdd(args[0])
+	case predicate == "areEqual" && len(args) == 2:
+		return p.AreEqual(args[0], args[1])
+	case predicate == "areNotEqual" && len(args) == 2:
+		return p.AreNotEqual(args[0], args[1])
+	case predicate == "isGreaterThan" && len(args) == 2:
+		return p.IsGreaterThan(args[0], args[1])
+	case predicate == "isLessThan" && len(args) == 2:
+		return p.IsLessThan(args[0], args[1])
+	case predicate == "isLeapYear" && len(args) == 1:
+		if l, ok := p.(leapYearAsker); ok {
+			return l.IsLeapYear(args[0])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "isPerfectNumber" && len(args) == 1:
+		if pn, ok := p.(perfectNumberAsker); ok {
+			return pn.IsPerfectNumber(args[0])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "isHappyNumber" && len(args) == 1:
+		if h, ok := p.(happyNumberAsker); ok {
+			return h.IsHappyNumber(args[0])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "isTriangularNumber" && len(args) == 1:
+		if tr, ok := p.(triangularNumberAsker); ok {
+			return tr.IsTriangularNumber(args[0])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "isFactorOf" && len(args) == 2:
+		if f, ok := p.(factorAsker); ok {
+			return f.IsFactorOf(args[0], args[1])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "sumIsEven" && len(args) == 2:
+		if s, ok := p.(parityOfSumAsker); ok {
+			return s.SumIsEven(args[0], args[1])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	case predicate == "productIsEven" && len(args) == 2:
+		if s, ok := p.(parityOfSumAsker); ok {
+			return s.ProductIsEven(args[0], args[1])
+		}
+		return nil, fmt.Errorf("cannot ask %T about %s", p, predicate)
+	default:
+		return nil, fmt.Errorf("cannot ask %s about %v", predicate, args)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"fmt"
+	"sync"
+)
+
+// stubProvider is a Provider for testing wrappers. It answers every predicate
+// through answer and records the calls it receives.
+type stubProvider struct {
+	mu     sync.Mutex
+	calls  []string
+	closed bool
+	answer func(predicate string, args ...int) (*bool, error)
+}
+
+func (p *stubProvider) ask(predicate string, args ...int) (*bool, error) {
+	p.mu.Lock()
+	p.calls = append(p.calls, fmt.Sprintf("%s%v", predicate, args))
+	p.mu.Unlock()
+	if p.answer == nil {
+		return nil, nil
+	}
+	return p.answer(predicate, args...)
+}
+
+func (p *stubProvider) callLog() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+func (p *stubProvider) IsEven(n int) (*bool, error)           { return p.ask("isEven", n) }
+func (p *stubProvider) IsOdd(n int) (*bool, error)            { return p.ask("isOdd", n) }
+func (p *stubProvider) AreEqual(a, b int) (*bool, error)      { return p.ask("areEqual", a, b) }
+func (p *stubProvider) AreNotEqual(a, b int) (*bool, error)   { return p.ask("areNotEqual", a, b) }
+func (p *stubProvider) IsGreaterThan(a, b int) (*bool, error) { return p.ask("isGreaterThan", a, b) }
+func (p *stubProvider) IsLessThan(a, b int) (*bool, error)    { return p.ask("isLessThan", a, b) }
+
+func (p *stubProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+// answerAlways returns an answer func for stubProvider that always yields v.
+func answerAlways(v bool) func(string, ...int) (*bool, error) {
+	return func(string, ...int) (*bool, error) { return &v, nil }
+}
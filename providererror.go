@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+)
+
+// ProviderError is a failed request to a provider's API, classified so that
+// callers can react to it without parsing error strings. It wraps both Kind
+// and the original error, so errors.Is(err, ErrRateLimited) and errors.As(err,
+// &apiErr) with the SDK's error type both work.
+type ProviderError struct {
+	Provider string // e.g. "gemini"
+	Model    string
+	// Kind is ErrRateLimited, ErrModelNotFound, ErrUnauthenticated,
+	// ErrInvalidRequest, ErrContextTooLong, ErrContentFiltered or
+	// ErrUnavailable, or nil if the error is none of these.
+	Kind   error
+	Code   int    // HTTP status code, or 0 if there was no response
+	Status string // API status, e.g. "RESOURCE_EXHAUSTED"; may be empty
+	// Retryable is whether the same request may succeed later.
+	Retryable bool
+	// RetryAfter is the delay suggested by the API before retrying, or 0.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Kind == nil {
+		return e.Err.Error()
+	}
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// IsRetryable reports whether the request that failed with err may succeed if
+// made again. Errors that are not a ProviderError are retryable unless they
+// are ErrBudgetExceeded or a canceled context.
+func IsRetryable(err error) bool {
+	var perr *ProviderError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &perr):
+		return perr.Retryable
+	default:
+		return !errors.Is(err, ErrBudgetExceeded) && !errors.Is(err, ErrShutdown) && !errors.Is(err, context.Canceled)
+	}
+}
+
+// geminiError classifies an error of a Gemini API request to model.
+func geminiError(model string, err error) error {
+	perr := &ProviderError{Provider: "gemini", Model: model, Err: err}
+	var apiErr *googleapi.Error
+	var blocked *genai.BlockedError
+	switch {
+	case errors.As(err, &blocked):
+		perr.Kind = ErrContentFiltered
+	case errors.As(err, &apiErr):
+		perr.Code = apiErr.Code
+		perr.Status, perr.RetryAfter = parseGoogleErrorBody(apiErr.Body)
+		switch {
+		case apiErr.Code == http.StatusTooManyRequests:
+			perr.Kind, perr.Retryable = ErrRateLimited, true
+		case apiErr.Code == http.StatusNotFound:
+			perr.Kind = ErrModelNotFound
+		case apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden:
+			perr.Kind = ErrUnauthenticated
+		case apiErr.Code == http.StatusBadRequest && strings.Contains(apiErr.Message, "token"):
+			// e.g. "The input token count (1048577) exceeds the maximum number of tokens allowed (1048576)."
+			perr.Kind = ErrContextTooLong
+		case apiErr.Code == http.StatusBadRequest:
+			perr.Kind = ErrInvalidRequest
+		case apiErr.Code >= 500:
+			perr.Kind, perr.Retryable = ErrUnavailable, true
+		}
+	default:
+		// Network errors and timeouts of individual calls.
+		perr.Retryable = !errors.Is(err, context.Canceled)
+	}
+	return perr
+}
+
+// parseGoogleErrorBody extracts the status and the retry delay, if any, from
+// the body of a Google API error response.
+func parseGoogleErrorBody(body string) (status string, retryAfter time.Duration) {
+	var resp struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				Type       string `json:"@type"`
+				RetryDelay string `json:"retryDelay"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(body), &resp) != nil {
+		return "", 0
+	}
+	for _, d := range resp.Error.Details {
+		if d.Type == "type.googleapis.com/google.rpc.RetryInfo" {
+			retryAfter, _ = time.ParseDuration(d.RetryDelay)
+		}
+	}
+	return resp.Error.Status, retryAfter
+}
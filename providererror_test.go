@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/geminitest"
+	"google.golang.org/api/googleapi"
+)
+
+func TestGeminiError(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		write     func(w http.ResponseWriter)
+		kind      error
+		status    string
+		retryable bool
+	}{
+		{"RateLimited", func(w http.ResponseWriter) {
+			geminitest.WriteError(w, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "quota exceeded")
+		}, ErrRateLimited, "RESOURCE_EXHAUSTED", true},
+		{"NotFound", func(w http.ResponseWriter) {
+			geminitest.WriteError(w, http.StatusNotFound, "NOT_FOUND", "model not found")
+		}, ErrModelNotFound, "NOT_FOUND", false},
+		{"Unauthenticated", func(w http.ResponseWriter) {
+			geminitest.WriteError(w, http.StatusForbidden, "PERMISSION_DENIED", "API key not valid")
+		}, ErrUnauthenticated, "PERMISSION_DENIED", false},
+		{"ContextTooLong", func(w http.ResponseWriter) {
+			geminitest.WriteError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "The input token count (1048577) exceeds the maximum number of tokens allowed (1048576).")
+		}, ErrContextTooLong, "INVALID_ARGUMENT", false},
+		{"InvalidRequest", func(w http.ResponseWriter) {
+			geminitest.WriteError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "bad request")
+		}, ErrInvalidRequest, "INVALID_ARGUMENT", false},
+		{"ContentFiltered", func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"promptFeedback":{"blockReason":"SAFETY"}}`))
+		}, ErrContentFiltered, "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) { tc.write(w) })
+			_, err := ai.IsEven(2)
+			if !errors.Is(err, tc.kind) {
+				t.Fatalf("Expected %v, got %v", tc.kind, err)
+			}
+			var perr *ProviderError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Expected a ProviderError, got %T: %v", err, err)
+			}
+			if perr.Provider != "gemini" || perr.Model != "gemini-2.0-flash-lite" || perr.Status != tc.status || perr.Retryable != tc.retryable {
+				t.Errorf("Unexpected ProviderError %+v", perr)
+			}
+			if IsRetryable(err) != tc.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", !tc.retryable, tc.retryable)
+			}
+		})
+	}
+}
+
+func TestGeminiError_RetryAfter(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":429,"message":"quota exceeded","status":"RESOURCE_EXHAUSTED","details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"17s"}]}}`))
+	})
+	_, err := ai.IsEven(2)
+	var perr *ProviderError
+	if !errors.As(err, &perr) || perr.RetryAfter != 17*time.Second || perr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected a ProviderError with RetryAfter 17s, got %v", err)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		t.Error("Expected the googleapi.Error to remain accessible")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for err, want := range map[error]bool{
+		nil:                   false,
+		errors.New("network"): true,
+		ErrBudgetExceeded:     false,
+		ErrShutdown:           false,
+		context.Canceled:      false,
+		&ProviderError{Kind: ErrUnavailable, Retryable: true, Err: errors.New("503")}: true,
+	} {
+		if got := IsRetryable(err); got != want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
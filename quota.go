@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/googleapi"
+)
+
+// defaultQuotaCooldown is how long QuotaLimiter pauses after a 429 response
+// unless configured otherwise.
+const defaultQuotaCooldown = 10 * time.Second
+
+// defaultTokensPerQuery is QuotaLimiter's token estimate for a query before
+// it is answered. The default prompts take about 40 tokens including the
+// system instruction, plus one for the answer.
+const defaultTokensPerQuery = 50
+
+// Quota describes a provider's rate limits. Zero limits are unlimited.
+type Quota struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+	// TokensPerQuery is the number of tokens reserved for a query before it is
+	// sent. Once it is answered, queries going over it are charged the rest.
+	// Optional: defaults to 50.
+	TokensPerQuery int
+	// Cooldown is how long all queries are held back after the provider
+	// rejected one for exceeding its quota. Optional: defaults to 10 seconds.
+	Cooldown time.Duration
+}
+
+// DefaultGeminiQuotas holds the free tier rate limits of common Gemini models
+// at the time of writing. Paid tiers are much higher; look up the limits of
+// your project in Google AI Studio.
+var DefaultGeminiQuotas = map[string]Quota{
+	ModelGeminiFlashLite: {RequestsPerMinute: 30, TokensPerMinute: 1_000_000},
+	ModelGeminiFlash:     {RequestsPerMinute: 15, TokensPerMinute: 1_000_000},
+	ModelGemini15Flash:   {RequestsPerMinute: 15, TokensPerMinute: 1_000_000},
+	ModelGemini15Flash8B: {RequestsPerMinute: 15, TokensPerMinute: 1_000_000},
+	ModelGemini15Pro:     {RequestsPerMinute: 2, TokensPerMinute: 32_000},
+}
+
+// QuotaLimiter paces queries to stay within a Quota, so that large offline
+// workloads run at the highest rate the provider accepts instead of failing
+// with 429 responses. Queries wait for their turn, or fail once their context
+// is done. If the provider still reports exceeding its quota, all queries are
+// held back for Quota.Cooldown. Add it to a client with Middleware and run the
+// workload with Batch:
+//
+//	ai.Use(is_even_ai.NewQuotaLimiter(is_even_ai.DefaultGeminiQuotas["gemini-2.0-flash-lite"]).Middleware())
+//	results := is_even_ai.Batch(ctx, numbers, 32, ...)
+//
+// QuotaLimiter is safe for concurrent use, and one can be shared by several
+// clients using the same quota.
+type QuotaLimiter struct {
+	quota    Quota
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// NewQuotaLimiter returns a QuotaLimiter for quota. Queries may start at the
+// full rate right away.
+func NewQuotaLimiter(quota Quota) *QuotaLimiter {
+	if quota.TokensPerQuery <= 0 {
+		quota.TokensPerQuery = defaultTokensPerQuery
+	}
+	if quota.Cooldown <= 0 {
+		quota.Cooldown = defaultQuotaCooldown
+	}
+	l := &QuotaLimiter{
+		quota:    quota,
+		requests: rate.NewLimiter(rate.Inf, 0),
+		tokens:   rate.NewLimiter(rate.Inf, 0),
+	}
+	if quota.RequestsPerMinute > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(quota.RequestsPerMinute)/60, quota.RequestsPerMinute)
+	}
+	if quota.TokensPerMinute > 0 {
+		l.tokens = rate.NewLimiter(rate.Limit(quota.TokensPerMinute)/60, quota.TokensPerMinute)
+		l.quota.TokensPerQuery = min(quota.TokensPerQuery, quota.TokensPerMinute)
+	}
+	return l
+}
+
+// Middleware returns middleware that paces queries with l.
+func (l *QuotaLimiter) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			if err := l.wait(ctx); err != nil {
+				return nil, err
+			}
+			md := core.CallMetadataFromContext(ctx)
+			if md == nil {
+				ctx, md = WithCallMetadata(ctx)
+			}
+			before := md.TotalTokens()
+			res, err := next(ctx, prompt)
+			if extra := int(md.TotalTokens()-before) - l.quota.TokensPerQuery; extra > 0 && l.quota.TokensPerMinute > 0 {
+				l.tokens.ReserveN(time.Now(), min(extra, l.quota.TokensPerMinute)) // Delays later queries
+			}
+			if isRateLimited(err) {
+				l.pause()
+			}
+			return res, err
+		}
+	}
+}
+
+// wait blocks until a query may be sent under the quota.
+func (l *QuotaLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	paused := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+	if paused > 0 {
+		timer := time.NewTimer(paused)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if err := l.requests.Wait(ctx); err != nil {
+		return err
+	}
+	return l.tokens.WaitN(ctx, l.quota.TokensPerQuery)
+}
+
+// pause holds back queries for the cooldown after a 429 response.
+func (l *QuotaLimiter) pause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pausedUntil = time.Now().Add(l.quota.Cooldown)
+}
+
+// isRateLimited reports whether err means the provider rejected a request for
+// exceeding its quota.
+func isRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *googleapi.Error
+	return errors.Is(err, ErrRateLimited) || (errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests)
+}
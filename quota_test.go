@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+func TestQuotaLimiter_Requests(t *testing.T) {
+	calls := 0
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true)))
+	core.Use(NewQuotaLimiter(Quota{RequestsPerMinute: 2}).Middleware())
+
+	for range 2 {
+		if _, err := core.IsEven(2); err != nil {
+			t.Fatalf("IsEven() within the quota failed: %v", err)
+		}
+	}
+	// The third request would have to wait 30 seconds.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := core.WithContext(ctx).IsEven(2); err == nil {
+		t.Error("IsEven() over the quota succeeded, want an error")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 queries to reach the provider, got %d", calls)
+	}
+}
+
+func TestQuotaLimiter_Tokens(t *testing.T) {
+	calls := 0
+	ai := newIsEvenAiCore(testPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		calls++
+		core.CallMetadataFromContext(ctx).Record("model", 900, 100, 0)
+		return answered(true)()
+	})
+	ai.Use(NewQuotaLimiter(Quota{TokensPerMinute: 1200, TokensPerQuery: 100}).Middleware())
+
+	if _, err := ai.IsEven(2); err != nil {
+		t.Fatalf("IsEven() within the quota failed: %v", err)
+	}
+	// The first query used 1000 of 1200 tokens, so the next reservation of
+	// 100 tokens is fine, but the one after that has to wait.
+	ctx, md := WithCallMetadata(context.Background())
+	if _, err := ai.WithContext(ctx).IsEven(2); err != nil {
+		t.Fatalf("IsEven() within the quota failed: %v", err)
+	}
+	if md.TotalTokens() != 1000 {
+		t.Errorf("Expected the caller's metadata to collect 1000 tokens, got %d", md.TotalTokens())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := ai.WithContext(ctx).IsEven(2); err == nil {
+		t.Error("IsEven() over the token quota succeeded, want an error")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 queries to reach the provider, got %d", calls)
+	}
+}
+
+func TestQuotaLimiter_Cooldown(t *testing.T) {
+	calls := 0
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls,
+		failed(fmt.Errorf("quota: %w", ErrRateLimited)), answered(true)))
+	core.Use(NewQuotaLimiter(Quota{Cooldown: 50 * time.Millisecond}).Middleware())
+
+	if _, err := core.IsEven(2); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("IsEven() = %v, want ErrRateLimited", err)
+	}
+	start := time.Now()
+	res, err := core.IsEven(2)
+	if err != nil || res == nil || !*res {
+		t.Errorf("IsEven() after the cooldown = %v, %v", res, err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Query after a 429 ran after %v, want it held back for the cooldown", elapsed)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Defaults of RegionOptions.
+const (
+	defaultRegionFailureThreshold = 5
+	defaultRegionCooldown         = time.Minute
+)
+
+// Region is a provider serving one region, e.g. an IsEvenAiGemini whose
+// GeminiClientOptions.BaseURL points at a gateway in that region.
+type Region struct {
+	Name     string // e.g. "europe-west4"
+	Provider Provider
+}
+
+// RegionOptions configures IsEvenAiRegions.
+type RegionOptions struct {
+	// FailureThreshold is the number of consecutive retryable errors (see
+	// IsRetryable) after which a region is taken out of service.
+	// Optional: defaults to 5.
+	FailureThreshold int
+	// Cooldown is how long a region stays out of service before queries are
+	// sent to it again. Optional: defaults to one minute.
+	Cooldown time.Duration
+	// OnFailover, if set, is called when queries move from one region to
+	// another because of err.
+	OnFailover func(from, to string, err error)
+}
+
+// IsEvenAiRegions sends queries to the first of several regions, such as an
+// EU region for data residency, and fails over to the next one once a region
+// keeps failing. The query that takes a region out of service is asked again
+// in the next one. After the cooldown, queries go back to the preferred
+// region. IsEvenAiRegions is safe for concurrent use.
+type IsEvenAiRegions struct {
+	regions    []Region
+	threshold  int
+	cooldown   time.Duration
+	onFailover func(from, to string, err error)
+
+	mu     sync.Mutex
+	states []regionState
+}
+
+// regionState tracks the health of a region.
+type regionState struct {
+	failures  int       // Consecutive retryable errors
+	downUntil time.Time // Out of service until then
+}
+
+var _ Provider = (*IsEvenAiRegions)(nil)
+
+// NewIsEvenAiRegions creates a provider failing over between regions, in
+// order of preference.
+func NewIsEvenAiRegions(regions []Region, opts ...RegionOptions) *IsEvenAiRegions {
+	if len(regions) == 0 {
+		panic("at least one region is required")
+	}
+	for _, r := range regions {
+		if r.Provider == nil {
+			panic("region providers cannot be nil")
+		}
+	}
+	var o RegionOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	r := &IsEvenAiRegions{
+		regions:    regions,
+		threshold:  defaultRegionFailureThreshold,
+		cooldown:   defaultRegionCooldown,
+		onFailover: o.OnFailover,
+		states:     make([]regionState, len(regions)),
+	}
+	if o.FailureThreshold > 0 {
+		r.threshold = o.FailureThreshold
+	}
+	if o.Cooldown > 0 {
+		r.cooldown = o.Cooldown
+	}
+	return r
+}
+
+// Active returns the name of the region queries are currently sent to.
+func (r *IsEvenAiRegions) Active() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.regions[r.pick(time.Now())].Name
+}
+
+// pick returns the index of the first region in service, or of the one
+// returning to service first if all are out. r.mu must be held.
+func (r *IsEvenAiRegions) pick(now time.Time) int {
+	best := 0
+	for i, s := range r.states {
+		if !now.Before(s.downUntil) {
+			return i
+		}
+		if s.downUntil.Before(r.states[best].downUntil) {
+			best = i
+		}
+	}
+	return best
+}
+
+// route asks the active region, failing over to the next one if this query
+// takes it out of service.
+func (r *IsEvenAiRegions) route(ask func(Provider) (*bool, error)) (*bool, error) {
+	r.mu.Lock()
+	i := r.pick(time.Now())
+	r.mu.Unlock()
+	for {
+		res, err := ask(r.regions[i].Provider)
+		next, failover := r.record(i, err)
+		if !failover {
+			return res, err
+		}
+		if r.onFailover != nil {
+			r.onFailover(r.regions[i].Name, r.regions[next].Name, err)
+		}
+		i = next
+	}
+}
+
+// record updates the health of region i after a query that failed with err,
+// if not nil. If the region is taken out of service and another one is in
+// service, it returns that region's index and true.
+func (r *IsEvenAiRegions) record(i int, err error) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := &r.states[i]
+	if !IsRetryable(err) {
+		if err == nil {
+			s.failures = 0
+		}
+		return 0, false
+	}
+	s.failures++
+	if s.failures < r.threshold {
+		return 0, false
+	}
+	now := time.Now()
+	s.failures = 0
+	s.downUntil = now.Add(r.cooldown)
+	next := r.pick(now)
+	if next == i || now.Before(r.states[next].downUntil) {
+		return 0, false // No region left to fail over to
+	}
+	return next, true
+}
+
+// IsEven checks if n is even.
+func (r *IsEvenAiRegions) IsEven(n int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.IsEven(n) })
+}
+
+// IsOdd checks if n is odd.
+func (r *IsEvenAiRegions) IsOdd(n int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.IsOdd(n) })
+}
+
+// AreEqual checks if a and b are equal.
+func (r *IsEvenAiRegions) AreEqual(a, b int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.AreEqual(a, b) })
+}
+
+// AreNotEqual checks if a and b are not equal.
+func (r *IsEvenAiRegions) AreNotEqual(a, b int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.AreNotEqual(a, b) })
+}
+
+// IsGreaterThan checks if a is greater than b.
+func (r *IsEvenAiRegions) IsGreaterThan(a, b int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.IsGreaterThan(a, b) })
+}
+
+// IsLessThan checks if a is less than b.
+func (r *IsEvenAiRegions) IsLessThan(a, b int) (*bool, error) {
+	return r.route(func(p Provider) (*bool, error) { return p.IsLessThan(a, b) })
+}
+
+// Close closes the providers of all regions.
+func (r *IsEvenAiRegions) Close() error {
+	var errs []error
+	for _, region := range r.regions {
+		errs = append(errs, region.Provider.Close())
+	}
+	return errors.Join(errs...)
+}
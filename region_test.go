@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsEvenAiRegions_Failover(t *testing.T) {
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+	primary := &stubProvider{answer: func(string, ...int) (*bool, error) {
+		if primaryDown.Load() {
+			return nil, &ProviderError{Kind: ErrUnavailable, Retryable: true, Err: errors.New("503")}
+		}
+		v := true
+		return &v, nil
+	}}
+	secondary := &stubProvider{answer: answerAlways(true)}
+	var failovers []string
+	r := NewIsEvenAiRegions([]Region{{"eu", primary}, {"us", secondary}}, RegionOptions{
+		FailureThreshold: 2,
+		Cooldown:         50 * time.Millisecond,
+		OnFailover:       func(from, to string, err error) { failovers = append(failovers, from+">"+to) },
+	})
+
+	if _, err := r.IsEven(2); err == nil {
+		t.Error("Expected the first failure to be returned")
+	}
+	if res, err := r.IsEven(2); err != nil || res == nil || !*res {
+		t.Errorf("Expected the failing-over query to be answered by the secondary region, got %v, %v", res, err)
+	}
+	if got := r.Active(); got != "us" {
+		t.Errorf("Active() = %s, want us", got)
+	}
+	if len(failovers) != 1 || failovers[0] != "eu>us" {
+		t.Errorf("Unexpected failovers %v", failovers)
+	}
+	_, _ = r.IsOdd(3)
+	if got := len(primary.callLog()); got != 2 {
+		t.Errorf("Expected the primary region to be skipped during the cooldown, got %d calls", got)
+	}
+
+	primaryDown.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if got := r.Active(); got != "eu" {
+		t.Errorf("Active() after cooldown = %s, want eu", got)
+	}
+	if _, err := r.IsEven(4); err != nil || len(primary.callLog()) != 3 {
+		t.Errorf("Expected the primary region to answer again, got %v", err)
+	}
+}
+
+func TestIsEvenAiRegions_NonRetryableErrors(t *testing.T) {
+	primary := &stubProvider{answer: func(string, ...int) (*bool, error) {
+		return nil, &ProviderError{Kind: ErrInvalidRequest, Err: errors.New("400")}
+	}}
+	secondary := &stubProvider{answer: answerAlways(true)}
+	r := NewIsEvenAiRegions([]Region{{"eu", primary}, {"us", secondary}}, RegionOptions{FailureThreshold: 1})
+	for range 3 {
+		if _, err := r.IsEven(2); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("Expected ErrInvalidRequest, got %v", err)
+		}
+	}
+	if len(secondary.callLog()) != 0 || r.Active() != "eu" {
+		t.Error("Expected no failover for non-retryable errors")
+	}
+}
+
+func TestIsEvenAiRegions_AllDown(t *testing.T) {
+	down := func(string, ...int) (*bool, error) { return nil, errors.New("connection refused") }
+	a, b := &stubProvider{answer: down}, &stubProvider{answer: down}
+	r := NewIsEvenAiRegions([]Region{{"a", a}, {"b", b}}, RegionOptions{FailureThreshold: 1})
+	if _, err := r.IsEven(2); err == nil {
+		t.Fatal("Expected an error when all regions are down")
+	}
+	if len(a.callLog()) != 1 || len(b.callLog()) != 1 {
+		t.Errorf("Expected each region to be tried once, got %d and %d calls", len(a.callLog()), len(b.callLog()))
+	}
+	if err := r.Close(); err != nil || !a.closed || !b.closed {
+		t.Errorf("Close() = %v; expected all regions to be closed", err)
+	}
+}
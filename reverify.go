@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// Defaults of CacheReverifier.
+const (
+	defaultReverifySampleSize   = 10
+	defaultReverifyInterval     = time.Hour
+	defaultReverifyMaxQuestions = 10000
+)
+
+// CacheReverifier periodically asks a random sample of cached questions again
+// and updates the cache where the answer changed, in case the model's opinion
+// about the parity of a number evolves. Connect it to a cache with
+// CacheOptions and run it in the background:
+//
+//	reverifier := &is_even_ai.CacheReverifier{SampleSize: 100, Interval: 6 * time.Hour}
+//	ai.Use(is_even_ai.CacheMiddlewareWithOptions(cache, is_even_ai.CacheOptions{Reverifier: reverifier}))
+//	go reverifier.Run(ctx)
+//
+// Questions are asked again through the middleware after the cache. A new
+// defined answer replaces the cached one; an answer that became undefined is
+// removed from the cache if it is a DeletingCache. Failed questions are tried
+// again in a later round. The reverifier only knows about questions cached,
+// or answered from the cache, since the process started. CacheReverifier is
+// safe for concurrent use.
+type CacheReverifier struct {
+	// SampleSize is the number of questions asked again per round. Optional:
+	// defaults to 10.
+	SampleSize int
+	// Interval is the time between rounds. Optional: defaults to 1 hour.
+	Interval time.Duration
+	// MaxQuestions bounds the number of questions remembered; further ones
+	// are not reverified. Optional: defaults to 10000.
+	MaxQuestions int
+	// OnChange, if set, is called with every changed answer.
+	OnChange func(CacheChange)
+	// Rand is the source of randomness for sampling.
+	// Optional: defaults to a randomly seeded source.
+	Rand *rand.Rand
+
+	mu        sync.Mutex // Guards Rand, which is not safe for concurrent use, and the questions
+	questions map[string]*cachedQuestion
+	keys      []string // Keys of questions, for sampling
+}
+
+var _ CacheInvalidator = (*CacheReverifier)(nil)
+
+// cachedQuestion is a question remembered by a CacheReverifier.
+type cachedQuestion struct {
+	cache  Cache
+	next   QueryHandler
+	info   CallInfo
+	prompt string
+	answer Answer
+}
+
+// CacheChange is a cached answer that changed when asked again.
+type CacheChange struct {
+	Question CallInfo
+	Old, New Answer
+}
+
+// ReverifyResult is the outcome of one CacheReverifier round.
+type ReverifyResult struct {
+	Time    time.Time
+	Checked int // Questions answered again
+	Changed int // Of those, with a different answer
+	Errors  int // Questions that failed
+}
+
+// Run reverifies a sample of cached questions every Interval until ctx is
+// done, and returns ctx.Err().
+func (r *CacheReverifier) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultReverifyInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if _, err := r.Reverify(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Reverify asks a sample of the remembered questions again and updates their
+// cached answers. If ctx is done, Reverify stops and returns ctx.Err().
+func (r *CacheReverifier) Reverify(ctx context.Context) (ReverifyResult, error) {
+	res := ReverifyResult{Time: time.Now()}
+	for _, key := range r.sample() {
+		if err := ctx.Err(); err != nil {
+			return ReverifyResult{}, err
+		}
+		r.mu.Lock()
+		q, ok := r.questions[key]
+		var question cachedQuestion
+		if ok {
+			question = *q
+		}
+		r.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		answer, err := question.next(core.ContextWithCallInfo(ctx, question.info), question.prompt)
+		if err != nil {
+			res.Errors++
+			continue
+		}
+		res.Checked++
+		change := CacheChange{Question: question.info, Old: question.answer, New: core.AnswerOf(answer)}
+		if change.New == change.Old {
+			continue
+		}
+		res.Changed++
+		if change.New != AnswerUndefined {
+			_ = question.cache.Set(ctx, key, change.New)
+			r.update(key, change.New)
+		} else {
+			if dc, ok := question.cache.(DeletingCache); ok {
+				_ = dc.Delete(ctx, key)
+			}
+			r.forget(key)
+		}
+		if r.OnChange != nil {
+			r.OnChange(change)
+		}
+	}
+	return res, nil
+}
+
+// Len returns the number of remembered questions.
+func (r *CacheReverifier) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.keys)
+}
+
+// InvalidateNumber implements CacheInvalidator by forgetting the questions
+// about n, so that a purged answer is not stored again by Reverify. Register
+// the reverifier together with its caches.
+func (r *CacheReverifier) InvalidateNumber(_ context.Context, n int) error {
+	r.forgetFunc(func(q *cachedQuestion) bool { return slices.Contains(q.info.Args, n) })
+	return nil
+}
+
+// InvalidatePredicate implements CacheInvalidator by forgetting the questions
+// about predicate.
+func (r *CacheReverifier) InvalidatePredicate(_ context.Context, predicate string) error {
+	r.forgetFunc(func(q *cachedQuestion) bool { return q.info.Predicate == predicate })
+	return nil
+}
+
+// FlushCache implements CacheInvalidator by forgetting all questions.
+func (r *CacheReverifier) FlushCache(context.Context) error {
+	r.forgetFunc(func(*cachedQuestion) bool { return true })
+	return nil
+}
+
+// remember records a question with a defined answer under key of cache, to be
+// asked again through next. It does nothing if r is nil or ctx carries no
+// CallInfo.
+func (r *CacheReverifier) remember(ctx context.Context, cache Cache, next QueryHandler, key, prompt string, answer Answer) {
+	if r == nil {
+		return
+	}
+	info, ok := CallInfoFromContext(ctx)
+	if !ok {
+		return
+	}
+	maxQuestions := r.MaxQuestions
+	if maxQuestions <= 0 {
+		maxQuestions = defaultReverifyMaxQuestions
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.questions == nil {
+		r.questions = map[string]*cachedQuestion{}
+	}
+	if q, ok := r.questions[key]; ok {
+		q.next, q.answer = next, answer
+		return
+	}
+	if len(r.keys) >= maxQuestions {
+		return
+	}
+	r.questions[key] = &cachedQuestion{cache: cache, next: next, info: info, prompt: prompt, answer: answer}
+	r.keys = append(r.keys, key)
+}
+
+// sample returns the keys of up to SampleSize random questions.
+func (r *CacheReverifier) sample() []string {
+	size := r.SampleSize
+	if size <= 0 {
+		size = defaultReverifySampleSize
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Rand == nil {
+		r.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	keys := append([]string(nil), r.keys...)
+	r.Rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	return keys[:min(size, len(keys))]
+}
+
+// update records the new answer to the question under key.
+func (r *CacheReverifier) update(key string, answer Answer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if q, ok := r.questions[key]; ok {
+		q.answer = answer
+	}
+}
+
+// forget removes the question under key.
+func (r *CacheReverifier) forget(key string) {
+	r.forgetFunc(func(q *cachedQuestion) bool { return q == r.questions[key] })
+}
+
+// forgetFunc removes the questions for which del returns true. del is called
+// with r.mu held.
+func (r *CacheReverifier) forgetFunc(del func(*cachedQuestion) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = slices.DeleteFunc(r.keys, func(key string) bool {
+		if q := r.questions[key]; del(q) {
+			delete(r.questions, key)
+			return true
+		}
+		return false
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCacheReverifier(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+	var changes []CacheChange
+	reverifier := &CacheReverifier{OnChange: func(c CacheChange) { changes = append(changes, c) }}
+	undefined := func() (*bool, error) { return nil, nil }
+	ai := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true), answered(false), failed(errors.New("boom")), undefined))
+	ai.Use(CacheMiddlewareWithOptions(cache, CacheOptions{Reverifier: reverifier}))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		res, err := ai.IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+	}
+	if calls != 1 || reverifier.Len() != 1 {
+		t.Fatalf("Expected one query and one remembered question, got %d queries and %d questions", calls, reverifier.Len())
+	}
+
+	// The model changed its mind, so the cached answer is replaced.
+	got, err := reverifier.Reverify(ctx)
+	if err != nil || got.Checked != 1 || got.Changed != 1 {
+		t.Errorf("Reverify() = %+v, %v; want 1 checked and changed", got, err)
+	}
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, false, "IsEven", 2)
+	if calls != 2 {
+		t.Errorf("Expected the new answer from cache, got %d queries", calls)
+	}
+	if len(changes) != 1 || changes[0].Old != AnswerTrue || changes[0].New != AnswerFalse || changes[0].Question.Predicate != "isEven" {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	// Failures keep the cached answer.
+	if got, err := reverifier.Reverify(ctx); err != nil || got.Errors != 1 || got.Changed != 0 {
+		t.Errorf("Reverify() = %+v, %v; want 1 error", got, err)
+	}
+
+	// Answers that became undefined are invalidated.
+	if got, err := reverifier.Reverify(ctx); err != nil || got.Changed != 1 {
+		t.Errorf("Reverify() = %+v, %v; want 1 changed", got, err)
+	}
+	if cache.Len() != 0 || reverifier.Len() != 0 {
+		t.Errorf("Expected the answer to be invalidated, got %d entries and %d questions", cache.Len(), reverifier.Len())
+	}
+	if got, err := reverifier.Reverify(ctx); err != nil || got.Checked != 0 {
+		t.Errorf("Reverify() without questions = %+v, %v", got, err)
+	}
+}
+
+func TestCacheReverifier_SampleSize(t *testing.T) {
+	calls := 0
+	reverifier := &CacheReverifier{SampleSize: 2, MaxQuestions: 3}
+	ai := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true)))
+	ai.Use(CacheMiddlewareWithOptions(NewMemoryCache(), CacheOptions{Reverifier: reverifier}))
+	for n := 0; n < 5; n++ {
+		_, _ = ai.IsEven(n)
+	}
+	if reverifier.Len() != 3 {
+		t.Errorf("Len() = %d, want MaxQuestions", reverifier.Len())
+	}
+
+	got, err := reverifier.Reverify(context.Background())
+	if err != nil || got.Checked != 2 || calls != 7 {
+		t.Errorf("Reverify() = %+v, %v after %d queries; want 2 checked", got, err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := reverifier.Reverify(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Reverify() with canceled context = %v, want context.Canceled", err)
+	}
+}
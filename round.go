@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// roundingRange returns the integers f may be rounded to, and whether f is
+// exactly halfway between two integers. Only exact halves may be rounded to
+// either neighbor; any other f has a single nearest integer.
+func roundingRange(f float64) (NumberRange, bool, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return NumberRange{}, false, fmt.Errorf("cannot round %v to an int", f)
+	}
+	floor := math.Floor(f)
+	if f-floor != 0.5 {
+		nearest := int(math.Round(f))
+		return NumberRange{Min: nearest, Max: nearest}, false, nil
+	}
+	return NumberRange{Min: int(floor), Max: int(math.Ceil(f))}, true, nil
+}
+
+// RoundToNearestEven asks the model to round f to the nearest integer, with
+// exact halves rounded to the even neighbor (banker's rounding), and reports
+// whether f was an exact half. With AskInt, the answer must be the integer
+// nearest to f, or for exact halves one of the integers next to f certified
+// by IsEven through the middleware; otherwise it is asked for again, and
+// after three attempts RoundToNearestEven fails with an error wrapping
+// ErrAnswerRejected.
+func (ai *IsEvenAiGemini) RoundToNearestEven(ctx context.Context, f float64) (int, bool, error) {
+	r, half, err := roundingRange(f)
+	if err != nil {
+		return 0, false, err
+	}
+	opts := AskIntOptions{Predicate: "roundToNearestEven", Range: &r}
+	if half {
+		opts.Validate = ai.certifier(ctx, true)
+	}
+	prompt := fmt.Sprintf("Round %s to the nearest integer. If it is exactly halfway between two integers, round to the even one. Answer with the integer only, in digits.",
+		strconv.FormatFloat(f, 'f', -1, 64))
+	n, err := ai.AskInt(ctx, prompt, opts)
+	if err != nil {
+		return 0, false, err
+	}
+	return n, half, nil
+}
+
+// RoundToNearestEven rounds f to the nearest integer, with exact halves
+// rounded to the even neighbor, and reports whether f was an exact half.
+func (l *IsEvenAiLocal) RoundToNearestEven(ctx context.Context, f float64) (int, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+	_, half, err := roundingRange(f)
+	if err != nil {
+		return 0, false, err
+	}
+	return int(math.RoundToEven(f)), half, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_RoundToNearestEven(t *testing.T) {
+	var certified []string
+	roundings := 0
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "Round 2.1 to"):
+			roundings++
+			if roundings == 1 {
+				geminitest.WriteAnswer(w, r, "3") // The wrong neighbor
+				return
+			}
+			geminitest.WriteAnswer(w, r, "2")
+		case strings.Contains(string(body), "Round 2.5 to"):
+			geminitest.WriteAnswer(w, r, "2")
+		case strings.Contains(string(body), "Round -1.25 to"):
+			geminitest.WriteAnswer(w, r, "-1")
+		case strings.Contains(string(body), "Round 3.5 to"):
+			geminitest.WriteAnswer(w, r, "3") // Not even, so never certified
+		case strings.Contains(string(body), "Is 2 an even number?"):
+			certified = append(certified, "2")
+			geminitest.WriteAnswer(w, r, "true")
+		case strings.Contains(string(body), "Is 3 an even number?"):
+			certified = append(certified, "3")
+			geminitest.WriteAnswer(w, r, "false")
+		default:
+			t.Errorf("Unexpected request: %s", body)
+		}
+	})
+	ctx := context.Background()
+
+	if n, half, err := ai.RoundToNearestEven(ctx, 2.5); err != nil || n != 2 || !half {
+		t.Errorf("RoundToNearestEven(2.5) = %d, %t, %v; want 2, true", n, half, err)
+	}
+	if n, half, err := ai.RoundToNearestEven(ctx, -1.25); err != nil || n != -1 || half {
+		t.Errorf("RoundToNearestEven(-1.25) = %d, %t, %v; want -1, false", n, half, err)
+	}
+	if n, half, err := ai.RoundToNearestEven(ctx, 2.1); err != nil || n != 2 || half || roundings != 2 {
+		t.Errorf("RoundToNearestEven(2.1) = %d, %t, %v after %d questions; want 2, false after asking again", n, half, err, roundings)
+	}
+	if n, _, err := ai.RoundToNearestEven(ctx, 3.5); err == nil {
+		t.Errorf("RoundToNearestEven(3.5) = %d, want an error", n)
+	}
+	if want := []string{"2", "3", "3", "3"}; strings.Join(certified, ",") != strings.Join(want, ",") {
+		t.Errorf("Certified %v, want %v", certified, want)
+	}
+	if _, _, err := ai.RoundToNearestEven(ctx, math.NaN()); err == nil {
+		t.Error("RoundToNearestEven(NaN) succeeded, want an error")
+	}
+}
+
+func TestIsEvenAiLocal_RoundToNearestEven(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	for _, tc := range []struct {
+		f    float64
+		want int
+		half bool
+	}{
+		{2.5, 2, true},
+		{3.5, 4, true},
+		{-2.5, -2, true},
+		{2.4, 2, false},
+		{-1.6, -2, false},
+		{7, 7, false},
+	} {
+		if n, half, err := l.RoundToNearestEven(context.Background(), tc.f); err != nil || n != tc.want || half != tc.half {
+			t.Errorf("RoundToNearestEven(%v) = %d, %t, %v; want %d, %t", tc.f, n, half, err, tc.want, tc.half)
+		}
+	}
+	if _, _, err := l.RoundToNearestEven(context.Background(), math.Inf(1)); err == nil {
+		t.Error("RoundToNearestEven(+Inf) succeeded, want an error")
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/philwo/is-even-ai/core"
+)
+
+// geminiSequenceSchema is the structured output of questions about a sequence
+// of numbers.
+var geminiSequenceSchema = &genai.Schema{
+	Type:       genai.TypeObject,
+	Properties: map[string]*genai.Schema{"answer": {Type: genai.TypeBoolean}},
+	Required:   []string{"answer"},
+}
+
+// formatSequence lists nums for a prompt, e.g. "[3, 1, 2]".
+func formatSequence(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// AreAllEqual asks the model whether all of nums are equal, in a single
+// question about the whole list rather than one AreEqual per pair. Lists of
+// fewer than two numbers are trivially equal and answered without a query.
+//
+// Like ExtractAndCheck, the question bypasses middleware, but counts towards
+// Usage and the Budget and is recorded in the audit log. A response that is
+// not the requested JSON fails with an error wrapping ErrUnparsableAnswer.
+func (ai *IsEvenAiGemini) AreAllEqual(ctx context.Context, nums ...int) (*bool, error) {
+	if len(nums) < 2 {
+		trivial := true
+		return &trivial, nil
+	}
+	prompt := fmt.Sprintf("Are all the numbers in the list %s equal to each other?", formatSequence(nums))
+	return ai.askSequence(ctx, "areAllEqual", prompt, nums)
+}
+
+// AllDistinct asks the model whether no two of nums are equal, in a single
+// question about the whole list rather than one AreNotEqual per pair. Lists
+// of fewer than two numbers are trivially distinct and answered without a
+// query. Otherwise it behaves like AreAllEqual.
+func (ai *IsEvenAiGemini) AllDistinct(ctx context.Context, nums ...int) (*bool, error) {
+	if len(nums) < 2 {
+		trivial := true
+		return &trivial, nil
+	}
+	prompt := fmt.Sprintf("Are all the numbers in the list %s different from each other, i.e. does no number appear more than once?", formatSequence(nums))
+	return ai.askSequence(ctx, "allDistinct", prompt, nums)
+}
+
+// IsSortedAscending asks the model whether nums is sorted in ascending order,
+// i.e. no number is greater than the next one, e.g. to check the output of a
+// sort implementation. Lists of fewer than two numbers are trivially sorted and
+// answered without a query. Otherwise it behaves like AreAllEqual.
+func (ai *IsEvenAiGemini) IsSortedAscending(ctx context.Context, nums []int) (*bool, error) {
+	if len(nums) < 2 {
+		trivial := true
+		return &trivial, nil
+	}
+	prompt := fmt.Sprintf("Is the list %s sorted in ascending order, i.e. is no number greater than the number after it? Equal neighbors are allowed.", formatSequence(nums))
+	return ai.askSequence(ctx, "isSortedAscending", prompt, nums)
+}
+
+// IsStrictlyIncreasing is like IsSortedAscending, but asks whether every
+// number is less than the next one, so equal neighbors make it false.
+func (ai *IsEvenAiGemini) IsStrictlyIncreasing(ctx context.Context, nums []int) (*bool, error) {
+	if len(nums) < 2 {
+		trivial := true
+		return &trivial, nil
+	}
+	prompt := fmt.Sprintf("Is the list %s strictly increasing, i.e. is every number less than the number after it? Equal neighbors are not allowed.", formatSequence(nums))
+	return ai.askSequence(ctx, "isStrictlyIncreasing", prompt, nums)
+}
+
+// askSequence asks prompt, a yes or no question about nums, for a structured
+// answer.
+func (ai *IsEvenAiGemini) askSequence(ctx context.Context, predicate, prompt string, nums []int) (*bool, error) {
+	var out struct {
+		Answer *bool `json:"answer"`
+	}
+	info := CallInfo{Predicate: predicate, Args: slices.Clone(nums)}
+	return ai.askStructured(ctx, info, prompt, geminiSequenceSchema, &out, func() *bool { return out.Answer })
+}
+
+// askStructured asks prompt of a model answering JSON of the given schema,
+// decodes the answer into out and returns its true/false part, as reported by
+// answer. An answer without it fails with ErrUnparsableAnswer. The question
+// counts towards Usage and the Budget and is recorded in the audit log.
+func (ai *IsEvenAiGemini) askStructured(ctx context.Context, info CallInfo, prompt string, schema *genai.Schema, out any, answer func() *bool) (*bool, error) {
+	if err := ai.Drainer().Enter(); err != nil {
+		return nil, err
+	}
+	defer ai.Drainer().Leave()
+	if err := ai.usage.checkBudget(); err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := withCallTimeout(core.ContextWithCallInfo(ctx, info), ai.timeout)
+	defer cancel()
+	start := time.Now()
+	call := geminiCall{model: ai.modelName}
+	ai.logger.Debug("gemini request started", "model", call.model, "predicate", info.Predicate)
+	result, err := ai.structuredAnswer(callCtx, prompt, schema, out, answer, &call)
+	if err != nil {
+		ai.logger.Debug("gemini request failed", "model", call.model, "latency", time.Since(start), "err", err)
+	}
+	ai.audit(start, info, LabelsFromContext(ctx), prompt, call, result, err)
+	return result, err
+}
+
+// structuredAnswer sends prompt to a model answering JSON of the given schema
+// and decodes the answer into out.
+func (ai *IsEvenAiGemini) structuredAnswer(ctx context.Context, prompt string, schema *genai.Schema, out any, answer func() *bool, call *geminiCall) (*bool, error) {
+	model := ai.freeformModel()
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = schema
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content from Gemini API: %w", geminiError(call.model, err))
+	}
+	ai.recordUsage(ctx, call.model, resp)
+	call.rawAnswer = geminiResponseText(resp)
+	if err := json.Unmarshal([]byte(call.rawAnswer), out); err != nil || answer() == nil {
+		ai.logger.Warn("gemini answer not understood", "model", call.model, "text", call.rawAnswer)
+		return nil, fmt.Errorf("%w: %q", ErrUnparsableAnswer, call.rawAnswer)
+	}
+	return answer(), nil
+}
+
+// AreAllEqual reports whether all of nums are equal.
+func (l *IsEvenAiLocal) AreAllEqual(ctx context.Context, nums ...int) (*bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	equal := true
+	for _, n := range nums {
+		if n != nums[0] {
+			equal = false
+			break
+		}
+	}
+	return &equal, nil
+}
+
+// AllDistinct reports whether no two of nums are equal.
+func (l *IsEvenAiLocal) AllDistinct(ctx context.Context, nums ...int) (*bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	distinct := true
+	seen := make(map[int]bool, len(nums))
+	for _, n := range nums {
+		if seen[n] {
+			distinct = false
+			break
+		}
+		seen[n] = true
+	}
+	return &distinct, nil
+}
+
+// IsSortedAscending reports whether no number of nums is greater than the
+// next one.
+func (l *IsEvenAiLocal) IsSortedAscending(ctx context.Context, nums []int) (*bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sorted := slices.IsSorted(nums)
+	return &sorted, nil
+}
+
+// IsStrictlyIncreasing reports whether every number of nums is less than the
+// next one.
+func (l *IsEvenAiLocal) IsStrictlyIncreasing(ctx context.Context, nums []int) (*bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	increasing := true
+	for i := 1; i < len(nums); i++ {
+		if nums[i-1] >= nums[i] {
+			increasing = false
+			break
+		}
+	}
+	return &increasing, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_AreAllEqual(t *testing.T) {
+	var bodies []string
+	answer := `{"answer": true}`
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		geminitest.WriteAnswer(w, r, answer)
+	})
+	ctx := context.Background()
+
+	res, err := ai.AreAllEqual(ctx, 7, 7, 7, 7)
+	checkGeminiResult(t, res, err, true, "AreAllEqual", 7, 7, 7, 7)
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "[7, 7, 7, 7]") || !strings.Contains(bodies[0], `"responseMimeType":"application/json"`) {
+		t.Errorf("Expected a single structured question about the whole list, got %q", bodies)
+	}
+	answer = `{"answer": false}`
+	res, err = ai.AllDistinct(ctx, 1, 2, 1)
+	checkGeminiResult(t, res, err, false, "AllDistinct", 1, 2, 1)
+	if u := ai.UsageByPredicate(); u["areAllEqual"].Requests != 1 || u["allDistinct"].Requests != 1 {
+		t.Errorf("Expected one request per predicate, got %+v", u)
+	}
+
+	for _, nums := range [][]int{nil, {5}} {
+		res, err = ai.AreAllEqual(ctx, nums...)
+		checkGeminiResult(t, res, err, true, "AreAllEqual", nums...)
+		res, err = ai.AllDistinct(ctx, nums...)
+		checkGeminiResult(t, res, err, true, "AllDistinct", nums...)
+	}
+	if len(bodies) != 2 {
+		t.Errorf("Expected lists of fewer than two numbers to be answered without a query, got %d queries", len(bodies))
+	}
+
+	answer = "true"
+	if _, err := ai.AreAllEqual(ctx, 1, 2); !errors.Is(err, ErrUnparsableAnswer) {
+		t.Errorf("AreAllEqual() with a plain answer = %v, want ErrUnparsableAnswer", err)
+	}
+}
+
+func TestIsEvenAiGemini_IsSortedAscending(t *testing.T) {
+	var prompts []string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompts = append(prompts, string(body))
+		geminitest.WriteAnswer(w, r, `{"answer": true}`)
+	})
+	ctx := context.Background()
+
+	res, err := ai.IsSortedAscending(ctx, []int{1, 2, 2, 5})
+	checkGeminiResult(t, res, err, true, "IsSortedAscending", 1, 2, 2, 5)
+	res, err = ai.IsStrictlyIncreasing(ctx, []int{1, 2, 5})
+	checkGeminiResult(t, res, err, true, "IsStrictlyIncreasing", 1, 2, 5)
+	if len(prompts) != 2 || !strings.Contains(prompts[0], "ascending order") || !strings.Contains(prompts[1], "strictly increasing") {
+		t.Errorf("Expected one question per list, got %q", prompts)
+	}
+	res, err = ai.IsStrictlyIncreasing(ctx, []int{9})
+	checkGeminiResult(t, res, err, true, "IsStrictlyIncreasing", 9)
+	if len(prompts) != 2 {
+		t.Errorf("Expected a single number to be answered without a query, got %d queries", len(prompts))
+	}
+}
+
+func TestIsEvenAiLocal_IsSortedAscending(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	ctx := context.Background()
+	for _, tc := range []struct {
+		nums               []int
+		sorted, increasing bool
+	}{
+		{nil, true, true},
+		{[]int{3}, true, true},
+		{[]int{1, 2, 2, 5}, true, false},
+		{[]int{-3, 0, 4}, true, true},
+		{[]int{2, 1}, false, false},
+	} {
+		res, err := l.IsSortedAscending(ctx, tc.nums)
+		checkGeminiResult(t, res, err, tc.sorted, "IsSortedAscending", tc.nums...)
+		res, err = l.IsStrictlyIncreasing(ctx, tc.nums)
+		checkGeminiResult(t, res, err, tc.increasing, "IsStrictlyIncreasing", tc.nums...)
+	}
+}
+
+func TestIsEvenAiLocal_AreAllEqual(t *testing.T) {
+	l := NewIsEvenAiLocal()
+	ctx := context.Background()
+	for _, tc := range []struct {
+		nums            []int
+		equal, distinct bool
+	}{
+		{nil, true, true},
+		{[]int{3}, true, true},
+		{[]int{3, 3, 3}, true, false},
+		{[]int{1, 2, 3}, false, true},
+		{[]int{1, 2, 1}, false, false},
+	} {
+		res, err := l.AreAllEqual(ctx, tc.nums...)
+		checkGeminiResult(t, res, err, tc.equal, "AreAllEqual", tc.nums...)
+		res, err = l.AllDistinct(ctx, tc.nums...)
+		checkGeminiResult(t, res, err, tc.distinct, "AllDistinct", tc.nums...)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+// shutdown drains d and then closes c, even if ctx ended the wait early.
+func shutdown(ctx context.Context, d *core.Drainer, c io.Closer) error {
+	err := d.Drain(ctx)
+	return errors.Join(err, c.Close())
+}
+
+// Shutdown stops accepting queries, waits for those in flight until ctx is
+// done, and then closes the client, e.g. for a clean rollout. Queries made
+// after Shutdown fail with ErrShutdown.
+func (ai *IsEvenAiGemini) Shutdown(ctx context.Context) error {
+	return shutdown(ctx, ai.Drainer(), ai)
+}
+
+// Shutdown stops accepting queries and waits for those in flight until ctx is
+// done. Queries made after Shutdown fail with ErrShutdown.
+func (l *IsEvenAiLocal) Shutdown(ctx context.Context) error {
+	return shutdown(ctx, l.Drainer(), l)
+}
+
+// Shutdown stops accepting queries and waits for those in flight until ctx is
+// done. It then shuts down or, if they have no Shutdown method, closes the
+// providers of the pool. Queries made after Shutdown fail with ErrShutdown.
+func (p *IsEvenAiPool) Shutdown(ctx context.Context) error {
+	errs := []error{p.drainer.Drain(ctx)}
+	for _, provider := range p.providers {
+		if s, ok := provider.(interface{ Shutdown(context.Context) error }); ok {
+			errs = append(errs, s.Shutdown(ctx))
+		} else {
+			errs = append(errs, provider.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIsEvenAiLocal_Shutdown(t *testing.T) {
+	ai := NewIsEvenAiLocal()
+	if err := ai.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if _, err := ai.IsEven(2); !errors.Is(err, ErrShutdown) {
+		t.Errorf("IsEven() after Shutdown() = %v, want ErrShutdown", err)
+	}
+}
+
+func TestIsEvenAiPool_Shutdown(t *testing.T) {
+	var stubs []*stubProvider
+	pool, err := NewIsEvenAiPool(2, func() (Provider, error) {
+		stub := &stubProvider{answer: answerAlways(true)}
+		stubs = append(stubs, stub)
+		return stub, nil
+	})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiPool() failed: %v", err)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	for i, stub := range stubs {
+		if !stub.closed {
+			t.Errorf("Provider %d was not closed", i)
+		}
+	}
+	if _, err := pool.IsEven(2); !errors.Is(err, ErrShutdown) {
+		t.Errorf("IsEven() after Shutdown() = %v, want ErrShutdown", err)
+	}
+}
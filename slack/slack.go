@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package slack answers Slack slash commands such as "/iseven 42" with a
+// provider:
+//
+//	http.Handle("/slack/iseven", slack.NewHandler(ai.IsEvenAiCore, slack.Options{
+//		SigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+//	}))
+//
+// Slack expects a reply within three seconds, which AI models don't always
+// manage, so the handler acknowledges each command immediately and posts the
+// answer to the command's response URL once it is known.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+// maxRequestAge is how old a request may be before it is rejected as a
+// possible replay, as recommended by Slack.
+const maxRequestAge = 5 * time.Minute
+
+// Options configures a Handler.
+type Options struct {
+	// SigningSecret verifies that requests come from Slack. Required.
+	SigningSecret string
+	// RateLimit and Burst limit the commands per second of each workspace.
+	// Defaults to one per second with a burst of 5.
+	RateLimit rate.Limit
+	Burst     int
+	// Timeout bounds how long an answer may take. Defaults to 30 seconds.
+	Timeout time.Duration
+	// HTTPClient posts answers to response URLs. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	Logger     *slog.Logger
+}
+
+// Handler is an http.Handler for Slack slash command requests.
+type Handler struct {
+	core *is_even_ai.IsEvenAiCore
+	opts Options
+	now  func() time.Time
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter // By workspace (team) ID
+	wg       sync.WaitGroup           // Pending answers
+}
+
+// NewHandler returns a Handler answering with core.
+func NewHandler(core *is_even_ai.IsEvenAiCore, opts Options) *Handler {
+	if opts.SigningSecret == "" {
+		panic("slack: signing secret required")
+	}
+	if opts.RateLimit == 0 {
+		opts.RateLimit = 1
+	}
+	if opts.Burst == 0 {
+		opts.Burst = 5
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	return &Handler{core: core, opts: opts, now: time.Now, limiters: map[string]*rate.Limiter{}}
+}
+
+// message is a slash command response.
+type message struct {
+	ResponseType string `json:"response_type"` // "ephemeral" or "in_channel"
+	Text         string `json:"text"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64<<10))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r.Header, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	command := form.Get("command")
+	n, err := strconv.Atoi(strings.TrimSpace(form.Get("text")))
+	if err != nil {
+		writeMessage(w, message{"ephemeral", fmt.Sprintf("Usage: %s NUMBER, e.g. %s 42", command, command)})
+		return
+	}
+	if !h.limiter(form.Get("team_id")).Allow() {
+		writeMessage(w, message{"ephemeral", "Too many questions from this workspace, please try again in a moment."})
+		return
+	}
+
+	responseURL := form.Get("response_url")
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.answer(n, responseURL)
+	}()
+	w.WriteHeader(http.StatusOK) // Acknowledge; the answer follows via responseURL
+}
+
+// answer asks whether n is even and posts the answer to responseURL.
+func (h *Handler) answer(n int, responseURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.opts.Timeout)
+	defer cancel()
+	msg := message{ResponseType: "in_channel"}
+	switch even, err := h.core.WithContext(ctx).IsEven(n); {
+	case err != nil:
+		h.opts.Logger.Warn("slack command failed", "n", n, "err", err)
+		msg = message{"ephemeral", fmt.Sprintf("Sorry, I could not find out whether %d is even.", n)}
+	case even == nil:
+		msg.Text = fmt.Sprintf("The AI could not decide whether %d is even.", n)
+	case *even:
+		msg.Text = fmt.Sprintf("%d is even.", n)
+	default:
+		msg.Text = fmt.Sprintf("%d is odd.", n)
+	}
+
+	body, _ := json.Marshal(msg)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		h.opts.Logger.Error("slack response failed", "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.opts.HTTPClient.Do(req)
+	if err != nil {
+		h.opts.Logger.Error("slack response failed", "err", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		h.opts.Logger.Error("slack response rejected", "status", resp.Status)
+	}
+}
+
+// Wait blocks until all pending answers have been posted, e.g. before shutdown.
+func (h *Handler) Wait() {
+	h.wg.Wait()
+}
+
+// verify checks the request signature as described in
+// https://api.slack.com/authentication/verifying-requests-from-slack.
+func (h *Handler) verify(header http.Header, body []byte) bool {
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := h.now().Sub(time.Unix(sec, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return false
+	}
+	sig, ok := strings.CutPrefix(header.Get("X-Slack-Signature"), "v0=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, sign(h.opts.SigningSecret, ts, body))
+}
+
+// sign computes the version 0 signature of a request.
+func sign(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func (h *Handler) limiter(team string) *rate.Limiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l, ok := h.limiters[team]
+	if !ok {
+		l = rate.NewLimiter(h.opts.RateLimit, h.opts.Burst)
+		h.limiters[team] = l
+	}
+	return l
+}
+
+func writeMessage(w http.ResponseWriter, msg message) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(msg)
+}
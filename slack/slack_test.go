@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package slack
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+const testSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// responseCollector is a fake Slack response URL endpoint.
+type responseCollector struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []message
+}
+
+func newResponseCollector(t *testing.T) *responseCollector {
+	c := &responseCollector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Invalid response message: %v", err)
+		}
+		c.mu.Lock()
+		c.messages = append(c.messages, msg)
+		c.mu.Unlock()
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// command sends a signed slash command to h.
+func command(h http.Handler, team, text, responseURL string) *httptest.ResponseRecorder {
+	body := url.Values{
+		"command":      {"/iseven"},
+		"team_id":      {team},
+		"text":         {text},
+		"response_url": {responseURL},
+	}.Encode()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest("POST", "/slack", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(sign(testSecret, ts, []byte(body))))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	collector := newResponseCollector(t)
+	h := NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, Options{SigningSecret: testSecret})
+	for _, text := range []string{"42", " 7 "} {
+		if rec := command(h, "T1", text, collector.URL); rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+			t.Errorf("Expected empty acknowledgement, got %d %q", rec.Code, rec.Body.String())
+		}
+		h.Wait()
+	}
+	want := []message{{"in_channel", "42 is even."}, {"in_channel", "7 is odd."}}
+	if len(collector.messages) != 2 || collector.messages[0] != want[0] || collector.messages[1] != want[1] {
+		t.Errorf("Posted messages = %+v; want %+v", collector.messages, want)
+	}
+}
+
+func TestHandler_Usage(t *testing.T) {
+	h := NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, Options{SigningSecret: testSecret})
+	rec := command(h, "T1", "forty-two", "")
+	if !strings.Contains(rec.Body.String(), `"text":"Usage: /iseven NUMBER, e.g. /iseven 42"`) {
+		t.Errorf("Expected usage message, got %q", rec.Body.String())
+	}
+}
+
+func TestHandler_Failure(t *testing.T) {
+	collector := newResponseCollector(t)
+	core := is_even_ai.NewIsEvenAiCore(is_even_ai.DefaultGeminiPromptTemplates, func(string) (*bool, error) {
+		return nil, errors.New("model unavailable")
+	})
+	h := NewHandler(core, Options{SigningSecret: testSecret})
+	command(h, "T1", "2", collector.URL)
+	h.Wait()
+	if len(collector.messages) != 1 || collector.messages[0].ResponseType != "ephemeral" {
+		t.Errorf("Expected one ephemeral error message, got %+v", collector.messages)
+	}
+}
+
+func TestHandler_RateLimitPerWorkspace(t *testing.T) {
+	collector := newResponseCollector(t)
+	h := NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, Options{SigningSecret: testSecret, RateLimit: 1e-9, Burst: 1})
+	command(h, "T1", "2", collector.URL)
+	if rec := command(h, "T1", "2", collector.URL); !strings.Contains(rec.Body.String(), "Too many questions") {
+		t.Errorf("Expected second command of T1 to be rate limited, got %q", rec.Body.String())
+	}
+	if rec := command(h, "T2", "2", collector.URL); rec.Body.Len() != 0 {
+		t.Errorf("Expected T2 to be unaffected, got %q", rec.Body.String())
+	}
+	h.Wait()
+}
+
+func TestHandler_Verification(t *testing.T) {
+	h := NewHandler(is_even_ai.NewIsEvenAiLocal().IsEvenAiCore, Options{SigningSecret: testSecret})
+	body := "text=42"
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	testCases := []struct {
+		name, ts, sig string
+	}{
+		{"Missing", "", ""},
+		{"WrongSecret", now, "v0=" + hex.EncodeToString(sign("other secret", now, []byte(body)))},
+		{"Replayed", old, "v0=" + hex.EncodeToString(sign(testSecret, old, []byte(body)))},
+		{"NotHex", now, "v0=xyz"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/slack", strings.NewReader(body))
+			req.Header.Set("X-Slack-Request-Timestamp", tc.ts)
+			req.Header.Set("X-Slack-Signature", tc.sig)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+const jobSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id        TEXT PRIMARY KEY,
+	predicate TEXT NOT NULL,
+	numbers   TEXT NOT NULL,    -- JSON array
+	status    TEXT NOT NULL,
+	created   INTEGER NOT NULL, -- Unix milliseconds
+	updated   INTEGER NOT NULL  -- Unix milliseconds
+);
+CREATE INDEX IF NOT EXISTS jobs_status ON jobs (status, created);
+CREATE TABLE IF NOT EXISTS job_results (
+	id     INTEGER PRIMARY KEY,
+	job_id TEXT NOT NULL REFERENCES jobs (id),
+	idx    INTEGER NOT NULL,
+	answer INTEGER,           -- 1 for true, 0 for false, NULL if undefined
+	error  TEXT NOT NULL,
+	UNIQUE (job_id, idx)
+);
+`
+
+var _ is_even_ai.JobStore = (*Store)(nil)
+
+// AddJob stores a new job and its results, if any.
+func (s *Store) AddJob(ctx context.Context, job is_even_ai.Job) error {
+	numbers, err := json.Marshal(job.Numbers)
+	if err != nil {
+		return fmt.Errorf("failed to store job: %w", err)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to store job: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO jobs (id, predicate, numbers, status, created, updated) VALUES (?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Predicate, string(numbers), string(job.Status), job.Created.UnixMilli(), job.Updated.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to store job: %w", err)
+	}
+	for _, res := range job.Results {
+		if err := addJobResult(ctx, tx, job.ID, res); err != nil {
+			return fmt.Errorf("failed to store job: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store job: %w", err)
+	}
+	return nil
+}
+
+// Job returns the job with the given ID and its results, or an error wrapping
+// is_even_ai.ErrJobNotFound.
+func (s *Store) Job(ctx context.Context, id string) (is_even_ai.Job, error) {
+	job := is_even_ai.Job{ID: id}
+	var (
+		numbers          string
+		status           string
+		created, updated int64
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT predicate, numbers, status, created, updated FROM jobs WHERE id = ?`, id).
+		Scan(&job.Predicate, &numbers, &status, &created, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return is_even_ai.Job{}, fmt.Errorf("%w: %s", is_even_ai.ErrJobNotFound, id)
+	}
+	if err != nil {
+		return is_even_ai.Job{}, fmt.Errorf("failed to read job store: %w", err)
+	}
+	if err := json.Unmarshal([]byte(numbers), &job.Numbers); err != nil {
+		return is_even_ai.Job{}, fmt.Errorf("failed to read job store: %w", err)
+	}
+	job.Status = is_even_ai.JobStatus(status)
+	job.Created = time.UnixMilli(created)
+	job.Updated = time.UnixMilli(updated)
+
+	rows, err := s.db.QueryContext(ctx, `SELECT idx, answer, error FROM job_results WHERE job_id = ? ORDER BY id`, id)
+	if err != nil {
+		return is_even_ai.Job{}, fmt.Errorf("failed to read job store: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			res    is_even_ai.JobResult
+			answer sql.NullBool
+		)
+		if err := rows.Scan(&res.Index, &answer, &res.Err); err != nil {
+			return is_even_ai.Job{}, fmt.Errorf("failed to read job store: %w", err)
+		}
+		res.Answer = answerOfColumn(answer)
+		job.Results = append(job.Results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return is_even_ai.Job{}, fmt.Errorf("failed to read job store: %w", err)
+	}
+	return job, nil
+}
+
+// AddJobResult stores the answer to one number of a job.
+func (s *Store) AddJobResult(ctx context.Context, id string, res is_even_ai.JobResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to store job result: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := touchJob(ctx, tx, id, `UPDATE jobs SET updated = ? WHERE id = ?`, s.now().UnixMilli(), id); err != nil {
+		return err
+	}
+	if err := addJobResult(ctx, tx, id, res); err != nil {
+		return fmt.Errorf("failed to store job result: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store job result: %w", err)
+	}
+	return nil
+}
+
+// SetJobStatus updates the status of a job.
+func (s *Store) SetJobStatus(ctx context.Context, id string, status is_even_ai.JobStatus) error {
+	return touchJob(ctx, s.db, id, `UPDATE jobs SET status = ?, updated = ? WHERE id = ?`, string(status), s.now().UnixMilli(), id)
+}
+
+// UnfinishedJobs returns the IDs of the jobs that are queued or running,
+// oldest first.
+func (s *Store) UnfinishedJobs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM jobs WHERE status != ? ORDER BY created, rowid`, string(is_even_ai.JobDone))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job store: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to read job store: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read job store: %w", err)
+	}
+	return ids, nil
+}
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// touchJob runs an update of the job with the given ID, failing with an error
+// wrapping is_even_ai.ErrJobNotFound if there is no such job.
+func touchJob(ctx context.Context, db execer, id, query string, args ...any) error {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %s", is_even_ai.ErrJobNotFound, id)
+	}
+	return nil
+}
+
+// addJobResult inserts res into job_results.
+func addJobResult(ctx context.Context, db execer, id string, res is_even_ai.JobResult) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO job_results (job_id, idx, answer, error) VALUES (?, ?, ?, ?)`,
+		id, res.Index, answerValue(res.Answer), res.Err)
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func TestJobStore(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	created := time.UnixMilli(1_700_000_000_000)
+	for i, id := range []string{"first", "second"} {
+		job := is_even_ai.Job{ID: id, Predicate: "isEven", Numbers: []int{1, 2}, Status: is_even_ai.JobQueued, Created: created.Add(time.Duration(i) * time.Second)}
+		if err := s.AddJob(ctx, job); err != nil {
+			t.Fatalf("AddJob(%s) failed: %v", id, err)
+		}
+	}
+	if err := s.AddJobResult(ctx, "first", is_even_ai.JobResult{Index: 1, Answer: is_even_ai.AnswerTrue}); err != nil {
+		t.Fatalf("AddJobResult() failed: %v", err)
+	}
+	if err := s.AddJobResult(ctx, "first", is_even_ai.JobResult{Index: 0, Err: "quota exceeded"}); err != nil {
+		t.Fatalf("AddJobResult() failed: %v", err)
+	}
+	if err := s.SetJobStatus(ctx, "second", is_even_ai.JobDone); err != nil {
+		t.Fatalf("SetJobStatus() failed: %v", err)
+	}
+
+	job, err := s.Job(ctx, "first")
+	if err != nil {
+		t.Fatalf("Job(first) failed: %v", err)
+	}
+	want := []is_even_ai.JobResult{{Index: 1, Answer: is_even_ai.AnswerTrue}, {Index: 0, Answer: is_even_ai.AnswerUndefined, Err: "quota exceeded"}}
+	if job.Predicate != "isEven" || !slices.Equal(job.Numbers, []int{1, 2}) || !job.Created.Equal(created) || !slices.Equal(job.Results, want) {
+		t.Errorf("Job(first) = %+v", job)
+	}
+	if ids, err := s.UnfinishedJobs(ctx); err != nil || !slices.Equal(ids, []string{"first"}) {
+		t.Errorf("UnfinishedJobs() = %v, %v; want [first]", ids, err)
+	}
+
+	if _, err := s.Job(ctx, "missing"); !errors.Is(err, is_even_ai.ErrJobNotFound) {
+		t.Errorf("Job(missing) = %v, want ErrJobNotFound", err)
+	}
+	if err := s.SetJobStatus(ctx, "missing", is_even_ai.JobDone); !errors.Is(err, is_even_ai.ErrJobNotFound) {
+		t.Errorf("SetJobStatus(missing) = %v, want ErrJobNotFound", err)
+	}
+	if err := s.AddJobResult(ctx, "first", is_even_ai.JobResult{Index: 1}); err == nil {
+		t.Error("AddJobResult() for an answered number succeeded")
+	}
+}
+
+func TestJobQueue_ResumesAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	queue := is_even_ai.NewJobQueue(s, is_even_ai.NewIsEvenAiLocal())
+	id, err := queue.Submit(context.Background(), "isEven", []int{2, 5})
+	if err != nil {
+		t.Fatalf("Submit() failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	// A new process picks up the job submitted before the restart.
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed on reopen: %v", err)
+	}
+	defer s.Close()
+	queue = is_even_ai.NewJobQueue(s, is_even_ai.NewIsEvenAiLocal())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() { _ = queue.Run(ctx) }()
+	for {
+		job, err := queue.Job(ctx, id)
+		if err != nil {
+			t.Fatalf("Job() failed: %v", err)
+		}
+		if job.Status == is_even_ai.JobDone {
+			if done, total := job.Progress(); done != 2 || total != 2 {
+				t.Errorf("Progress() = %d, %d; want 2, 2", done, total)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package sqlitestore keeps a durable record of the answers given by the
+// is-even-ai predicates in a SQLite database, and answers questions about it:
+//
+//	store, err := sqlitestore.Open("answers.db")
+//	...
+//	defer store.Close()
+//	ai.Use(store.Middleware(slog.Default()))
+//	...
+//	evens, err := store.Numbers(ctx, "isEven", is_even_ai.AnswerTrue)
+//
+// A Store is also the durable is_even_ai.JobStore of a JobQueue.
+//
+// It uses a pure Go SQLite driver, so no C toolchain is needed.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS answers (
+	id        INTEGER PRIMARY KEY,
+	time      INTEGER NOT NULL, -- Unix milliseconds
+	predicate TEXT NOT NULL,
+	a         INTEGER NOT NULL,
+	b         INTEGER,          -- NULL for predicates of one number
+	answer    INTEGER,          -- 1 for true, 0 for false, NULL if undefined
+	provider  TEXT NOT NULL,
+	model     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS answers_question ON answers (predicate, a, b);
+`
+
+// Record is one stored answer.
+type Record struct {
+	ID        int64
+	Time      time.Time
+	Predicate string // Prompt name, e.g. "isEven"
+	Args      []int
+	Answer    is_even_ai.Answer
+	Provider  string
+	Model     string
+}
+
+// Store records answers in a SQLite database. It is safe for concurrent use.
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens or creates the database at path (":memory:" for a temporary one)
+// and prepares its schema. Call Close when done.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open answer store: %w", err)
+	}
+	// SQLite serializes writes anyway, and a single connection keeps an
+	// in-memory database alive and shared between queries.
+	db.SetMaxOpenConns(1)
+	s, err := New(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New returns a Store using db, which must be a SQLite database, and prepares
+// its schema. Closing the Store closes db.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create answer store schema: %w", err)
+	}
+	if _, err := db.Exec(jobSchema); err != nil {
+		return nil, fmt.Errorf("failed to create job store schema: %w", err)
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record stores rec. Its ID is ignored, and a zero Time is replaced by the
+// current time.
+func (s *Store) Record(ctx context.Context, rec Record) error {
+	if len(rec.Args) != 1 && len(rec.Args) != 2 {
+		return fmt.Errorf("failed to store answer: %s needs one or two arguments, got %d", rec.Predicate, len(rec.Args))
+	}
+	if rec.Time.IsZero() {
+		rec.Time = s.now()
+	}
+	var b sql.NullInt64
+	if len(rec.Args) == 2 {
+		b = sql.NullInt64{Int64: int64(rec.Args[1]), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO answers (time, predicate, a, b, answer, provider, model) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.Time.UnixMilli(), rec.Predicate, rec.Args[0], b, answerValue(rec.Answer), rec.Provider, rec.Model)
+	if err != nil {
+		return fmt.Errorf("failed to store answer: %w", err)
+	}
+	return nil
+}
+
+// Middleware stores every answer given by the provider, including undefined
+// ones, with the provider and configured model from the query's CallInfo.
+// Failed queries and prompts without CallInfo are not stored. Store failures
+// don't fail the query; they are logged to logger if it is not nil.
+func (s *Store) Middleware(logger *slog.Logger) is_even_ai.Middleware {
+	return func(next is_even_ai.QueryHandler) is_even_ai.QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			res, err := next(ctx, prompt)
+			info, ok := is_even_ai.CallInfoFromContext(ctx)
+			if err != nil || !ok {
+				return res, err
+			}
+			rec := Record{
+				Predicate: info.Predicate,
+				Args:      info.Args,
+				Answer:    answerOf(res),
+				Provider:  info.Source.Provider,
+				Model:     info.Source.Model,
+			}
+			// Store the answer even if the caller has given up on it by now.
+			if storeErr := s.Record(context.WithoutCancel(ctx), rec); storeErr != nil && logger != nil {
+				logger.Error("answer not stored", "predicate", info.Predicate, "args", info.Args, "err", storeErr)
+			}
+			return res, err
+		}
+	}
+}
+
+// Latest returns the most recent answer to predicate applied to args, and
+// whether there is one.
+func (s *Store) Latest(ctx context.Context, predicate string, args ...int) (Record, bool, error) {
+	recs, err := s.query(ctx, predicate, args, "ORDER BY time DESC, id DESC LIMIT 1")
+	if err != nil || len(recs) == 0 {
+		return Record{}, false, err
+	}
+	return recs[0], true, nil
+}
+
+// History returns all answers to predicate applied to args, oldest first.
+func (s *Store) History(ctx context.Context, predicate string, args ...int) ([]Record, error) {
+	return s.query(ctx, predicate, args, "ORDER BY time, id")
+}
+
+// Numbers returns, in ascending order, the numbers whose latest answer to the
+// one-number predicate is answer, e.g. all numbers known to be even.
+func (s *Store) Numbers(ctx context.Context, predicate string, answer is_even_ai.Answer) ([]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a FROM answers AS x
+		WHERE predicate = ? AND b IS NULL AND answer IS ?
+			AND id = (SELECT id FROM answers WHERE predicate = x.predicate AND a = x.a AND b IS NULL ORDER BY time DESC, id DESC LIMIT 1)
+		ORDER BY a`,
+		predicate, answerValue(answer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query answer store: %w", err)
+	}
+	defer rows.Close()
+	var numbers []int
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return nil, fmt.Errorf("failed to read answer store: %w", err)
+		}
+		numbers = append(numbers, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read answer store: %w", err)
+	}
+	return numbers, nil
+}
+
+// query returns the answers to predicate applied to args, ordered by order.
+func (s *Store) query(ctx context.Context, predicate string, args []int, order string) ([]Record, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.New("failed to query answer store: need one or two arguments")
+	}
+	var b sql.NullInt64
+	if len(args) == 2 {
+		b = sql.NullInt64{Int64: int64(args[1]), Valid: true}
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, time, a, b, answer, provider, model FROM answers
+		WHERE predicate = ? AND a = ? AND b IS ? `+order,
+		predicate, args[0], b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query answer store: %w", err)
+	}
+	defer rows.Close()
+	var recs []Record
+	for rows.Next() {
+		var (
+			rec    = Record{Predicate: predicate}
+			millis int64
+			a      int
+			b      sql.NullInt64
+			answer sql.NullBool
+		)
+		if err := rows.Scan(&rec.ID, &millis, &a, &b, &answer, &rec.Provider, &rec.Model); err != nil {
+			return nil, fmt.Errorf("failed to read answer store: %w", err)
+		}
+		rec.Time = time.UnixMilli(millis)
+		rec.Args = []int{a}
+		if b.Valid {
+			rec.Args = append(rec.Args, int(b.Int64))
+		}
+		rec.Answer = answerOfColumn(answer)
+		recs = append(recs, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read answer store: %w", err)
+	}
+	return recs, nil
+}
+
+// answerValue converts answer to its column value.
+func answerValue(answer is_even_ai.Answer) sql.NullBool {
+	switch answer {
+	case is_even_ai.AnswerTrue:
+		return sql.NullBool{Bool: true, Valid: true}
+	case is_even_ai.AnswerFalse:
+		return sql.NullBool{Valid: true}
+	default:
+		return sql.NullBool{}
+	}
+}
+
+// answerOfColumn converts a column value to an Answer.
+func answerOfColumn(answer sql.NullBool) is_even_ai.Answer {
+	switch {
+	case !answer.Valid:
+		return is_even_ai.AnswerUndefined
+	case answer.Bool:
+		return is_even_ai.AnswerTrue
+	default:
+		return is_even_ai.AnswerFalse
+	}
+}
+
+// answerOf converts a predicate result to an Answer.
+func answerOf(res *bool) is_even_ai.Answer {
+	switch {
+	case res == nil:
+		return is_even_ai.AnswerUndefined
+	case *res:
+		return is_even_ai.AnswerTrue
+	default:
+		return is_even_ai.AnswerFalse
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqlitestore
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	is_even_ai "github.com/philwo/is-even-ai"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestMiddleware(t *testing.T) {
+	s := openTestStore(t)
+	ai := is_even_ai.NewIsEvenAiLocal()
+	ai.Use(s.Middleware(nil))
+	ctx := context.Background()
+
+	for _, n := range []int{4, 7, 2} {
+		if _, err := ai.IsEven(n); err != nil {
+			t.Fatalf("IsEven(%d) failed: %v", n, err)
+		}
+	}
+	if _, err := ai.IsGreaterThan(3, 1); err != nil {
+		t.Fatalf("IsGreaterThan(3, 1) failed: %v", err)
+	}
+
+	rec, ok, err := s.Latest(ctx, "isEven", 4)
+	if err != nil || !ok {
+		t.Fatalf("Latest(isEven, 4) = %v, %v; want a record", ok, err)
+	}
+	if rec.Answer != is_even_ai.AnswerTrue || rec.Provider != "local" || rec.Model != "local" || !slices.Equal(rec.Args, []int{4}) {
+		t.Errorf("Latest(isEven, 4) = %+v", rec)
+	}
+	rec, ok, err = s.Latest(ctx, "isGreaterThan", 3, 1)
+	if err != nil || !ok || rec.Answer != is_even_ai.AnswerTrue || !slices.Equal(rec.Args, []int{3, 1}) {
+		t.Errorf("Latest(isGreaterThan, 3, 1) = %+v, %v, %v", rec, ok, err)
+	}
+	if _, ok, err := s.Latest(ctx, "isGreaterThan", 3); ok || err != nil {
+		t.Errorf("Latest(isGreaterThan, 3) = %v, %v; want no record", ok, err)
+	}
+
+	evens, err := s.Numbers(ctx, "isEven", is_even_ai.AnswerTrue)
+	if err != nil || !slices.Equal(evens, []int{2, 4}) {
+		t.Errorf("Numbers(isEven, true) = %v, %v; want [2 4]", evens, err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	start := time.UnixMilli(1_700_000_000_000)
+	for i, answer := range []is_even_ai.Answer{is_even_ai.AnswerUndefined, is_even_ai.AnswerFalse, is_even_ai.AnswerTrue} {
+		rec := Record{Time: start.Add(time.Duration(i) * time.Second), Predicate: "isEven", Args: []int{6}, Answer: answer, Provider: "gemini", Model: "gemini-2.0-flash-lite"}
+		if err := s.Record(ctx, rec); err != nil {
+			t.Fatalf("Record() failed: %v", err)
+		}
+	}
+
+	history, err := s.History(ctx, "isEven", 6)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("History(isEven, 6) = %d records, %v; want 3", len(history), err)
+	}
+	if history[0].Answer != is_even_ai.AnswerUndefined || !history[0].Time.Equal(start) || history[2].Answer != is_even_ai.AnswerTrue {
+		t.Errorf("History(isEven, 6) = %+v", history)
+	}
+
+	// Only the latest answer counts.
+	if odds, err := s.Numbers(ctx, "isEven", is_even_ai.AnswerFalse); err != nil || len(odds) != 0 {
+		t.Errorf("Numbers(isEven, false) = %v, %v; want none", odds, err)
+	}
+}
+
+func TestRecord_InvalidArgs(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Record(context.Background(), Record{Predicate: "isEven"}); err == nil {
+		t.Error("Record() without arguments succeeded")
+	}
+}
+
+func TestOpen_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if err := s.Record(context.Background(), Record{Predicate: "isOdd", Args: []int{1}, Answer: is_even_ai.AnswerTrue}); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed on reopen: %v", err)
+	}
+	defer s.Close()
+	if _, ok, err := s.Latest(context.Background(), "isOdd", 1); !ok || err != nil {
+		t.Errorf("Latest(isOdd, 1) after reopen = %v, %v; want a record", ok, err)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the buckets of LatencyHistogram.
+var latencyBounds = []time.Duration{
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// Stats counts queries, errors and cache hits, and records latency
+// histograms per provider and predicate, so operators can inspect a running
+// instance without metrics infrastructure. Count queries with
+// Middleware and cache lookups with Cache:
+//
+//	stats := &is_even_ai.Stats{Usage: ai.Usage, Budget: ai.BudgetStatus}
+//	ai.Use(stats.Middleware(), is_even_ai.CacheMiddleware(stats.Cache(cache)))
+//	stats.Publish("isevenai")
+//
+// The zero value is ready to use. Stats is safe for concurrent use.
+type Stats struct {
+	// Usage and Budget, if set, add a client's token usage and budget status
+	// to snapshots, and UsageByPredicate its breakdown by predicate.
+	Usage            func() Usage
+	UsageByPredicate func() map[string]Usage
+	Budget           func() BudgetStatus
+	// LabelNames are the names of the labels (see WithLabels) whose values
+	// break down the counters, e.g. "team". Other labels are ignored, so that
+	// labels like request IDs don't grow the snapshots without bounds.
+	// UsageByLabel, if set, adds the usage by value of each of them.
+	LabelNames   []string
+	UsageByLabel func(name string) map[string]Usage
+
+	requests, errors, undefined atomic.Int64
+	cacheHits, cacheMisses      atomic.Int64
+
+	mu      sync.Mutex
+	latency map[latencyKey]*LatencyHistogram
+	labels  map[label]*LabelStats
+}
+
+// LabelStats counts the queries with one value of a label.
+type LabelStats struct {
+	Requests  int64  `json:"requests"`
+	Errors    int64  `json:"errors"`
+	Undefined int64  `json:"undefined"`
+	Usage     *Usage `json:"usage,omitempty"`
+}
+
+// LatencyHistogram is the latency distribution of the queries about one
+// predicate answered by one provider.
+type LatencyHistogram struct {
+	Provider  string        `json:"provider"`
+	Predicate string        `json:"predicate"`
+	Count     int64         `json:"count"`
+	Sum       time.Duration `json:"sum_ns"`
+	// Counts[i] counts the queries that took at most Bounds[i], but longer
+	// than Bounds[i-1]. The last count is of queries slower than all bounds.
+	Bounds []time.Duration `json:"bounds_ns"`
+	Counts []int64         `json:"counts"`
+}
+
+// Mean returns the average latency, or 0 if there were no queries.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// observe adds a query that took d.
+func (h *LatencyHistogram) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(h.Bounds, d)
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// StatsSnapshot is the state of Stats at one point in time.
+type StatsSnapshot struct {
+	Requests    int64         `json:"requests"`
+	Errors      int64         `json:"errors"`
+	Undefined   int64         `json:"undefined"`
+	CacheHits   int64         `json:"cache_hits"`
+	CacheMisses int64         `json:"cache_misses"`
+	Usage       *Usage        `json:"usage,omitempty"`
+	Budget      *BudgetStatus `json:"budget,omitempty"`
+	// UsageByPredicate breaks Usage down by predicate.
+	UsageByPredicate map[string]Usage `json:"usage_by_predicate,omitempty"`
+	// Latency holds a histogram per provider and predicate, sorted by both.
+	Latency []LatencyHistogram `json:"latency,omitempty"`
+	// ByLabel holds the counters by label name and value, for
+	// Stats.LabelNames.
+	ByLabel map[string]map[string]LabelStats `json:"by_label,omitempty"`
+}
+
+// Snapshot returns the current counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	snap := StatsSnapshot{
+		Requests:    s.requests.Load(),
+		Errors:      s.errors.Load(),
+		Undefined:   s.undefined.Load(),
+		CacheHits:   s.cacheHits.Load(),
+		CacheMisses: s.cacheMisses.Load(),
+	}
+	if s.Usage != nil {
+		u := s.Usage()
+		snap.Usage = &u
+	}
+	if s.UsageByPredicate != nil {
+		snap.UsageByPredicate = s.UsageByPredicate()
+	}
+	if s.Budget != nil {
+		b := s.Budget()
+		snap.Budget = &b
+	}
+	s.mu.Lock()
+	for _, h := range s.latency {
+		h := *h
+		h.Bounds, h.Counts = slices.Clone(h.Bounds), slices.Clone(h.Counts)
+		snap.Latency = append(snap.Latency, h)
+	}
+	byLabel := func(name string) map[string]LabelStats {
+		if snap.ByLabel == nil {
+			snap.ByLabel = map[string]map[string]LabelStats{}
+		}
+		if snap.ByLabel[name] == nil {
+			snap.ByLabel[name] = map[string]LabelStats{}
+		}
+		return snap.ByLabel[name]
+	}
+	for l, ls := range s.labels {
+		byLabel(l.name)[l.value] = *ls
+	}
+	s.mu.Unlock()
+	if s.UsageByLabel != nil {
+		for _, name := range s.LabelNames {
+			for value, u := range s.UsageByLabel(name) {
+				values := byLabel(name)
+				ls := values[value]
+				ls.Usage = &u
+				values[value] = ls
+			}
+		}
+	}
+	slices.SortFunc(snap.Latency, func(a, b LatencyHistogram) int {
+		if c := strings.Compare(a.Provider, b.Provider); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Predicate, b.Predicate)
+	})
+	return snap
+}
+
+// Middleware counts the queries passing through it, and how many of them
+// failed or were answered as undefined, in total and by LabelNames. It
+// records their latency by the provider and predicate in their CallInfo; put
+// it after caches to leave cache hits out of the histograms.
+func (s *Stats) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			s.requests.Add(1)
+			start := time.Now()
+			res, err := next(ctx, prompt)
+			if info, ok := CallInfoFromContext(ctx); ok {
+				s.observeLatency(info, time.Since(start))
+			}
+			switch {
+			case err != nil:
+				s.errors.Add(1)
+			case res == nil:
+				s.undefined.Add(1)
+			}
+			s.countLabels(LabelsFromContext(ctx), res, err)
+			return res, err
+		}
+	}
+}
+
+// countLabels counts a query with labels by LabelNames.
+func (s *Stats) countLabels(labels Labels, res *bool, err error) {
+	if len(labels) == 0 || len(s.LabelNames) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range s.LabelNames {
+		value, ok := labels[name]
+		if !ok {
+			continue
+		}
+		if s.labels == nil {
+			s.labels = map[label]*LabelStats{}
+		}
+		ls, ok := s.labels[label{name, value}]
+		if !ok {
+			ls = &LabelStats{}
+			s.labels[label{name, value}] = ls
+		}
+		ls.Requests++
+		switch {
+		case err != nil:
+			ls.Errors++
+		case res == nil:
+			ls.Undefined++
+		}
+	}
+}
+
+// observeLatency adds a query about info that took d to its histogram.
+func (s *Stats) observeLatency(info CallInfo, d time.Duration) {
+	key := latencyKey{provider: info.Source.Provider, predicate: info.Predicate}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latency == nil {
+		s.latency = map[latencyKey]*LatencyHistogram{}
+	}
+	h, ok := s.latency[key]
+	if !ok {
+		h = &LatencyHistogram{Provider: key.provider, Predicate: key.predicate, Bounds: latencyBounds, Counts: make([]int64, len(latencyBounds)+1)}
+		s.latency[key] = h
+	}
+	h.observe(d)
+}
+
+// Cache returns a Cache that counts the hits and misses of cache. The result
+// is an ExpiringCache, DeletingCache, TimedCache and CacheInvalidator, so it
+// can be used with CacheOptions.UndefinedTTL and TTL, CacheReverifier and
+// RegisterCache if cache supports them.
+func (s *Stats) Cache(cache Cache) Cache {
+	return &statsCache{Cache: cache, stats: s}
+}
+
+type statsCache struct {
+	Cache
+	stats *Stats
+}
+
+func (c *statsCache) Get(ctx context.Context, key string) (Answer, bool, error) {
+	a, ok, err := c.Cache.Get(ctx, key)
+	c.count(ok, err)
+	return a, ok, err
+}
+
+// count counts a lookup as a hit or miss.
+func (c *statsCache) count(ok bool, err error) {
+	if ok && err == nil {
+		c.stats.cacheHits.Add(1)
+	} else {
+		c.stats.cacheMisses.Add(1)
+	}
+}
+
+// GetTime implements TimedCache, with the zero time if the wrapped cache
+// does not.
+func (c *statsCache) GetTime(ctx context.Context, key string) (Answer, time.Time, bool, error) {
+	tc, ok := c.Cache.(TimedCache)
+	if !ok {
+		a, ok, err := c.Get(ctx, key)
+		return a, time.Time{}, ok, err
+	}
+	a, stored, ok, err := tc.GetTime(ctx, key)
+	c.count(ok, err)
+	return a, stored, ok, err
+}
+
+// SetTTL implements ExpiringCache, failing with errors.ErrUnsupported if the
+// wrapped cache does not.
+func (c *statsCache) SetTTL(ctx context.Context, key string, answer Answer, ttl time.Duration) error {
+	if ec, ok := c.Cache.(ExpiringCache); ok {
+		return ec.SetTTL(ctx, key, answer, ttl)
+	}
+	return errors.ErrUnsupported
+}
+
+// Delete implements DeletingCache, failing with errors.ErrUnsupported if the
+// wrapped cache does not.
+func (c *statsCache) Delete(ctx context.Context, key string) error {
+	if dc, ok := c.Cache.(DeletingCache); ok {
+		return dc.Delete(ctx, key)
+	}
+	return errors.ErrUnsupported
+}
+
+// InvalidateNumber implements CacheInvalidator, failing with
+// errors.ErrUnsupported if the wrapped cache does not.
+func (c *statsCache) InvalidateNumber(ctx context.Context, n int) error {
+	if ci, ok := c.Cache.(CacheInvalidator); ok {
+		return ci.InvalidateNumber(ctx, n)
+	}
+	return errors.ErrUnsupported
+}
+
+// InvalidatePredicate implements CacheInvalidator, failing with
+// errors.ErrUnsupported if the wrapped cache does not.
+func (c *statsCache) InvalidatePredicate(ctx context.Context, predicate string) error {
+	if ci, ok := c.Cache.(CacheInvalidator); ok {
+		return ci.InvalidatePredicate(ctx, predicate)
+	}
+	return errors.ErrUnsupported
+}
+
+// FlushCache implements CacheInvalidator, failing with errors.ErrUnsupported
+// if the wrapped cache does not.
+func (c *statsCache) FlushCache(ctx context.Context) error {
+	if ci, ok := c.Cache.(CacheInvalidator); ok {
+		return ci.FlushCache(ctx)
+	}
+	return errors.ErrUnsupported
+}
+
+// Publish exports snapshots as the expvar variable name, so they appear on
+// /debug/vars. Like expvar.Publish, it panics if name is already in use.
+func (s *Stats) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return s.Snapshot() }))
+}
+
+// ServeHTTP serves the current snapshot as JSON, for mounting on a debug
+// endpoint.
+func (s *Stats) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.Snapshot())
+}
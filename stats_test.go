@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	stats := &Stats{
+		Usage:            func() Usage { return Usage{Requests: 3} },
+		UsageByPredicate: func() map[string]Usage { return map[string]Usage{"isEven": {Requests: 3}} },
+	}
+	var calls int
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true), answered(false), failed(errors.New("boom")), answered(true)))
+	core.Use(stats.Middleware(), CacheMiddleware(stats.Cache(NewMemoryCache())))
+
+	_, _ = core.IsEven(1) // Miss, answered true and cached
+	_, _ = core.IsEven(1) // Hit
+	_, _ = core.IsEven(2) // Miss, answered false
+	_, _ = core.IsEven(3) // Miss, error
+	got := stats.Snapshot()
+	want := StatsSnapshot{Requests: 4, Errors: 1, CacheHits: 1, CacheMisses: 3, Usage: &Usage{Requests: 3}}
+	if got.Requests != want.Requests || got.Errors != want.Errors || got.CacheHits != want.CacheHits ||
+		got.CacheMisses != want.CacheMisses || got.Usage == nil || *got.Usage != *want.Usage || got.Budget != nil ||
+		got.UsageByPredicate["isEven"] != *want.Usage {
+		t.Errorf("Snapshot() = %+v; want %+v", got, want)
+	}
+}
+
+func TestStats_Labels(t *testing.T) {
+	stats := &Stats{
+		LabelNames:   []string{"team"},
+		UsageByLabel: func(name string) map[string]Usage { return map[string]Usage{"a": {Requests: 2}} },
+	}
+	var calls int
+	ai := newIsEvenAiCore(testPromptTemplates, countingHandler(&calls, answered(true), failed(errors.New("boom")), answered(true)))
+	ai.Use(stats.Middleware())
+
+	teamA := WithLabels(context.Background(), Labels{"team": "a", "request_id": "1"})
+	_, _ = ai.WithContext(teamA).IsEven(1)
+	_, _ = ai.WithContext(teamA).IsEven(2)
+	_, _ = ai.WithContext(WithLabels(context.Background(), Labels{"team": "b"})).IsEven(3)
+	_, _ = ai.IsEven(4)
+
+	got := stats.Snapshot()
+	if got.Requests != 4 || len(got.ByLabel) != 1 {
+		t.Fatalf("Snapshot() = %+v, want 4 requests broken down by team only", got)
+	}
+	a, b := got.ByLabel["team"]["a"], got.ByLabel["team"]["b"]
+	if a.Requests != 2 || a.Errors != 1 || a.Usage == nil || a.Usage.Requests != 2 {
+		t.Errorf("Team a = %+v, want 2 requests, 1 error and usage", a)
+	}
+	if b.Requests != 1 || b.Errors != 0 || b.Usage != nil {
+		t.Errorf("Team b = %+v, want 1 request without usage", b)
+	}
+}
+
+func TestStats_Undefined(t *testing.T) {
+	var stats Stats
+	core := newIsEvenAiCore(testPromptTemplates, func(context.Context, string) (*bool, error) { return nil, nil })
+	core.Use(stats.Middleware())
+	_, _ = core.IsEven(1)
+	if got := stats.Snapshot(); got.Requests != 1 || got.Undefined != 1 {
+		t.Errorf("Expected one undefined request, got %+v", got)
+	}
+}
+
+func TestStats_Latency(t *testing.T) {
+	var stats Stats
+	core := newIsEvenAiCore(testPromptTemplates, countingHandler(new(int), answered(true)))
+	core.Use(stats.Middleware())
+	_, _ = core.IsEven(1)
+	_, _ = core.AreEqual(1, 2)
+	_, _ = core.IsEven(2)
+	got := stats.Snapshot().Latency
+	if len(got) != 2 || got[0].Predicate != "areEqual" || got[0].Count != 1 || got[1].Predicate != "isEven" || got[1].Count != 2 {
+		t.Fatalf("Latency = %+v; want histograms of 1 areEqual and 2 isEven queries", got)
+	}
+
+	info := CallInfo{Predicate: "isOdd", Source: AnswerSource{Provider: "gemini"}}
+	for _, d := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 11 * time.Millisecond, time.Minute} {
+		stats.observeLatency(info, d)
+	}
+	h := stats.Snapshot().Latency[2]
+	if h.Provider != "gemini" || h.Count != 4 || h.Counts[0] != 2 || h.Counts[1] != 1 || h.Counts[len(h.Counts)-1] != 1 {
+		t.Errorf("Histogram = %+v; want 2 queries up to 10ms, 1 up to 25ms and 1 above all bounds", h)
+	}
+	if want := (5*time.Millisecond + 10*time.Millisecond + 11*time.Millisecond + time.Minute) / 4; h.Mean() != want {
+		t.Errorf("Mean() = %v; want %v", h.Mean(), want)
+	}
+}
+
+func TestStats_Publish(t *testing.T) {
+	var stats Stats
+	stats.Publish("isevenai_test")
+	stats.requests.Add(2)
+	var snap StatsSnapshot
+	if err := json.Unmarshal([]byte(expvar.Get("isevenai_test").String()), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Requests != 2 {
+		t.Errorf("Published requests = %d; want 2", snap.Requests)
+	}
+
+	rec := httptest.NewRecorder()
+	stats.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/iseven", nil))
+	if rec.Body.String() != `{"requests":2,"errors":0,"undefined":0,"cache_hits":0,"cache_misses":0}`+"\n" {
+		t.Errorf("Unexpected handler output %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PromptTemplateFile is the serializable form of IsEvenAiCorePromptTemplates,
+// for keeping prompts in configuration files. Templates of one number refer
+// to it as {n}, templates of two numbers to them as {a} and {b}, e.g.
+// "Is {a} greater than {b}?". Empty templates are nil in Templates.
+type PromptTemplateFile struct {
+	Version            string `json:"version,omitempty"`
+	IsEven             string `json:"isEven"`
+	IsOdd              string `json:"isOdd,omitempty"`
+	AreEqual           string `json:"areEqual"`
+	AreNotEqual        string `json:"areNotEqual,omitempty"`
+	IsGreaterThan      string `json:"isGreaterThan"`
+	IsLessThan         string `json:"isLessThan,omitempty"`
+	IsLeapYear         string `json:"isLeapYear,omitempty"`
+	IsPerfectNumber    string `json:"isPerfectNumber,omitempty"`
+	IsHappyNumber      string `json:"isHappyNumber,omitempty"`
+	IsTriangularNumber string `json:"isTriangularNumber,omitempty"`
+	IsFactorOf         string `json:"isFactorOf,omitempty"`
+	SumIsEven          string `json:"sumIsEven,omitempty"`
+	ProductIsEven      string `json:"productIsEven,omitempty"`
+}
+
+// ReadPromptTemplateFile reads a PromptTemplateFile in JSON format from r.
+func ReadPromptTemplateFile(r io.Reader) (PromptTemplateFile, error) {
+	var f PromptTemplateFile
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return PromptTemplateFile{}, fmt.Errorf("failed to read prompt templates: %w", err)
+	}
+	return f, nil
+}
+
+// Write writes f to w in JSON format, for ReadPromptTemplateFile.
+func (f PromptTemplateFile) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Templates returns the templates of f. It fails like
+// IsEvenAiCorePromptTemplates.Validate if a mandatory template is missing,
+// and if a template does not use all its numbers.
+func (f PromptTemplateFile) Templates() (IsEvenAiCorePromptTemplates, error) {
+	t := IsEvenAiCorePromptTemplates{
+		IsEven:             template1(f.IsEven),
+		IsOdd:              template1(f.IsOdd),
+		AreEqual:           template2(f.AreEqual),
+		AreNotEqual:        template2(f.AreNotEqual),
+		IsGreaterThan:      template2(f.IsGreaterThan),
+		IsLessThan:         template2(f.IsLessThan),
+		IsLeapYear:         template1(f.IsLeapYear),
+		IsPerfectNumber:    template1(f.IsPerfectNumber),
+		IsHappyNumber:      template1(f.IsHappyNumber),
+		IsTriangularNumber: template1(f.IsTriangularNumber),
+		IsFactorOf:         template2(f.IsFactorOf),
+		SumIsEven:          template2(f.SumIsEven),
+		ProductIsEven:      template2(f.ProductIsEven),
+		Version:            f.Version,
+	}
+	if err := t.Validate(); err != nil {
+		return IsEvenAiCorePromptTemplates{}, err
+	}
+	for name, text := range map[string]string{"isEven": f.IsEven, "isOdd": f.IsOdd, "isLeapYear": f.IsLeapYear, "isPerfectNumber": f.IsPerfectNumber, "isHappyNumber": f.IsHappyNumber, "isTriangularNumber": f.IsTriangularNumber} {
+		if text != "" && !strings.Contains(text, "{n}") {
+			return IsEvenAiCorePromptTemplates{}, fmt.Errorf("%w: %s template does not contain {n}", ErrInvalidTemplates, name)
+		}
+	}
+	for name, text := range map[string]string{"areEqual": f.AreEqual, "areNotEqual": f.AreNotEqual, "isGreaterThan": f.IsGreaterThan, "isLessThan": f.IsLessThan, "isFactorOf": f.IsFactorOf, "sumIsEven": f.SumIsEven, "productIsEven": f.ProductIsEven} {
+		if text != "" && (!strings.Contains(text, "{a}") || !strings.Contains(text, "{b}")) {
+			return IsEvenAiCorePromptTemplates{}, fmt.Errorf("%w: %s template does not contain {a} and {b}", ErrInvalidTemplates, name)
+		}
+	}
+	return t, nil
+}
+
+// template1 returns a template substituting {n} in text, or nil if text is empty.
+func template1(text string) PromptTemplate1 {
+	if text == "" {
+		return nil
+	}
+	return func(n int) string { return strings.ReplaceAll(text, "{n}", strconv.Itoa(n)) }
+}
+
+// template2 returns a template substituting {a} and {b} in text, or nil if text is empty.
+func template2(text string) PromptTemplate2 {
+	if text == "" {
+		return nil
+	}
+	return func(a, b int) string {
+		return strings.NewReplacer("{a}", strconv.Itoa(a), "{b}", strconv.Itoa(b)).Replace(text)
+	}
+}
+
+// PromptTemplateHistory holds template sets by Version, so that past answers
+// can be reproduced with the prompts that gave them, e.g. during incident
+// review. CallMetadata, AuditRecord and cache keys record the version:
+//
+//	history, err := is_even_ai.LoadPromptTemplateHistory(os.DirFS("prompts"))
+//	templates, err := history.Get(record.TemplateVersion)
+//	ai, err := is_even_ai.NewIsEvenAiGemini(clientOpts, is_even_ai.GeminiModelOptions{Model: record.Model, PromptTemplates: templates})
+type PromptTemplateHistory struct {
+	versions map[string]IsEvenAiCorePromptTemplates
+}
+
+// NewPromptTemplateHistory returns a history of templates, which must all be
+// valid and have different, non-empty versions.
+func NewPromptTemplateHistory(templates ...IsEvenAiCorePromptTemplates) (*PromptTemplateHistory, error) {
+	h := &PromptTemplateHistory{versions: map[string]IsEvenAiCorePromptTemplates{}}
+	for _, t := range templates {
+		if t.Version == "" {
+			return nil, fmt.Errorf("%w: version is empty", ErrInvalidTemplates)
+		}
+		if _, ok := h.versions[t.Version]; ok {
+			return nil, fmt.Errorf("%w: version %q appears twice", ErrInvalidTemplates, t.Version)
+		}
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("version %q: %w", t.Version, err)
+		}
+		h.versions[t.Version] = t
+	}
+	return h, nil
+}
+
+// LoadPromptTemplateHistory reads every PromptTemplateFile with the extension
+// .json in the root of fsys, e.g. a directory kept in version control with
+// one file per version.
+func LoadPromptTemplateHistory(fsys fs.FS) (*PromptTemplateHistory, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	var templates []IsEvenAiCorePromptTemplates
+	for _, name := range names {
+		t, err := loadPromptTemplateFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		templates = append(templates, t)
+	}
+	return NewPromptTemplateHistory(templates...)
+}
+
+// loadPromptTemplateFile reads the templates in the file name of fsys.
+func loadPromptTemplateFile(fsys fs.FS, name string) (IsEvenAiCorePromptTemplates, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return IsEvenAiCorePromptTemplates{}, err
+	}
+	defer f.Close()
+	file, err := ReadPromptTemplateFile(f)
+	if err != nil {
+		return IsEvenAiCorePromptTemplates{}, err
+	}
+	return file.Templates()
+}
+
+// Get returns the templates of version, or an error wrapping
+// ErrUnknownTemplateVersion.
+func (h *PromptTemplateHistory) Get(version string) (IsEvenAiCorePromptTemplates, error) {
+	t, ok := h.versions[version]
+	if !ok {
+		return IsEvenAiCorePromptTemplates{}, fmt.Errorf("%w: %q", ErrUnknownTemplateVersion, version)
+	}
+	return t, nil
+}
+
+// Versions returns the versions in h, sorted.
+func (h *PromptTemplateHistory) Versions() []string {
+	return slices.Sorted(maps.Keys(h.versions))
+}
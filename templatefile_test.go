@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestPromptTemplateFile(t *testing.T) {
+	f := PromptTemplateFile{
+		Version:       "custom-1",
+		IsEven:        "Is {n} divisible by two?",
+		AreEqual:      "Is {a} the same as {b}?",
+		IsGreaterThan: "Is {a} more than {b}?",
+		IsLessThan:    "Is {a} fewer than {b}? {a} < {b}?",
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	read, err := ReadPromptTemplateFile(&buf)
+	if err != nil {
+		t.Fatalf("ReadPromptTemplateFile failed: %v", err)
+	}
+	if read != f {
+		t.Errorf("Read %+v, want %+v", read, f)
+	}
+
+	templates, err := read.Templates()
+	if err != nil {
+		t.Fatalf("Templates failed: %v", err)
+	}
+	if got := templates.IsEven(42); got != "Is 42 divisible by two?" {
+		t.Errorf("IsEven(42) = %q", got)
+	}
+	if got := templates.IsLessThan(1, -2); got != "Is 1 fewer than -2? 1 < -2?" {
+		t.Errorf("IsLessThan(1, -2) = %q", got)
+	}
+	if templates.IsOdd != nil || templates.AreNotEqual != nil {
+		t.Error("Expected nil templates for empty entries")
+	}
+	if templates.Version != "custom-1" {
+		t.Errorf("Version = %q, want custom-1", templates.Version)
+	}
+}
+
+func TestPromptTemplateFile_Invalid(t *testing.T) {
+	for _, f := range []PromptTemplateFile{
+		{AreEqual: "{a} = {b}?", IsGreaterThan: "{a} > {b}?"},
+		{IsEven: "Is it even?", AreEqual: "{a} = {b}?", IsGreaterThan: "{a} > {b}?"},
+		{IsEven: "{n} even?", AreEqual: "{a} = {b}?", IsGreaterThan: "{a} > 0?"},
+	} {
+		if _, err := f.Templates(); !errors.Is(err, ErrInvalidTemplates) {
+			t.Errorf("Templates() of %+v returned %v, want ErrInvalidTemplates", f, err)
+		}
+	}
+	if _, err := ReadPromptTemplateFile(strings.NewReader(`{"isPrime": "{n}?"}`)); err == nil {
+		t.Error("Expected ReadPromptTemplateFile to reject unknown fields")
+	}
+}
+
+func TestNewIsEvenAiGemini_PromptTemplates(t *testing.T) {
+	templates, err := PromptTemplateFile{IsEven: "{n} even?", AreEqual: "{a} = {b}?", IsGreaterThan: "{a} > {b}?", Version: "2"}.Templates()
+	if err != nil {
+		t.Fatalf("Templates failed: %v", err)
+	}
+	var prompt string
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		geminitest.WriteAnswer(w, r, "true")
+	}, GeminiModelOptions{PromptTemplates: templates})
+
+	ctx, md := WithCallMetadata(context.Background())
+	res, err := ai.WithContext(ctx).IsEven(4)
+	checkGeminiResult(t, res, err, true, "IsEven", 4)
+	if !strings.Contains(prompt, `"4 even?"`) {
+		t.Errorf("Expected the custom prompt to be sent, got %s", prompt)
+	}
+	if md.TemplateVersion != "2" {
+		t.Errorf("CallMetadata.TemplateVersion = %q, want 2", md.TemplateVersion)
+	}
+
+	_, err = NewIsEvenAiGemini(GeminiClientOptions{APIKey: "fake-api-key"}, GeminiModelOptions{PromptTemplates: IsEvenAiCorePromptTemplates{IsEven: templates.IsEven}})
+	if !errors.Is(err, ErrInvalidTemplates) {
+		t.Errorf("Expected ErrInvalidTemplates for incomplete templates, got %v", err)
+	}
+}
+
+func TestLoadPromptTemplateHistory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"v1.json":   {Data: []byte(`{"version": "1", "isEven": "Is {n} even?", "areEqual": "{a} = {b}?", "isGreaterThan": "{a} > {b}?"}`)},
+		"v2.json":   {Data: []byte(`{"version": "2", "isEven": "{n} even?", "areEqual": "{a} = {b}?", "isGreaterThan": "{a} > {b}?"}`)},
+		"README.md": {Data: []byte("Prompt history")},
+	}
+	history, err := LoadPromptTemplateHistory(fsys)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplateHistory failed: %v", err)
+	}
+	if got := history.Versions(); !slices.Equal(got, []string{"1", "2"}) {
+		t.Errorf("Versions() = %v, want [1 2]", got)
+	}
+	templates, err := history.Get("1")
+	if err != nil || templates.Version != "1" || templates.IsEven(4) != "Is 4 even?" {
+		t.Errorf("Get(1) = %+v, %v", templates, err)
+	}
+	if _, err := history.Get("3"); !errors.Is(err, ErrUnknownTemplateVersion) {
+		t.Errorf("Get(3) = %v, want ErrUnknownTemplateVersion", err)
+	}
+
+	fsys["copy.json"] = fsys["v2.json"]
+	if _, err := LoadPromptTemplateHistory(fsys); !errors.Is(err, ErrInvalidTemplates) {
+		t.Errorf("Expected ErrInvalidTemplates for a duplicate version, got %v", err)
+	}
+	if _, err := NewPromptTemplateHistory(DefaultGeminiPromptTemplates, IsEvenAiCorePromptTemplates{IsEven: templates.IsEven}); !errors.Is(err, ErrInvalidTemplates) {
+		t.Errorf("Expected ErrInvalidTemplates for templates without a version, got %v", err)
+	}
+}
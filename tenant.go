@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/philwo/is-even-ai/core"
+	"golang.org/x/time/rate"
+)
+
+// TenantConfig configures one tenant of Tenants.
+type TenantConfig struct {
+	// APIKeys identify the tenant's requests to services like isevend, see
+	// Tenants.Authenticate.
+	APIKeys []string
+	// RateLimit is the number of queries per second the tenant may make, with
+	// bursts of up to Burst queries. Queries over the limit fail with
+	// ErrTenantRateLimited. Optional: zero is unlimited.
+	RateLimit rate.Limit
+	Burst     int
+	// Budget caps the tenant's spending, as reported by the provider to
+	// CallMetadata. Once it is used up, the tenant's queries fail with
+	// ErrBudgetExceeded.
+	Budget Budget
+	// Cache, if set, holds the tenant's answers apart from those of other
+	// tenants. Cached answers don't count against the rate limit or budget.
+	Cache Cache
+}
+
+// TenantStatus reports the usage of a tenant.
+type TenantStatus struct {
+	Usage            Usage            `json:"usage"`
+	UsageByPredicate map[string]Usage `json:"usage_by_predicate,omitempty"`
+	Budget           BudgetStatus     `json:"budget"`
+}
+
+// Tenants shares one client between several tenants, each with its own rate
+// limit, budget and cache. Callers name the tenant of each query with
+// WithTenant, and queries without a known tenant fail with ErrUnknownTenant:
+//
+//	tenants := is_even_ai.NewTenants(map[string]is_even_ai.TenantConfig{
+//		"team-a": {RateLimit: 5, Burst: 10, Budget: is_even_ai.Budget{MaxCostUSD: 1}},
+//		"team-b": {Cache: is_even_ai.NewMemoryCache()},
+//	})
+//	ai.Use(tenants.Middleware())
+//	even, err := ai.WithContext(is_even_ai.WithTenant(ctx, "team-a")).IsEven(2)
+//
+// The client's own limits, like GeminiModelOptions.Budget, still apply to all
+// tenants together. Tenants is safe for concurrent use.
+type Tenants struct {
+	tenants map[string]*tenant
+	keys    map[string]string // API key to tenant ID
+}
+
+// tenant is the state of one configured tenant.
+type tenant struct {
+	config  TenantConfig
+	limiter *rate.Limiter
+	usage   *usageTracker
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose queries are made on behalf of the tenant
+// with the given ID.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFromContext returns the tenant ID set with WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
+
+// NewTenants returns Tenants with the given configurations by tenant ID. It
+// panics if two tenants share an API key.
+func NewTenants(configs map[string]TenantConfig) *Tenants {
+	t := &Tenants{tenants: map[string]*tenant{}, keys: map[string]string{}}
+	for id, config := range configs {
+		limiter := rate.NewLimiter(rate.Inf, 0)
+		if config.RateLimit > 0 {
+			limiter = rate.NewLimiter(config.RateLimit, max(config.Burst, 1))
+		}
+		t.tenants[id] = &tenant{
+			config:  config,
+			limiter: limiter,
+			usage:   newUsageTracker(nil, config.Budget),
+		}
+		for _, key := range config.APIKeys {
+			if other, ok := t.keys[key]; ok {
+				panic(fmt.Sprintf("is_even_ai: tenants %q and %q share an API key", other, id))
+			}
+			t.keys[key] = id
+		}
+	}
+	return t
+}
+
+// IDs returns the IDs of the tenants in sorted order.
+func (t *Tenants) IDs() []string {
+	return slices.Sorted(maps.Keys(t.tenants))
+}
+
+// Authenticate returns the ID of the tenant with the given API key.
+func (t *Tenants) Authenticate(apiKey string) (string, bool) {
+	for key, id := range t.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// Status returns the usage of the tenant with the given ID.
+func (t *Tenants) Status(id string) (TenantStatus, bool) {
+	tn, ok := t.tenants[id]
+	if !ok {
+		return TenantStatus{}, false
+	}
+	return TenantStatus{
+		Usage:            tn.usage.snapshot(),
+		UsageByPredicate: tn.usage.snapshotByPredicate(),
+		Budget:           tn.usage.budgetStatus(),
+	}, true
+}
+
+// Middleware returns middleware that applies the limits and cache of each
+// query's tenant.
+func (t *Tenants) Middleware() Middleware {
+	return func(next QueryHandler) QueryHandler {
+		handlers := map[string]QueryHandler{}
+		for id, tn := range t.tenants {
+			h := tn.account(next)
+			if tn.config.Cache != nil {
+				h = CacheMiddleware(tn.config.Cache)(h)
+			}
+			handlers[id] = h
+		}
+		return func(ctx context.Context, prompt string) (*bool, error) {
+			id, ok := TenantFromContext(ctx)
+			if !ok {
+				return nil, ErrUnknownTenant
+			}
+			h, ok := handlers[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, id)
+			}
+			return h(ctx, prompt)
+		}
+	}
+}
+
+// account returns a handler that checks the limits of tn before calling next,
+// and adds the usage of next to tn.
+func (tn *tenant) account(next QueryHandler) QueryHandler {
+	return func(ctx context.Context, prompt string) (*bool, error) {
+		if !tn.limiter.Allow() {
+			return nil, ErrTenantRateLimited
+		}
+		if err := tn.usage.checkBudget(); err != nil {
+			return nil, err
+		}
+		md := core.CallMetadataFromContext(ctx)
+		if md == nil {
+			ctx, md = WithCallMetadata(ctx)
+		}
+		before := md.Usage
+		res, err := next(ctx, prompt)
+		var predicate string
+		if info, ok := CallInfoFromContext(ctx); ok {
+			predicate = info.Predicate
+		}
+		tn.usage.add(predicate, LabelsFromContext(ctx), Usage{
+			Requests:         md.Requests - before.Requests,
+			PromptTokens:     md.PromptTokens - before.PromptTokens,
+			CompletionTokens: md.CompletionTokens - before.CompletionTokens,
+			CostUSD:          md.CostUSD - before.CostUSD,
+		})
+		return res, err
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/philwo/is-even-ai/core"
+)
+
+func TestTenants(t *testing.T) {
+	calls := 0
+	ai := newIsEvenAiCore(testPromptTemplates, func(ctx context.Context, _ string) (*bool, error) {
+		calls++
+		core.CallMetadataFromContext(ctx).Record("model", 10, 1, 0.5)
+		return answered(true)()
+	})
+	tenants := NewTenants(map[string]TenantConfig{
+		"limited":  {APIKeys: []string{"key-a"}, RateLimit: 0.001, Burst: 2},
+		"budgeted": {APIKeys: []string{"key-b"}, Budget: Budget{MaxCostUSD: 1}},
+		"cached":   {Cache: NewMemoryCache()},
+	})
+	ai.Use(tenants.Middleware())
+	as := func(id string) *IsEvenAiCore {
+		return ai.WithContext(WithTenant(context.Background(), id))
+	}
+
+	if _, err := ai.IsEven(2); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("IsEven() without a tenant = %v, want ErrUnknownTenant", err)
+	}
+	if _, err := as("other").IsEven(2); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("IsEven() of an unknown tenant = %v, want ErrUnknownTenant", err)
+	}
+
+	for range 2 {
+		res, err := as("limited").IsEven(2)
+		checkGeminiResult(t, res, err, true, "IsEven", 2)
+	}
+	if _, err := as("limited").IsEven(2); !errors.Is(err, ErrTenantRateLimited) || !errors.Is(err, ErrRateLimited) {
+		t.Errorf("IsEven() over the rate limit = %v, want ErrTenantRateLimited", err)
+	}
+
+	for range 2 {
+		res, err := as("budgeted").IsOdd(3)
+		checkGeminiResult(t, res, err, true, "IsOdd", 3)
+	}
+	if _, err := as("budgeted").IsOdd(3); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("IsOdd() over the budget = %v, want ErrBudgetExceeded", err)
+	}
+	status, ok := tenants.Status("budgeted")
+	if !ok {
+		t.Fatal("Status(budgeted) not found")
+	}
+	if status.Usage.Requests != 2 || status.Usage.CostUSD != 1 || status.UsageByPredicate["isOdd"].Requests != 2 {
+		t.Errorf("Status(budgeted) = %+v, want 2 isOdd requests costing $1", status)
+	}
+	if status.Budget.RemainingCostUSD == nil || *status.Budget.RemainingCostUSD != 0 {
+		t.Errorf("Status(budgeted).Budget = %+v, want $0 remaining", status.Budget)
+	}
+
+	calls = 0
+	for range 3 {
+		res, err := as("cached").IsEven(4)
+		checkGeminiResult(t, res, err, true, "IsEven", 4)
+	}
+	if _, err := as("limited").IsEven(4); !errors.Is(err, ErrTenantRateLimited) {
+		t.Errorf("IsEven() of another tenant used the cache: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 query of the cached tenant to reach the provider, got %d", calls)
+	}
+	if status, _ := tenants.Status("cached"); status.Usage.Requests != 1 {
+		t.Errorf("Expected the cached tenant to be charged for 1 request, got %d", status.Usage.Requests)
+	}
+}
+
+func TestTenants_Authenticate(t *testing.T) {
+	tenants := NewTenants(map[string]TenantConfig{
+		"a": {APIKeys: []string{"key-a1", "key-a2"}},
+		"b": {APIKeys: []string{"key-b"}},
+	})
+	for key, want := range map[string]string{"key-a1": "a", "key-a2": "a", "key-b": "b"} {
+		if id, ok := tenants.Authenticate(key); !ok || id != want {
+			t.Errorf("Authenticate(%q) = %q, %v, want %q", key, id, ok, want)
+		}
+	}
+	if id, ok := tenants.Authenticate("key-c"); ok {
+		t.Errorf("Authenticate(key-c) = %q, want no tenant", id)
+	}
+	if ids := tenants.IDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("IDs() = %v, want [a b]", ids)
+	}
+}
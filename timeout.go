@@ -0,0 +1,38 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"time"
+)
+
+// defaultCallTimeout limits each query unless configured otherwise.
+const defaultCallTimeout = 30 * time.Second
+
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a context whose queries (see IsEvenAiCore.WithContext)
+// use timeout instead of the provider's configured call timeout, e.g. a short
+// one on interactive paths or a long one in batch jobs. A non-positive timeout
+// leaves calls limited only by the context's own deadline.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// withCallTimeout derives the context of one call from ctx, limited by the
+// timeout set with WithCallTimeout, or by def if there is none. A deadline of
+// ctx that expires earlier still applies.
+func withCallTimeout(ctx context.Context, def time.Duration) (context.Context, context.CancelFunc) {
+	timeout := def
+	if t, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		timeout = t
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
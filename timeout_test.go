@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestIsEvenAiGemini_Timeout(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		geminitest.WriteAnswer(w, r, "true")
+	}
+	ai := newFakeGemini(t, slow, GeminiModelOptions{Timeout: 20 * time.Millisecond})
+
+	if _, err := ai.IsEven(2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("IsEven() with a 20ms timeout = %v, want context.DeadlineExceeded", err)
+	}
+
+	// A per-call timeout overrides the instance's.
+	res, err := ai.WithContext(WithCallTimeout(context.Background(), 5*time.Second)).IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven with a longer call timeout", 2)
+
+	// An earlier context deadline still applies.
+	ctx, cancel := context.WithTimeout(WithCallTimeout(context.Background(), 5*time.Second), 20*time.Millisecond)
+	defer cancel()
+	if _, err := ai.WithContext(ctx).IsEven(2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("IsEven() with a 20ms context deadline = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	ctx, cancel := withCallTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Hour {
+		t.Errorf("Deadline() = %v, %v; want the default timeout", deadline, ok)
+	}
+
+	ctx, cancel = withCallTimeout(WithCallTimeout(context.Background(), 0), time.Hour)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Deadline() is set despite a call timeout of 0")
+	}
+}
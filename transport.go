@@ -0,0 +1,16 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+//go:build !js && !wasip1
+
+package is_even_ai
+
+import "net/http"
+
+// defaultTransport returns the transport used for API requests when
+// GeminiClientOptions.HTTPTransport is unset, or nil to let the SDK pick one.
+func defaultTransport() http.RoundTripper {
+	return nil
+}
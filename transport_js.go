@@ -0,0 +1,17 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+//go:build js && wasm
+
+package is_even_ai
+
+import "net/http"
+
+// defaultTransport returns http.DefaultTransport, which sends requests with the
+// fetch API in browsers and JavaScript edge runtimes. The SDK's own transport
+// is bypassed, as it configures dialers and HTTP/2 that have no effect there.
+func defaultTransport() http.RoundTripper {
+	return http.DefaultTransport
+}
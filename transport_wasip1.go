@@ -0,0 +1,30 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+//go:build wasip1
+
+package is_even_ai
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errNoNetwork is returned by API requests on WASI, which has no sockets.
+var errNoNetwork = errors.New("wasip1 has no network access; set GeminiClientOptions.HTTPTransport to a host-provided transport")
+
+// defaultTransport returns a transport that fails every request with
+// errNoNetwork, rather than letting the dialer fail with an obscure error.
+// Embedders pass a transport backed by the host's HTTP API instead.
+func defaultTransport() http.RoundTripper {
+	return noNetworkTransport{}
+}
+
+type noNetworkTransport struct{}
+
+// RoundTrip implements http.RoundTripper.
+func (noNetworkTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errNoNetwork
+}
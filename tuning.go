@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTPTransportOptions tunes the connection handling of the default transport
+// for clients making hundreds of queries per second, where Go's defaults of
+// at most two idle connections per host become the bottleneck. Zero values
+// keep the defaults of http.DefaultTransport.
+type HTTPTransportOptions struct {
+	MaxIdleConns        int // Across all hosts
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int // Including connections in use; zero means no limit
+	IdleConnTimeout     time.Duration
+	// DisableHTTP2 restricts the transport to HTTP/1.1, which spreads
+	// concurrent queries over several connections instead of multiplexing
+	// them over one.
+	DisableHTTP2 bool
+}
+
+// tunedTransport returns a copy of base, or of http.DefaultTransport if base
+// is nil, with opts applied. Transports other than *http.Transport, such as
+// the one used on wasip1, are returned unchanged.
+func tunedTransport(base http.RoundTripper, opts HTTPTransportOptions) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+	t = t.Clone()
+	if opts.MaxIdleConns > 0 {
+		t.MaxIdleConns = opts.MaxIdleConns
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = opts.MaxConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.DisableHTTP2 {
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP1(true)
+	}
+	return t
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/philwo/is-even-ai/geminitest"
+)
+
+func TestTunedTransport(t *testing.T) {
+	rt := tunedTransport(nil, HTTPTransportOptions{MaxIdleConnsPerHost: 64, MaxConnsPerHost: 128, IdleConnTimeout: time.Minute, DisableHTTP2: true})
+	tr, ok := rt.(*http.Transport)
+	if !ok {
+		t.Fatalf("tunedTransport() = %T, want *http.Transport", rt)
+	}
+	if tr == http.DefaultTransport {
+		t.Fatal("tunedTransport() modified http.DefaultTransport instead of a copy")
+	}
+	if tr.MaxIdleConnsPerHost != 64 || tr.MaxConnsPerHost != 128 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("tunedTransport() settings = %d, %d, %v", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout)
+	}
+	if tr.MaxIdleConns != http.DefaultTransport.(*http.Transport).MaxIdleConns {
+		t.Errorf("tunedTransport() changed MaxIdleConns to %d although it was not set", tr.MaxIdleConns)
+	}
+	if tr.Protocols == nil || tr.Protocols.HTTP2() || !tr.Protocols.HTTP1() {
+		t.Errorf("tunedTransport() protocols = %v, want HTTP/1 only", tr.Protocols)
+	}
+
+	custom := &apiKeyTransport{base: http.DefaultTransport}
+	if got := tunedTransport(custom, HTTPTransportOptions{MaxConnsPerHost: 1}); got != custom {
+		t.Errorf("tunedTransport() = %v, want a custom transport to be returned as is", got)
+	}
+}
+
+func TestIsEvenAiGemini_TransportOptions(t *testing.T) {
+	baseURL := startFakeGeminiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		geminitest.WriteAnswer(w, r, "true")
+	})
+	ai, err := NewIsEvenAiGemini(GeminiClientOptions{
+		APIKey:    "fake-api-key",
+		BaseURL:   baseURL,
+		Transport: HTTPTransportOptions{MaxConnsPerHost: 4, DisableHTTP2: true},
+	})
+	if err != nil {
+		t.Fatalf("NewIsEvenAiGemini with transport options failed: %v", err)
+	}
+	defer ai.Close()
+	res, err := ai.IsEven(2)
+	checkGeminiResult(t, res, err, true, "IsEven", 2)
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ModelPrice is the price of a model in USD per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Cost returns the price in USD of the given number of prompt and completion tokens.
+func (p ModelPrice) Cost(promptTokens, completionTokens int64) float64 {
+	return (float64(promptTokens)*p.InputPerMillion + float64(completionTokens)*p.OutputPerMillion) / 1e6
+}
+
+// DefaultPriceTable holds the paid-tier list prices of common Gemini models
+// (prompts up to 128k tokens) at the time of writing. Prices change; pass your
+// own table via GeminiModelOptions.PriceTable for accurate accounting.
+var DefaultPriceTable = map[string]ModelPrice{
+	ModelGeminiFlashLite: {InputPerMillion: 0.075, OutputPerMillion: 0.30},
+	ModelGeminiFlash:     {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	ModelGemini15Flash:   {InputPerMillion: 0.075, OutputPerMillion: 0.30},
+	ModelGemini15Flash8B: {InputPerMillion: 0.0375, OutputPerMillion: 0.15},
+	ModelGemini15Pro:     {InputPerMillion: 1.25, OutputPerMillion: 5.00},
+}
+
+// Budget caps how much a client may spend. Zero limits are unlimited.
+// Limits are checked before each call, so the call that crosses a limit still
+// completes and only subsequent calls fail with ErrBudgetExceeded.
+type Budget struct {
+	MaxCostUSD float64
+	MaxTokens  int64
+	// Window, if set, applies the limits per fixed time window (e.g. time.Hour)
+	// instead of over the lifetime of the client.
+	Window time.Duration
+}
+
+// BudgetStatus reports how much of a Budget is used up in the current window.
+// Remaining amounts are nil for unlimited budgets.
+type BudgetStatus struct {
+	Spent            Usage    `json:"spent"`
+	RemainingCostUSD *float64 `json:"remaining_cost_usd,omitempty"`
+	RemainingTokens  *int64   `json:"remaining_tokens,omitempty"`
+}
+
+// usageTracker accumulates Usage across concurrent requests and enforces a Budget.
+type usageTracker struct {
+	mu      sync.Mutex
+	prices  map[string]ModelPrice
+	total   Usage
+	byPred  map[string]*Usage // total by predicate
+	byLabel map[label]*Usage  // total by label
+
+	budget      Budget
+	window      Usage // Usage counted against budget
+	windowStart time.Time
+	now         func() time.Time
+}
+
+func newUsageTracker(prices map[string]ModelPrice, budget Budget) *usageTracker {
+	if prices == nil {
+		prices = DefaultPriceTable
+	}
+	return &usageTracker{prices: prices, budget: budget, now: time.Now}
+}
+
+// checkBudget returns ErrBudgetExceeded if the budget is used up.
+func (t *usageTracker) checkBudget() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollWindow()
+	if t.budget.MaxCostUSD > 0 && t.window.CostUSD >= t.budget.MaxCostUSD {
+		return fmt.Errorf("%w: spent $%.6f of $%.6f", ErrBudgetExceeded, t.window.CostUSD, t.budget.MaxCostUSD)
+	}
+	if t.budget.MaxTokens > 0 && t.window.TotalTokens() >= t.budget.MaxTokens {
+		return fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, t.window.TotalTokens(), t.budget.MaxTokens)
+	}
+	return nil
+}
+
+// rollWindow starts a new budget window once the current one has expired.
+// t.mu must be held.
+func (t *usageTracker) rollWindow() {
+	if t.budget.Window <= 0 {
+		return
+	}
+	if now := t.now(); now.Sub(t.windowStart) >= t.budget.Window {
+		t.window = Usage{}
+		t.windowStart = now
+	}
+}
+
+// label is one label of a query.
+type label struct{ name, value string }
+
+// record adds one request to model about predicate with the given labels and
+// returns its estimated cost.
+func (t *usageTracker) record(model, predicate string, labels Labels, promptTokens, completionTokens int64) float64 {
+	return t.recordDiscounted(model, predicate, labels, promptTokens, completionTokens, 1)
+}
+
+// recordDiscounted is like record for requests billed at factor times the
+// list price, such as batch jobs.
+func (t *usageTracker) recordDiscounted(model, predicate string, labels Labels, promptTokens, completionTokens int64, factor float64) float64 {
+	cost := factor * t.prices[model].Cost(promptTokens, completionTokens)
+	t.add(predicate, labels, Usage{Requests: 1, PromptTokens: promptTokens, CompletionTokens: completionTokens, CostUSD: cost})
+	return cost
+}
+
+// add adds usage about predicate with the given labels whose cost is already
+// known.
+func (t *usageTracker) add(predicate string, labels Labels, usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollWindow()
+	if t.byPred == nil {
+		t.byPred = map[string]*Usage{}
+	}
+	pred, ok := t.byPred[predicate]
+	if !ok {
+		pred = &Usage{}
+		t.byPred[predicate] = pred
+	}
+	totals := []*Usage{&t.total, &t.window, pred}
+	for name, value := range labels {
+		if t.byLabel == nil {
+			t.byLabel = map[label]*Usage{}
+		}
+		l := label{name, value}
+		u, ok := t.byLabel[l]
+		if !ok {
+			u = &Usage{}
+			t.byLabel[l] = u
+		}
+		totals = append(totals, u)
+	}
+	for _, u := range totals {
+		u.Requests += usage.Requests
+		u.PromptTokens += usage.PromptTokens
+		u.CompletionTokens += usage.CompletionTokens
+		u.CostUSD += usage.CostUSD
+	}
+}
+
+// budgetStatus reports the spending in the current budget window.
+func (t *usageTracker) budgetStatus() BudgetStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollWindow()
+	status := BudgetStatus{Spent: t.window}
+	if t.budget.MaxCostUSD > 0 {
+		remaining := max(t.budget.MaxCostUSD-t.window.CostUSD, 0)
+		status.RemainingCostUSD = &remaining
+	}
+	if t.budget.MaxTokens > 0 {
+		remaining := max(t.budget.MaxTokens-t.window.TotalTokens(), 0)
+		status.RemainingTokens = &remaining
+	}
+	return status
+}
+
+func (t *usageTracker) snapshot() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// snapshotByPredicate returns the total usage by predicate.
+func (t *usageTracker) snapshotByPredicate() map[string]Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byPred := make(map[string]Usage, len(t.byPred))
+	for predicate, u := range t.byPred {
+		byPred[predicate] = *u
+	}
+	return byPred
+}
+
+// snapshotByLabel returns the total usage by value of the label name.
+func (t *usageTracker) snapshotByLabel(name string) map[string]Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byValue := map[string]Usage{}
+	for l, u := range t.byLabel {
+		if l.name == name {
+			byValue[l.value] = *u
+		}
+	}
+	return byValue
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestModelPrice_Cost(t *testing.T) {
+	p := ModelPrice{InputPerMillion: 0.5, OutputPerMillion: 2}
+	if got, want := p.Cost(2_000_000, 1_000_000), 3.0; got != want {
+		t.Errorf("Cost() = %g, want %g", got, want)
+	}
+}
+
+func TestUsageTracker_Budget(t *testing.T) {
+	prices := map[string]ModelPrice{"m": {InputPerMillion: 1e6}} // $1 per prompt token
+
+	t.Run("MaxCost", func(t *testing.T) {
+		tr := newUsageTracker(prices, Budget{MaxCostUSD: 2})
+		for i := 0; i < 2; i++ {
+			if err := tr.checkBudget(); err != nil {
+				t.Fatalf("call %d: unexpected error %v", i, err)
+			}
+			tr.record("m", "isEven", nil, 1, 0)
+		}
+		if err := tr.checkBudget(); !errors.Is(err, ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded after spending the budget, got %v", err)
+		}
+	})
+
+	t.Run("MaxTokens", func(t *testing.T) {
+		tr := newUsageTracker(prices, Budget{MaxTokens: 10})
+		tr.record("unpriced", "isEven", nil, 8, 1)
+		if err := tr.checkBudget(); err != nil {
+			t.Fatalf("Unexpected error below the token limit: %v", err)
+		}
+		tr.record("unpriced", "isEven", nil, 1, 0)
+		if err := tr.checkBudget(); !errors.Is(err, ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded at the token limit, got %v", err)
+		}
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		tr := newUsageTracker(prices, Budget{MaxCostUSD: 1, Window: time.Hour})
+		tr.now = func() time.Time { return now }
+		tr.record("m", "isEven", nil, 1, 0)
+		if err := tr.checkBudget(); !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected ErrBudgetExceeded within the window, got %v", err)
+		}
+		now = now.Add(time.Hour)
+		if err := tr.checkBudget(); err != nil {
+			t.Errorf("Expected budget to be available in the next window, got %v", err)
+		}
+		if u := tr.snapshot(); u.CostUSD != 1 {
+			t.Errorf("Window rollover should not reset cumulative usage, got %+v", u)
+		}
+	})
+}
+
+func TestUsageTracker_BudgetStatus(t *testing.T) {
+	prices := map[string]ModelPrice{"m": {InputPerMillion: 1e6}} // $1 per prompt token
+
+	tr := newUsageTracker(prices, Budget{})
+	tr.record("m", "isEven", nil, 1, 0)
+	if s := tr.budgetStatus(); s.RemainingCostUSD != nil || s.RemainingTokens != nil || s.Spent.CostUSD != 1 {
+		t.Errorf("Unexpected status for unlimited budget: %+v", s)
+	}
+
+	tr = newUsageTracker(prices, Budget{MaxCostUSD: 2, MaxTokens: 10})
+	tr.record("m", "isEven", nil, 3, 1)
+	s := tr.budgetStatus()
+	if s.RemainingCostUSD == nil || *s.RemainingCostUSD != 0 {
+		t.Errorf("Expected no remaining cost after overspending, got %v", s.RemainingCostUSD)
+	}
+	if s.RemainingTokens == nil || *s.RemainingTokens != 6 {
+		t.Errorf("Expected 6 remaining tokens, got %v", s.RemainingTokens)
+	}
+}
+
+func TestUsageTracker_ByPredicate(t *testing.T) {
+	prices := map[string]ModelPrice{"m": {InputPerMillion: 1e6}} // $1 per prompt token
+
+	tr := newUsageTracker(prices, Budget{})
+	tr.record("m", "isEven", nil, 1, 1)
+	tr.record("m", "areEqual", nil, 2, 1)
+	tr.recordDiscounted("m", "areEqual", nil, 2, 1, 0.5)
+	got := tr.snapshotByPredicate()
+	want := map[string]Usage{
+		"isEven":   {Requests: 1, PromptTokens: 1, CompletionTokens: 1, CostUSD: 1},
+		"areEqual": {Requests: 2, PromptTokens: 4, CompletionTokens: 2, CostUSD: 3},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("snapshotByPredicate() = %+v, want %+v", got, want)
+	}
+	if u := tr.snapshot(); u.CostUSD != 4 || u.Requests != 3 {
+		t.Errorf("snapshot() = %+v, want the sum", u)
+	}
+}
+
+func TestUsageTracker_ByLabel(t *testing.T) {
+	prices := map[string]ModelPrice{"m": {InputPerMillion: 1e6}} // $1 per prompt token
+
+	tr := newUsageTracker(prices, Budget{})
+	tr.record("m", "isEven", Labels{"team": "a", "feature": "x"}, 1, 0)
+	tr.record("m", "isEven", Labels{"team": "a"}, 2, 0)
+	tr.record("m", "isEven", Labels{"team": "b"}, 3, 0)
+	tr.record("m", "isEven", nil, 4, 0)
+	got := tr.snapshotByLabel("team")
+	want := map[string]Usage{
+		"a": {Requests: 2, PromptTokens: 3, CostUSD: 3},
+		"b": {Requests: 1, PromptTokens: 3, CostUSD: 3},
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("snapshotByLabel(team) = %+v, want %+v", got, want)
+	}
+	if got := tr.snapshotByLabel("feature"); len(got) != 1 || got["x"].Requests != 1 {
+		t.Errorf("snapshotByLabel(feature) = %+v, want 1 request for x", got)
+	}
+	if u := tr.snapshot(); u.Requests != 4 {
+		t.Errorf("snapshot() = %+v, want all 4 requests", u)
+	}
+}
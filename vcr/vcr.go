@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package vcr records HTTP interactions with an AI provider to a golden file
+// ("cassette") and replays them later, so integration tests can run
+// deterministically and without API keys.
+//
+// Use a Recorder as the transport of a client, e.g. via
+// is_even_ai.GeminiClientOptions.HTTPTransport:
+//
+//	rec, err := vcr.New("testdata/integration.json", vcr.ModeReplay)
+//	...
+//	ai, err := is_even_ai.NewIsEvenAiGemini(is_even_ai.GeminiClientOptions{
+//		APIKey:        "unused-in-replay",
+//		HTTPTransport: rec,
+//	})
+//
+// Request headers are never recorded and the "key" query parameter is removed
+// from recorded URLs, so cassettes do not contain credentials. JSON request
+// bodies are recorded and matched without insignificant whitespace.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Mode selects whether a Recorder talks to the real API or to its cassette.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails requests that
+	// were not recorded. No network access happens.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real API and records the
+	// interactions; Save writes them to the cassette.
+	ModeRecord
+)
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an HTTP request.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+// It is safe for concurrent use. In replay mode, identical requests are
+// answered in the order in which they were recorded.
+type Recorder struct {
+	path string
+	mode Mode
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool // Replay: interactions already served
+}
+
+// New returns a Recorder for the cassette at path. In replay mode the cassette
+// must exist; in record mode it is created or overwritten by Save.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, base: http.DefaultTransport}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recReq := Request{Method: req.Method, URL: redactURL(req.URL)}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recReq.Body = compactJSON(body)
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+	return r.record(req, recReq)
+}
+
+func (r *Recorder) replay(req *http.Request, recReq Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if !r.used[i] && in.Request == recReq {
+			r.used[i] = true
+			return newResponse(req, in.Response), nil
+		}
+	}
+	return nil, fmt.Errorf("vcr: no recorded interaction for %s %s", recReq.Method, recReq.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recReq Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recReq,
+		Response: Response{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(body),
+		},
+	})
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette. It is a no-op in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Unused returns an error listing recorded interactions that were never
+// replayed, which usually means the cassette is stale.
+func (r *Recorder) Unused() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for i, used := range r.used {
+		if !used {
+			in := r.interactions[i].Request
+			errs = append(errs, fmt.Errorf("vcr: unused interaction %s %s", in.Method, in.URL))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compactJSON returns body without insignificant whitespace if it is JSON,
+// and as is otherwise. The SDK encodes requests with protojson, which adds
+// whitespace at random per build, so bodies are compared in compact form.
+func compactJSON(body []byte) string {
+	var buf bytes.Buffer
+	if json.Compact(&buf, body) != nil {
+		return string(body)
+	}
+	return buf.String()
+}
+
+// redactURL returns u without the "key" query parameter.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	q := redacted.Query()
+	q.Del("key")
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+func newResponse(req *http.Request, recResp Response) *http.Response {
+	header := make(http.Header)
+	if recResp.ContentType != "" {
+		header.Set("Content-Type", recResp.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recResp.StatusCode, http.StatusText(recResp.StatusCode)),
+		StatusCode:    recResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(recResp.Body))),
+		ContentLength: int64(len(recResp.Body)),
+		Request:       req,
+	}
+}
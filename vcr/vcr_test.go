@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"echo":"`+string(body)+`"}`)
+	}))
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	url := srv.URL + "/v1beta/models/m:generateContent?key=s3cr3t"
+
+	rec, err := New(cassette, ModeRecord)
+	if err != nil {
+		t.Fatalf("New(ModeRecord) failed: %v", err)
+	}
+	client := &http.Client{Transport: rec}
+	for _, body := range []string{"one", "two"} {
+		if got := post(t, client, url, body); got != `{"echo":"`+body+`"}` {
+			t.Fatalf("Recording returned unexpected body %q", got)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("Cassette contains the API key:\n%s", data)
+	}
+
+	rep, err := New(cassette, ModeReplay)
+	if err != nil {
+		t.Fatalf("New(ModeReplay) failed: %v", err)
+	}
+	client = &http.Client{Transport: rep}
+	if got := post(t, client, url, "two"); got != `{"echo":"two"}` {
+		t.Errorf("Replay returned %q, want the recorded response", got)
+	}
+	if err := rep.Unused(); err == nil {
+		t.Error("Expected Unused to report the interaction that was not replayed")
+	}
+	if _, err := client.Post(url, "text/plain", strings.NewReader("two")); err == nil {
+		t.Error("Expected an error when replaying a request more often than recorded")
+	}
+	if got := post(t, client, url, "one"); got != `{"echo":"one"}` {
+		t.Errorf("Replay returned %q, want the recorded response", got)
+	}
+	if err := rep.Unused(); err != nil {
+		t.Errorf("Unused() = %v after replaying everything", err)
+	}
+}
+
+func TestRecorder_ReplayIgnoresJSONWhitespace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "recorded")
+	}))
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(cassette, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post(t, &http.Client{Transport: rec}, srv.URL, `{"a":1,"b":[2,3]}`)
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := New(cassette, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := post(t, &http.Client{Transport: rep}, srv.URL, `{"a":1, "b": [2,  3]}`); got != "recorded" {
+		t.Errorf("Replay returned %q, want the response recorded for the same JSON", got)
+	}
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("Expected error for a missing cassette in replay mode")
+	}
+}
+
+func post(t *testing.T, client *http.Client, url, body string) string {
+	t.Helper()
+	resp, err := client.Post(url, "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(got)
+}
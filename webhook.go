@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BatchJobEvent reports a batch job that is done, successfully or not.
+type BatchJobEvent struct {
+	Job     string // GeminiBatchJob.Name
+	State   BatchJobState
+	Err     error // Why the job failed, if it did
+	Results []BatchResult
+	Time    time.Time // When the job was seen to be done
+}
+
+// MarshalJSON encodes the event as the payload sent by Webhook. Results have
+// the fields of NewJSONLResultWriter.
+func (e BatchJobEvent) MarshalJSON() ([]byte, error) {
+	payload := struct {
+		Job     string         `json:"job"`
+		State   BatchJobState  `json:"state"`
+		Error   string         `json:"error,omitempty"`
+		Time    time.Time      `json:"time"`
+		Results []resultRecord `json:"results"`
+	}{Job: e.Job, State: e.State, Time: e.Time, Results: []resultRecord{}}
+	if e.Err != nil {
+		payload.Error = e.Err.Error()
+	}
+	for _, res := range e.Results {
+		payload.Results = append(payload.Results, newResultRecord(res))
+	}
+	return json.Marshal(payload)
+}
+
+// Notify waits for the job like Wait and then passes it to notify, e.g. a
+// Webhook's Send, returning its error. It returns without calling notify if
+// ctx is done or the status of the job cannot be fetched before the job is
+// done. Run it in the background to be called back once the job completes:
+//
+//	go func() {
+//		if err := job.Notify(ctx, time.Minute, hook.Send); err != nil {
+//			log.Printf("batch job %s: %v", job.Name, err)
+//		}
+//	}()
+func (j *GeminiBatchJob) Notify(ctx context.Context, interval time.Duration, notify func(context.Context, BatchJobEvent) error) error {
+	results, err := j.Wait(ctx, interval)
+	j.mu.Lock()
+	state := j.state
+	j.mu.Unlock()
+	if !state.Done() {
+		return err
+	}
+	return notify(ctx, BatchJobEvent{Job: j.Name, State: state, Err: err, Results: results, Time: time.Now()})
+}
+
+// Webhook posts BatchJobEvents as JSON to URL, e.g. to resume a workflow once
+// a batch job is done. Requests are signed with Secret: the header
+// X-IsEvenAi-Timestamp holds the Unix time of sending, and
+// X-IsEvenAi-Signature is "v1=" followed by the hex encoded HMAC-SHA256 of
+// "v1:<timestamp>:<body>". Receivers check both with VerifyWebhook.
+type Webhook struct {
+	URL    string
+	Secret string
+	// Client sends the requests. Optional: defaults to http.DefaultClient.
+	Client *http.Client
+
+	now func() time.Time // For tests
+}
+
+// Send posts event to the webhook. Responses other than 2xx are errors.
+func (h *Webhook) Send(ctx context.Context, event BatchJobEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if h.now != nil {
+		now = h.now
+	}
+	ts := strconv.FormatInt(now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-IsEvenAi-Timestamp", ts)
+	req.Header.Set("X-IsEvenAi-Signature", "v1="+hex.EncodeToString(signWebhook(h.Secret, ts, body)))
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", withoutQuery(h.URL), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: %s", withoutQuery(h.URL), resp.Status)
+	}
+	return nil
+}
+
+// VerifyWebhook checks that a request with header and body was signed by a
+// Webhook with secret at most maxAge ago, and returns an error wrapping
+// ErrInvalidSignature if not.
+func VerifyWebhook(secret string, header http.Header, body []byte, maxAge time.Duration) error {
+	ts := header.Get("X-IsEvenAi-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp %q", ErrInvalidSignature, ts)
+	}
+	if age := time.Since(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+		return fmt.Errorf("%w: signed %v ago", ErrInvalidSignature, age.Round(time.Second))
+	}
+	sig, ok := strings.CutPrefix(header.Get("X-IsEvenAi-Signature"), "v1=")
+	if !ok {
+		return fmt.Errorf("%w: no v1 signature", ErrInvalidSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, signWebhook(secret, ts, body)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// signWebhook computes the version 1 signature of a webhook request.
+func signWebhook(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v1:%s:", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package is_even_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeminiBatchJob_Notify(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"batches/42","done":true,"metadata":{"state":"BATCH_STATE_SUCCEEDED"},
+			"response":{"inlinedResponses":{"inlinedResponses":[
+				{"metadata":{"index":"0","n":"2"},"response":{"candidates":[{"content":{"parts":[{"text":"true"}]}}]}}
+			]}}}`)
+	})
+
+	var payload struct {
+		Job     string `json:"job"`
+		State   string `json:"state"`
+		Results []struct {
+			N      int   `json:"n"`
+			Answer *bool `json:"answer"`
+		} `json:"results"`
+	}
+	var verifyErr error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = VerifyWebhook("s3cret", r.Header, body, time.Minute)
+		_ = json.Unmarshal(body, &payload)
+	}))
+	defer srv.Close()
+
+	hook := &Webhook{URL: srv.URL, Secret: "s3cret"}
+	if err := ai.BatchJob("batches/42").Notify(context.Background(), 0, hook.Send); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if verifyErr != nil {
+		t.Errorf("VerifyWebhook() on the sent request failed: %v", verifyErr)
+	}
+	if payload.Job != "batches/42" || payload.State != "SUCCEEDED" || len(payload.Results) != 1 || payload.Results[0].N != 2 ||
+		payload.Results[0].Answer == nil || !*payload.Results[0].Answer {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusServiceUnavailable)
+	})
+	if err := hook.Send(context.Background(), BatchJobEvent{Job: "batches/42"}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Send() to a failing webhook = %v, want a 503 error", err)
+	}
+}
+
+func TestGeminiBatchJob_NotifyCanceled(t *testing.T) {
+	ai := newFakeGemini(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"batches/42","metadata":{"state":"BATCH_STATE_RUNNING"}}`)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := ai.BatchJob("batches/42").Notify(ctx, 0, func(context.Context, BatchJobEvent) error {
+		t.Error("notify called for an unfinished job")
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Notify() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"job":"batches/1"}`)
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { header = r.Header }))
+	defer srv.Close()
+	sign := func(secret string, at time.Time) http.Header {
+		h := &Webhook{URL: srv.URL, Secret: secret, now: func() time.Time { return at }}
+		if err := h.Send(context.Background(), BatchJobEvent{Job: "batches/1"}); err != nil {
+			t.Fatal(err)
+		}
+		return header
+	}
+	now := time.Now()
+	if err := VerifyWebhook("s3cret", sign("s3cret", now), body, time.Minute); err == nil {
+		t.Error("VerifyWebhook() accepted a different body")
+	}
+	body, _ = json.Marshal(BatchJobEvent{Job: "batches/1"})
+	tests := []struct {
+		name   string
+		header http.Header
+		valid  bool
+	}{
+		{"valid", sign("s3cret", now), true},
+		{"wrong secret", sign("other", now), false},
+		{"too old", sign("s3cret", now.Add(-time.Hour)), false},
+		{"unsigned", http.Header{}, false},
+	}
+	for _, tc := range tests {
+		err := VerifyWebhook("s3cret", tc.header, body, time.Minute)
+		if tc.valid && err != nil || !tc.valid && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: VerifyWebhook() = %v", tc.name, err)
+		}
+	}
+}